@@ -0,0 +1,131 @@
+package algo_test
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"L1.16/algo"
+)
+
+// inputs набор входов, на которых наивный quicksort деградирует
+func inputs(n int) map[string][]int {
+	r := rand.New(rand.NewSource(1))
+	random := make([]int, n)
+	dups := make([]int, n)
+	sorted := make([]int, n)
+	reversed := make([]int, n)
+	organ := make([]int, n)
+	for i := range n {
+		random[i] = r.Int() - math.MaxInt/2
+		dups[i] = r.Intn(4)
+		sorted[i] = i
+		reversed[i] = n - i
+		organ[i] = min(i, n-i)
+	}
+	return map[string][]int{
+		"случайные":      random,
+		"дубликаты":      dups,
+		"по возрастанию": sorted,
+		"по убыванию":    reversed,
+		"горка":          organ,
+	}
+}
+
+func TestSorts(t *testing.T) {
+	sorts := map[string]func([]int){
+		"IntroSort":    algo.IntroSort[int],
+		"HeapSort":     algo.HeapSort[int],
+		"MergeSort":    algo.MergeSort[int],
+		"RadixSort":    algo.RadixSort[int],
+		"ParallelSort": algo.ParallelSort[int],
+	}
+	for _, n := range []int{0, 1, 2, 17, 1000, 100_000} {
+		for inName, in := range inputs(n) {
+			want := slices.Clone(in)
+			slices.Sort(want)
+			for name, sort := range sorts {
+				got := slices.Clone(in)
+				sort(got)
+				if !slices.Equal(got, want) {
+					t.Errorf("%s, %s, n=%d: результат не отсортирован", name, inName, n)
+				}
+			}
+		}
+	}
+}
+
+func TestRadixSortTypes(t *testing.T) {
+	i8 := []int8{5, -128, 127, 0, -1, 3, -7}
+	algo.RadixSort(i8)
+	if !slices.IsSorted(i8) {
+		t.Errorf("int8: ожидали отсортированный срез, получили: %v", i8)
+	}
+
+	u := []uint64{math.MaxUint64, 0, 1 << 63, 42, 1<<63 - 1}
+	algo.RadixSort(u)
+	if !slices.IsSorted(u) {
+		t.Errorf("uint64: ожидали отсортированный срез, получили: %v", u)
+	}
+}
+
+type item struct {
+	key, order int
+}
+
+func TestStability(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	in := make([]item, 5000)
+	for i := range in {
+		in[i] = item{key: r.Intn(10), order: i}
+	}
+	byKey := func(a, b item) int { return cmp.Compare(a.key, b.key) }
+	want := slices.Clone(in)
+	slices.SortStableFunc(want, byKey)
+
+	got := slices.Clone(in)
+	algo.MergeSortFunc(got, byKey)
+	if !slices.Equal(got, want) {
+		t.Error("MergeSortFunc: порядок равных элементов нарушен")
+	}
+
+	got = slices.Clone(in)
+	algo.RadixSortBy(got, 1, func(it item) uint64 { return uint64(it.key) })
+	if !slices.Equal(got, want) {
+		t.Error("RadixSortBy: порядок равных элементов нарушен")
+	}
+}
+
+func TestBounds(t *testing.T) {
+	s := []int{1, 2, 2, 2, 5, 7}
+	tests := []struct {
+		target, lower, upper int
+	}{
+		{0, 0, 0},
+		{1, 0, 1},
+		{2, 1, 4},
+		{3, 4, 4},
+		{7, 5, 6},
+		{8, 6, 6},
+	}
+	for _, tt := range tests {
+		if got := algo.LowerBound(s, tt.target); got != tt.lower {
+			t.Errorf("LowerBound(%d): ожидали: %d получили: %d", tt.target, tt.lower, got)
+		}
+		if got := algo.UpperBound(s, tt.target); got != tt.upper {
+			t.Errorf("UpperBound(%d): ожидали: %d получили: %d", tt.target, tt.upper, got)
+		}
+	}
+
+	if i, ok := algo.BinarySearch(s, 5); !ok || i != 4 {
+		t.Errorf("BinarySearch(5): ожидали: 4 true получили: %d %v", i, ok)
+	}
+	if i, ok := algo.BinarySearch(s, 3); ok || i != 4 {
+		t.Errorf("BinarySearch(3): ожидали: 4 false получили: %d %v", i, ok)
+	}
+	if i, ok := algo.BinarySearch([]int(nil), 3); ok || i != 0 {
+		t.Errorf("BinarySearch на пустом срезе: ожидали: 0 false получили: %d %v", i, ok)
+	}
+}
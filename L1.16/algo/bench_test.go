@@ -0,0 +1,75 @@
+package algo_test
+
+import (
+	"slices"
+	"testing"
+
+	"L1.16/algo"
+)
+
+// quicksort исходная реализация из L1.16 для сравнения
+func quicksort(array []int, left, right int) {
+	if left >= right {
+		return
+	}
+	pivot := array[(left+right)/2]
+	i, j := left, right
+	for i <= j {
+		for array[i] < pivot && i <= right {
+			i++
+		}
+		for array[j] > pivot && i >= left {
+			j--
+		}
+		if i <= j {
+			array[i], array[j] = array[j], array[i]
+			i++
+			j--
+		}
+	}
+	quicksort(array, left, j)
+	quicksort(array, i, right)
+}
+
+func BenchmarkSort(b *testing.B) {
+	sorts := []struct {
+		name string
+		sort func([]int)
+	}{
+		{"slices.Sort", slices.Sort[[]int]},
+		{"quicksort", func(s []int) { quicksort(s, 0, len(s)-1) }},
+		{"IntroSort", algo.IntroSort[int]},
+		{"HeapSort", algo.HeapSort[int]},
+		{"MergeSort", algo.MergeSort[int]},
+		{"RadixSort", algo.RadixSort[int]},
+		{"ParallelSort", algo.ParallelSort[int]},
+	}
+	for inName, in := range inputs(1 << 16) {
+		for _, s := range sorts {
+			b.Run(inName+"/"+s.name, func(b *testing.B) {
+				buf := make([]int, len(in))
+				for b.Loop() {
+					copy(buf, in)
+					s.sort(buf)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkLowerBound(b *testing.B) {
+	s := make([]int, 1<<20)
+	for i := range s {
+		s[i] = 2 * i
+	}
+	b.Run("LowerBound", func(b *testing.B) {
+		for i := 0; b.Loop(); i++ {
+			algo.LowerBound(s, i&(1<<21-1))
+		}
+	})
+	b.Run("slices.BinarySearch", func(b *testing.B) {
+		for i := 0; b.Loop(); i++ {
+			_, _ = slices.BinarySearch(s, i&(1<<21-1))
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package algo
+
+import (
+	"cmp"
+	"runtime"
+	"sync"
+)
+
+// parallelThreshold меньшие срезы сортируются в одной горутине
+const parallelThreshold = 1 << 14
+
+// ParallelSort сортирует срез, деля его на части по числу CPU:
+// части сортируются IntroSort параллельно, затем попарно сливаются, тоже параллельно.
+// Использует O(n) доп. памяти, сортировка нестабильная
+func ParallelSort[T cmp.Ordered](s []T) {
+	ParallelSortFunc(s, cmp.Compare[T])
+}
+
+// ParallelSortFunc как ParallelSort, но с функцией сравнения
+func ParallelSortFunc[T any](s []T, compare func(a, b T) int) {
+	workers := runtime.GOMAXPROCS(0)
+	if len(s) < parallelThreshold || workers < 2 {
+		IntroSortFunc(s, compare)
+		return
+	}
+
+	chunk := (len(s) + workers - 1) / workers
+	bounds := make([]int, 0, workers+1)
+	for lo := 0; lo < len(s); lo += chunk {
+		bounds = append(bounds, lo)
+	}
+	bounds = append(bounds, len(s))
+
+	var wg sync.WaitGroup
+	for i := 0; i+1 < len(bounds); i++ {
+		wg.Add(1)
+		go func(part []T) {
+			defer wg.Done()
+			IntroSortFunc(part, compare)
+		}(s[bounds[i]:bounds[i+1]])
+	}
+	wg.Wait()
+
+	buf := make([]T, len(s))
+	src, dst := s, buf
+	for len(bounds) > 2 {
+		next := make([]int, 0, len(bounds)/2+1)
+		for i := 0; i+1 < len(bounds); i += 2 {
+			lo := bounds[i]
+			next = append(next, lo)
+			if i+2 >= len(bounds) {
+				// нечётная часть без пары просто переносится
+				copy(dst[lo:], src[lo:bounds[i+1]])
+				continue
+			}
+			mid, hi := bounds[i+1], bounds[i+2]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				merge(dst[lo:hi], src[lo:mid], src[mid:hi], compare)
+			}()
+		}
+		next = append(next, len(s))
+		wg.Wait()
+		bounds = next
+		src, dst = dst, src
+	}
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
@@ -0,0 +1,67 @@
+package algo
+
+import "unsafe"
+
+// Integer целочисленные типы, которые умеет сортировать RadixSort
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// RadixSort поразрядная LSD сортировка целых по байтам: O(n*k), где k - размер типа в байтах.
+// Стабильная, использует O(n) доп. памяти
+func RadixSort[T Integer](s []T) {
+	var zero T
+	size := int(unsafe.Sizeof(zero))
+	signed := ^zero < 0
+	RadixSortBy(s, size, func(v T) uint64 {
+		key := uint64(v)
+		if signed {
+			// инвертируем знаковый бит, чтобы отрицательные шли раньше положительных
+			key ^= 1 << (8*size - 1)
+		}
+		return key
+	})
+}
+
+// RadixSortBy стабильно сортирует элементы по беззнаковому ключу из keyBytes младших байтов
+func RadixSortBy[E any](s []E, keyBytes int, key func(E) uint64) {
+	if len(s) < 2 {
+		return
+	}
+	keys := make([]uint64, len(s))
+	for i, v := range s {
+		keys[i] = key(v)
+	}
+	bufKeys := make([]uint64, len(s))
+	buf := make([]E, len(s))
+
+	src, dst := s, buf
+	srcKeys, dstKeys := keys, bufKeys
+	for b := 0; b < keyBytes; b++ {
+		shift := uint(8 * b)
+		var count [256]int
+		for _, k := range srcKeys {
+			count[(k>>shift)&0xff]++
+		}
+		// все ключи в одной корзине - разряд ничего не меняет
+		if count[(srcKeys[0]>>shift)&0xff] == len(s) {
+			continue
+		}
+		pos := 0
+		for i := range count {
+			pos, count[i] = pos+count[i], pos
+		}
+		for i, k := range srcKeys {
+			d := (k >> shift) & 0xff
+			dst[count[d]] = src[i]
+			dstKeys[count[d]] = k
+			count[d]++
+		}
+		src, dst = dst, src
+		srcKeys, dstKeys = dstKeys, srcKeys
+	}
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
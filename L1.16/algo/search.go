@@ -0,0 +1,50 @@
+package algo
+
+import "cmp"
+
+// LowerBound возвращает индекс первого элемента >= target в отсортированном срезе,
+// либо len(s), если таких нет
+func LowerBound[T cmp.Ordered](s []T, target T) int {
+	return LowerBoundFunc(s, target, cmp.Compare[T])
+}
+
+// LowerBoundFunc как LowerBound, но с функцией сравнения
+func LowerBoundFunc[T, K any](s []T, target K, compare func(elem T, target K) int) int {
+	lo, hi := 0, len(s)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if compare(s[mid], target) < 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// UpperBound возвращает индекс первого элемента > target в отсортированном срезе,
+// либо len(s), если таких нет
+func UpperBound[T cmp.Ordered](s []T, target T) int {
+	return UpperBoundFunc(s, target, cmp.Compare[T])
+}
+
+// UpperBoundFunc как UpperBound, но с функцией сравнения
+func UpperBoundFunc[T, K any](s []T, target K, compare func(elem T, target K) int) int {
+	lo, hi := 0, len(s)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if compare(s[mid], target) <= 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// BinarySearch ищет target в отсортированном срезе итеративно.
+// Возвращает индекс первого вхождения и true, либо позицию для вставки и false
+func BinarySearch[T cmp.Ordered](s []T, target T) (int, bool) {
+	i := LowerBound(s, target)
+	return i, i < len(s) && s[i] == target
+}
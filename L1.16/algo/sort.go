@@ -0,0 +1,191 @@
+package algo
+
+import (
+	"cmp"
+	"math/bits"
+)
+
+// insertionThreshold до этого размера подмассивы досортировываются вставками
+const insertionThreshold = 16
+
+// IntroSort сортирует срез по возрастанию. Это quicksort с медианой из трёх
+// и трёхпутевым разбиением (много одинаковых элементов не ухудшают сложность),
+// который при слишком глубокой рекурсии переключается на heapsort,
+// поэтому худший случай O(n*log(n)). Сортировка нестабильная
+func IntroSort[T cmp.Ordered](s []T) {
+	IntroSortFunc(s, cmp.Compare[T])
+}
+
+// IntroSortFunc как IntroSort, но с функцией сравнения в стиле slices.SortFunc
+func IntroSortFunc[T any](s []T, compare func(a, b T) int) {
+	if len(s) < 2 {
+		return
+	}
+	introSort(s, compare, 2*bits.Len(uint(len(s))))
+}
+
+func introSort[T any](s []T, compare func(a, b T) int, depth int) {
+	for len(s) > insertionThreshold {
+		if depth == 0 {
+			HeapSortFunc(s, compare)
+			return
+		}
+		depth--
+
+		lt, gt := partition3(s, compare)
+		// рекурсия в меньшую часть, цикл по большей: глубина стека O(log(n))
+		if lt < len(s)-gt {
+			introSort(s[:lt], compare, depth)
+			s = s[gt:]
+		} else {
+			introSort(s[gt:], compare, depth)
+			s = s[:lt]
+		}
+	}
+	insertionSort(s, compare)
+}
+
+// partition3 разбивает s на части < pivot, == pivot, > pivot
+// и возвращает границы средней части [lt, gt)
+func partition3[T any](s []T, compare func(a, b T) int) (lt, gt int) {
+	medianOfThree(s, compare)
+	pivot := s[0]
+	lt, i, gt := 0, 1, len(s)
+	for i < gt {
+		c := compare(s[i], pivot)
+		switch {
+		case c < 0:
+			s[lt], s[i] = s[i], s[lt]
+			lt++
+			i++
+		case c > 0:
+			gt--
+			s[i], s[gt] = s[gt], s[i]
+		default:
+			i++
+		}
+	}
+	return lt, gt
+}
+
+// ninthThreshold начиная с этого размера опорный выбирается как медиана медиан (ninther)
+const ninthThreshold = 128
+
+// medianOfThree ставит в s[0] опорный элемент: медиану из первого, среднего и последнего,
+// а для больших срезов - медиану трёх таких медиан, чтобы "горка" и похожие входы
+// не давали плохих разбиений
+func medianOfThree[T any](s []T, compare func(a, b T) int) {
+	n := len(s)
+	a, b, c := 0, n/2, n-1
+	if n >= ninthThreshold {
+		d := n / 8
+		a = median(s, a, a+d, a+2*d, compare)
+		b = median(s, b-d, b, b+d, compare)
+		c = median(s, c-2*d, c-d, c, compare)
+	}
+	m := median(s, a, b, c, compare)
+	s[0], s[m] = s[m], s[0]
+}
+
+// median возвращает индекс медианы из s[a], s[b], s[c]
+func median[T any](s []T, a, b, c int, compare func(a, b T) int) int {
+	if compare(s[b], s[a]) < 0 {
+		a, b = b, a
+	}
+	if compare(s[c], s[b]) < 0 {
+		b = c
+		if compare(s[b], s[a]) < 0 {
+			b = a
+		}
+	}
+	return b
+}
+
+func insertionSort[T any](s []T, compare func(a, b T) int) {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && compare(s[j], s[j-1]) < 0; j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+	}
+}
+
+// HeapSort сортирует срез пирамидой: O(n*log(n)) в худшем случае, без доп. памяти, нестабильная
+func HeapSort[T cmp.Ordered](s []T) {
+	HeapSortFunc(s, cmp.Compare[T])
+}
+
+// HeapSortFunc как HeapSort, но с функцией сравнения
+func HeapSortFunc[T any](s []T, compare func(a, b T) int) {
+	n := len(s)
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(s, i, n, compare)
+	}
+	for end := n - 1; end > 0; end-- {
+		s[0], s[end] = s[end], s[0]
+		siftDown(s, 0, end, compare)
+	}
+}
+
+func siftDown[T any](s []T, root, end int, compare func(a, b T) int) {
+	for {
+		child := 2*root + 1
+		if child >= end {
+			return
+		}
+		if child+1 < end && compare(s[child], s[child+1]) < 0 {
+			child++
+		}
+		if compare(s[root], s[child]) >= 0 {
+			return
+		}
+		s[root], s[child] = s[child], s[root]
+		root = child
+	}
+}
+
+// MergeSort стабильная сортировка слиянием снизу вверх, O(n*log(n)) и O(n) доп. памяти
+func MergeSort[T cmp.Ordered](s []T) {
+	MergeSortFunc(s, cmp.Compare[T])
+}
+
+// MergeSortFunc как MergeSort, но с функцией сравнения. Равные элементы сохраняют порядок
+func MergeSortFunc[T any](s []T, compare func(a, b T) int) {
+	n := len(s)
+	if n < 2 {
+		return
+	}
+	for lo := 0; lo < n; lo += insertionThreshold {
+		insertionSort(s[lo:min(lo+insertionThreshold, n)], compare)
+	}
+
+	buf := make([]T, n)
+	src, dst := s, buf
+	for width := insertionThreshold; width < n; width *= 2 {
+		for lo := 0; lo < n; lo += 2 * width {
+			mid := min(lo+width, n)
+			hi := min(lo+2*width, n)
+			merge(dst[lo:hi], src[lo:mid], src[mid:hi], compare)
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
+
+// merge сливает отсортированные a и b в dst; при равенстве берётся элемент из a
+func merge[T any](dst, a, b []T, compare func(a, b T) int) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if compare(b[j], a[i]) < 0 {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
@@ -0,0 +1,3 @@
+module L1.16
+
+go 1.24.0
@@ -0,0 +1,128 @@
+package concurrent
+
+import (
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// mutexCounter повторяет concurrentCounter из L1.18
+type mutexCounter struct {
+	counter int
+	mu      sync.Mutex
+}
+
+func (c *mutexCounter) inc() {
+	c.mu.Lock()
+	c.counter++
+	c.mu.Unlock()
+}
+
+// rwMutexMap повторяет SyncMap из L1.7
+type rwMutexMap struct {
+	mapa map[int]int
+	mu   sync.RWMutex
+}
+
+func (m *rwMutexMap) load(key int) (int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.mapa[key]
+	return v, ok
+}
+
+func (m *rwMutexMap) store(key, value int) {
+	m.mu.Lock()
+	m.mapa[key] = value
+	m.mu.Unlock()
+}
+
+func BenchmarkCounter_Sharded(b *testing.B) {
+	c := NewCounter()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Inc()
+		}
+	})
+}
+
+func BenchmarkCounter_Atomic(b *testing.B) {
+	var c atomic.Int64
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Add(1)
+		}
+	})
+}
+
+func BenchmarkCounter_Mutex(b *testing.B) {
+	c := &mutexCounter{}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.inc()
+		}
+	})
+}
+
+const benchKeys = 1 << 12
+
+// нагрузка на map: 90% чтений, 10% записей по случайным ключам
+func benchmarkMap(b *testing.B, load func(int) (int, bool), store func(int, int)) {
+	for i := 0; i < benchKeys; i++ {
+		store(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			key := rand.IntN(benchKeys)
+			if rand.IntN(10) == 0 {
+				store(key, key)
+			} else {
+				load(key)
+			}
+		}
+	})
+}
+
+func BenchmarkMap_Sharded(b *testing.B) {
+	m := NewMap[int, int](0)
+	benchmarkMap(b, m.Load, m.Store)
+}
+
+func BenchmarkMap_SyncMap(b *testing.B) {
+	var m sync.Map
+	benchmarkMap(b,
+		func(key int) (int, bool) {
+			v, ok := m.Load(key)
+			if !ok {
+				return 0, false
+			}
+			return v.(int), true
+		},
+		func(key, value int) { m.Store(key, value) },
+	)
+}
+
+func BenchmarkMap_RWMutex(b *testing.B) {
+	m := &rwMutexMap{mapa: make(map[int]int)}
+	benchmarkMap(b, m.load, m.store)
+}
+
+func BenchmarkGauge_Add(b *testing.B) {
+	var g Gauge
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.Add(1)
+		}
+	})
+}
+
+func BenchmarkHistogram_Observe(b *testing.B) {
+	h := NewHistogram(nil)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			h.Observe(rand.Float64())
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package concurrent
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestCounter_Concurrent(t *testing.T) {
+	c := NewCounter()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Inc()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := c.Value(); got != 20000 {
+		t.Errorf("got=%d, want=20000", got)
+	}
+	c.Add(-5)
+	if got := c.Value(); got != 19995 {
+		t.Errorf("got=%d после Add(-5)", got)
+	}
+	c.Reset()
+	if got := c.Value(); got != 0 {
+		t.Errorf("got=%d после Reset", got)
+	}
+}
+
+func TestMap_ConcurrentUpdate(t *testing.T) {
+	m := NewMap[int, int](0)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := 0; key < 100; key++ {
+				m.Update(key, func(old int, _ bool) int { return old + 1 })
+			}
+		}()
+	}
+	wg.Wait()
+
+	if m.Len() != 100 {
+		t.Fatalf("ожидали 100 ключей, получили %d", m.Len())
+	}
+	m.Range(func(key, value int) bool {
+		if value != 10 {
+			t.Errorf("ключ %d: got=%d, want=10", key, value)
+		}
+		return true
+	})
+}
+
+func TestMap_Operations(t *testing.T) {
+	m := NewMap[string, int](3)
+	if len(m.shards) != 4 {
+		t.Errorf("количество шардов должно округляться до степени двойки, получили %d", len(m.shards))
+	}
+
+	if _, ok := m.Load("a"); ok {
+		t.Error("пустая map не должна содержать ключей")
+	}
+	m.Store("a", 1)
+	if v, ok := m.Load("a"); !ok || v != 1 {
+		t.Errorf("Load: got=%d ok=%v", v, ok)
+	}
+	if v, loaded := m.LoadOrStore("a", 2); !loaded || v != 1 {
+		t.Errorf("LoadOrStore существующего: got=%d loaded=%v", v, loaded)
+	}
+	if v, loaded := m.LoadOrStore("b", 2); loaded || v != 2 {
+		t.Errorf("LoadOrStore нового: got=%d loaded=%v", v, loaded)
+	}
+	m.Delete("a")
+	if _, ok := m.Load("a"); ok {
+		t.Error("ключ должен быть удалён")
+	}
+
+	visited := 0
+	m.Store("c", 3)
+	m.Range(func(string, int) bool {
+		visited++
+		return false
+	})
+	if visited != 1 {
+		t.Errorf("Range должен остановиться после false, посещено %d", visited)
+	}
+}
+
+func TestGauge(t *testing.T) {
+	var g Gauge
+	g.Set(1.5)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				g.Add(0.5)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := g.Value(); got != 501.5 {
+		t.Errorf("got=%v, want=501.5", got)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]float64{10, 1, 5})
+	for _, v := range []float64{0.5, 1, 2, 3, 4, 6, 7, 8, 9, 100} {
+		h.Observe(v)
+	}
+	s := h.Snapshot()
+
+	wantCounts := []uint64{2, 3, 4, 1}
+	for i, c := range wantCounts {
+		if s.Counts[i] != c {
+			t.Errorf("корзина %d: got=%d, want=%d", i, s.Counts[i], c)
+		}
+	}
+	if s.Count != 10 || s.Sum != 140.5 {
+		t.Errorf("count=%d sum=%v", s.Count, s.Sum)
+	}
+	if q := s.Quantile(0.5); q != 5 {
+		t.Errorf("медиана: got=%v, want=5", q)
+	}
+	if q := s.Quantile(1); q != 10 {
+		t.Errorf("максимум: got=%v, want=10", q)
+	}
+	if q := NewHistogram(nil).Snapshot().Quantile(0.5); !math.IsNaN(q) {
+		t.Errorf("пустая гистограмма: got=%v, want NaN", q)
+	}
+}
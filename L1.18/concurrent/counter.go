@@ -0,0 +1,63 @@
+package concurrent
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+// cacheLine размер кэш-линии; шарды выравниваются по нему, чтобы не было false sharing
+const cacheLine = 64
+
+type counterShard struct {
+	value atomic.Int64
+	_     [cacheLine - 8]byte
+}
+
+// Counter счётчик, разделённый на шарды по числу CPU.
+// Add из разных горутин попадает в разные шарды и почти не конкурирует,
+// зато Value обходит все шарды, поэтому счётчик рассчитан на частую запись и редкое чтение
+type Counter struct {
+	shards []counterShard
+}
+
+// NewCounter - конструктор для Counter
+func NewCounter() *Counter {
+	return &Counter{shards: make([]counterShard, nextPowerOfTwo(runtime.GOMAXPROCS(0)))}
+}
+
+// Add прибавляет delta к счётчику
+func (c *Counter) Add(delta int64) {
+	// rand.Uint32 из math/rand/v2 не берёт общих блокировок
+	i := rand.Uint32() & uint32(len(c.shards)-1)
+	c.shards[i].value.Add(delta)
+}
+
+// Inc увеличивает счётчик на 1
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Value возвращает сумму по всем шардам
+func (c *Counter) Value() int64 {
+	var sum int64
+	for i := range c.shards {
+		sum += c.shards[i].value.Load()
+	}
+	return sum
+}
+
+// Reset обнуляет счётчик. Конкурентные Add во время Reset могут как учесться, так и потеряться
+func (c *Counter) Reset() {
+	for i := range c.shards {
+		c.shards[i].value.Store(0)
+	}
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
@@ -0,0 +1,32 @@
+package concurrent
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Gauge значение float64 без блокировок: хранит биты числа в atomic.Uint64
+type Gauge struct {
+	bits atomic.Uint64
+}
+
+// Set устанавливает значение
+func (g *Gauge) Set(v float64) {
+	g.bits.Store(math.Float64bits(v))
+}
+
+// Add прибавляет delta (CAS в цикле) и возвращает новое значение
+func (g *Gauge) Add(delta float64) float64 {
+	for {
+		old := g.bits.Load()
+		updated := math.Float64frombits(old) + delta
+		if g.bits.CompareAndSwap(old, math.Float64bits(updated)) {
+			return updated
+		}
+	}
+}
+
+// Value возвращает текущее значение
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(g.bits.Load())
+}
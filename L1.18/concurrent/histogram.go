@@ -0,0 +1,95 @@
+package concurrent
+
+import (
+	"math"
+	"sort"
+	"sync/atomic"
+)
+
+// DefaultBuckets границы корзин по умолчанию, подходят для задержек HTTP в секундах
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Histogram распределение наблюдений по корзинам с фиксированными верхними границами.
+// Observe не берёт блокировок
+type Histogram struct {
+	bounds []float64
+	counts []atomic.Uint64 // последняя корзина - значения больше всех границ
+	count  atomic.Uint64
+	sum    Gauge
+}
+
+// HistogramSnapshot срез состояния гистограммы
+type HistogramSnapshot struct {
+	Bounds []float64 // верхние границы корзин
+	Counts []uint64  // len(Counts) == len(Bounds)+1, последняя - переполнение
+	Count  uint64
+	Sum    float64
+}
+
+// NewHistogram - конструктор для Histogram. Границы сортируются; пустой список означает DefaultBuckets
+func NewHistogram(bounds []float64) *Histogram {
+	if len(bounds) == 0 {
+		bounds = DefaultBuckets
+	}
+	b := append([]float64(nil), bounds...)
+	sort.Float64s(b)
+	return &Histogram{
+		bounds: b,
+		counts: make([]atomic.Uint64, len(b)+1),
+	}
+}
+
+// Observe добавляет наблюдение
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.bounds, v)
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(v)
+}
+
+// Snapshot возвращает копию счётчиков. Корзины читаются по очереди,
+// поэтому при конкурентной записи Count может немного отличаться от суммы Counts
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{
+		Bounds: append([]float64(nil), h.bounds...),
+		Counts: make([]uint64, len(h.counts)),
+		Count:  h.count.Load(),
+		Sum:    h.sum.Value(),
+	}
+	for i := range h.counts {
+		s.Counts[i] = h.counts[i].Load()
+	}
+	return s
+}
+
+// Quantile оценивает квантиль q (0..1) линейной интерполяцией внутри корзины.
+// Для значений из корзины переполнения возвращается последняя граница
+func (s HistogramSnapshot) Quantile(q float64) float64 {
+	var total uint64
+	for _, c := range s.Counts {
+		total += c
+	}
+	if total == 0 || math.IsNaN(q) {
+		return math.NaN()
+	}
+	q = math.Max(0, math.Min(1, q))
+
+	rank := q * float64(total)
+	var seen uint64
+	for i, c := range s.Counts {
+		if c == 0 || float64(seen+c) < rank {
+			seen += c
+			continue
+		}
+		if i == len(s.Bounds) {
+			return s.Bounds[len(s.Bounds)-1]
+		}
+		lower := 0.0
+		if i > 0 {
+			lower = s.Bounds[i-1]
+		}
+		upper := s.Bounds[i]
+		return lower + (upper-lower)*(rank-float64(seen))/float64(c)
+	}
+	return s.Bounds[len(s.Bounds)-1]
+}
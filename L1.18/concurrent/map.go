@@ -0,0 +1,121 @@
+package concurrent
+
+import (
+	"hash/maphash"
+	"runtime"
+	"sync"
+)
+
+type mapShard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+	_  [cacheLine]byte
+}
+
+// Map потокобезопасная map, разделённая на шарды, каждый со своим RWMutex.
+// Ключи распределяются по шардам хешем, поэтому операции с разными ключами редко конкурируют
+type Map[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []mapShard[K, V]
+}
+
+// NewMap - конструктор для Map. Число шардов округляется вверх до степени двойки,
+// shards <= 0 означает 4 шарда на каждый GOMAXPROCS: ключей больше, чем горутин, и запас
+// шардов снижает вероятность, что две горутины попадут в один
+func NewMap[K comparable, V any](shards int) *Map[K, V] {
+	if shards <= 0 {
+		shards = 4 * runtime.GOMAXPROCS(0)
+	}
+	shards = nextPowerOfTwo(shards)
+	m := &Map[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]mapShard[K, V], shards),
+	}
+	for i := range m.shards {
+		m.shards[i].m = make(map[K]V)
+	}
+	return m
+}
+
+func (m *Map[K, V]) shard(key K) *mapShard[K, V] {
+	h := maphash.Comparable(m.seed, key)
+	return &m.shards[h&uint64(len(m.shards)-1)]
+}
+
+// Load возвращает значение по ключу
+func (m *Map[K, V]) Load(key K) (V, bool) {
+	s := m.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.m[key]
+	return v, ok
+}
+
+// Store записывает значение по ключу
+func (m *Map[K, V]) Store(key K, value V) {
+	s := m.shard(key)
+	s.mu.Lock()
+	s.m[key] = value
+	s.mu.Unlock()
+}
+
+// LoadOrStore возвращает существующее значение, либо записывает value.
+// loaded == true, если значение уже было
+func (m *Map[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v, true
+	}
+	s.m[key] = value
+	return value, false
+}
+
+// Update атомарно заменяет значение по ключу результатом fn и возвращает новое значение.
+// fn получает текущее значение и признак его наличия; fn вызывается под блокировкой шарда
+func (m *Map[K, V]) Update(key K, fn func(old V, ok bool) V) V {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.m[key]
+	v := fn(old, ok)
+	s.m[key] = v
+	return v
+}
+
+// Delete удаляет ключ
+func (m *Map[K, V]) Delete(key K) {
+	s := m.shard(key)
+	s.mu.Lock()
+	delete(s.m, key)
+	s.mu.Unlock()
+}
+
+// Len возвращает количество элементов. При конкурентной записи значение приблизительное
+func (m *Map[K, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Range вызывает fn для каждого элемента, пока fn возвращает true.
+// Шард блокируется на чтение на время обхода, поэтому fn не должна менять Map
+func (m *Map[K, V]) Range(fn func(key K, value V) bool) {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		for k, v := range s.m {
+			if !fn(k, v) {
+				s.mu.RUnlock()
+				return
+			}
+		}
+		s.mu.RUnlock()
+	}
+}
@@ -0,0 +1,3 @@
+module L1.18
+
+go 1.24.0
@@ -0,0 +1,184 @@
+package bigcalc
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// DefaultPrecision количество знаков после запятой при выводе по умолчанию
+const DefaultPrecision = 20
+
+// Ограничения по умолчанию, чтобы одно выражение не съело всю память. MaxExponent и MaxFactorial
+// ограничивают аргументы, MaxResultBits — размер числителя и знаменателя любого промежуточного
+// результата: без него (9^99999)^99999 проходит проверку показателя
+const (
+	DefaultMaxExponent   = 100_000
+	DefaultMaxFactorial  = 10_000
+	DefaultMaxResultBits = 1 << 22 // около 1,26 млн десятичных знаков
+)
+
+// LastResult имя переменной с результатом последнего вычисления
+const LastResult = "ans"
+
+// Calculator вычисляет выражения в точной рациональной арифметике (big.Rat)
+// и хранит переменные между вызовами Eval. Не безопасен для конкурентного использования
+type Calculator struct {
+	Precision     int // знаков после запятой в Format
+	MaxExponent   int64
+	MaxFactorial  int64
+	MaxResultBits int64
+
+	vars map[string]*big.Rat
+}
+
+// New - конструктор для Calculator
+func New() *Calculator {
+	return &Calculator{
+		Precision:     DefaultPrecision,
+		MaxExponent:   DefaultMaxExponent,
+		MaxFactorial:  DefaultMaxFactorial,
+		MaxResultBits: DefaultMaxResultBits,
+		vars:          make(map[string]*big.Rat),
+	}
+}
+
+// Eval вычисляет выражение или присваивание вида "x = 1/3" и возвращает точный результат.
+// Результат также сохраняется в переменную ans
+func (c *Calculator) Eval(expr string) (*big.Rat, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{calc: c, tokens: tokens}
+	v, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("лишний токен %q", t.text)}
+	}
+	c.vars[LastResult] = new(big.Rat).Set(v)
+	return v, nil
+}
+
+// EvalString вычисляет выражение и форматирует результат с точностью Precision
+func (c *Calculator) EvalString(expr string) (string, error) {
+	v, err := c.Eval(expr)
+	if err != nil {
+		return "", err
+	}
+	return Format(v, c.Precision), nil
+}
+
+// Set задаёт переменную
+func (c *Calculator) Set(name string, v *big.Rat) {
+	c.vars[name] = new(big.Rat).Set(v)
+}
+
+// Get возвращает копию значения переменной
+func (c *Calculator) Get(name string) (*big.Rat, bool) {
+	v, ok := c.vars[name]
+	if !ok {
+		return nil, false
+	}
+	return new(big.Rat).Set(v), true
+}
+
+// Vars возвращает имена переменных по алфавиту
+func (c *Calculator) Vars() []string {
+	names := make([]string, 0, len(c.vars))
+	for name := range c.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Format выводит число в десятичном виде: целые без дробной части,
+// дробные округляются до precision знаков (половины от нуля), хвостовые нули отбрасываются
+func Format(v *big.Rat, precision int) string {
+	if v.IsInt() {
+		return v.Num().String()
+	}
+	if precision < 0 {
+		precision = 0
+	}
+	s := v.FloatString(precision)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimSuffix(s, ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+func isReserved(name string) bool {
+	return name == LastResult
+}
+
+func (c *Calculator) pow(base, exp *big.Rat, pos int) (*big.Rat, error) {
+	if !exp.IsInt() {
+		return nil, &SyntaxError{Pos: pos, Msg: "показатель степени должен быть целым, иначе результат не рационален"}
+	}
+	n := exp.Num()
+	if !n.IsInt64() || abs64(n.Int64()) > c.MaxExponent {
+		return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("показатель степени по модулю больше %d", c.MaxExponent)}
+	}
+	e := n.Int64()
+	if e < 0 && base.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	// размер результата оцениваем до вычисления: bitlen(a^e) <= bitlen(a) * |e|
+	baseBits := int64(max(base.Num().BitLen(), base.Denom().BitLen()))
+	if baseBits > 1 && baseBits > c.MaxResultBits/max(abs64(e), 1) {
+		return nil, c.tooLarge(pos)
+	}
+
+	absE := big.NewInt(abs64(e))
+	num := new(big.Int).Exp(base.Num(), absE, nil)
+	den := new(big.Int).Exp(base.Denom(), absE, nil)
+	if e < 0 {
+		num, den = den, num
+	}
+	return new(big.Rat).SetFrac(num, den), nil
+}
+
+func (c *Calculator) factorial(v *big.Rat, pos int) (*big.Rat, error) {
+	if !v.IsInt() || v.Sign() < 0 {
+		return nil, &SyntaxError{Pos: pos, Msg: "факториал определён только для неотрицательных целых"}
+	}
+	n := v.Num()
+	if !n.IsInt64() || n.Int64() > c.MaxFactorial {
+		return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("факториал числа больше %d", c.MaxFactorial)}
+	}
+	// bitlen(n!) <= n * bitlen(n)
+	if k := n.Int64(); k > 1 && int64(n.BitLen()) > c.MaxResultBits/k {
+		return nil, c.tooLarge(pos)
+	}
+	result := new(big.Int).MulRange(1, n.Int64())
+	return new(big.Rat).SetInt(result), nil
+}
+
+// checkSize проверяет размер уже вычисленного результата; для умножения и деления он
+// не больше суммы размеров операндов, поэтому проверка после вычисления безопасна
+func (c *Calculator) checkSize(v *big.Rat, pos int) error {
+	if int64(max(v.Num().BitLen(), v.Denom().BitLen())) > c.MaxResultBits {
+		return c.tooLarge(pos)
+	}
+	return nil
+}
+
+func (c *Calculator) tooLarge(pos int) error {
+	return &SyntaxError{Pos: pos, Msg: fmt.Sprintf("результат больше %d бит", c.MaxResultBits)}
+}
+
+func abs64(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
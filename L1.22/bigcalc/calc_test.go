@@ -0,0 +1,126 @@
+package bigcalc_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"L1.22/bigcalc"
+)
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"1 + 2 * 3", "7"},
+		{"(1 + 2) * 3", "9"},
+		{"1/3 + 1/6", "0.5"},
+		{"0.1 + 0.2", "0.3"},
+		{"2^10", "1024"},
+		{"2^3^2", "512"},
+		{"-2^2", "-4"},
+		{"(-2)^2", "4"},
+		{"2^-2", "0.25"},
+		{"(2/3)^2", "0.44444444444444444444"},
+		{"5!", "120"},
+		{"0!", "1"},
+		{"3!!", "720"},
+		{"2 * 3!", "12"},
+		{"7 % 3", "1"},
+		{"-7 % 3", "-1"},
+		{"7.5 % 2", "1.5"},
+		{"1e3 / 8", "125"},
+		{"1_000_000 * 1_000_000", "1000000000000"},
+		{"10 - 4 - 3", "3"},
+		{"2 / 4 / 2", "0.25"},
+		{"25!", "15511210043330985984000000"},
+		{"99999999999999999999 + 1", "100000000000000000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := bigcalc.New().EvalString(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got=%s, want=%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEval_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  error
+	}{
+		{"1 / 0", bigcalc.ErrDivisionByZero},
+		{"1 % 0", bigcalc.ErrDivisionByZero},
+		{"0^-1", bigcalc.ErrDivisionByZero},
+	}
+	for _, tt := range tests {
+		if _, err := bigcalc.New().Eval(tt.expr); !errors.Is(err, tt.err) {
+			t.Errorf("%s: ожидали %v, получили %v", tt.expr, tt.err, err)
+		}
+	}
+
+	syntax := []string{"", "1 +", "(1 + 2", "1 2", "2^(1/2)", "(-1)!", "1.5!", "x", "1 $ 2", "ans = 1", "2^1000000", "100000!", "(9^99999)^99999", "(2^100000)^100000", "9^99999 * 9^99999 * 9^99999 * 9^99999 * 9^99999 * 9^99999 * 9^99999 * 9^99999 * 9^99999 * 9^99999 * 9^99999 * 9^99999 * 9^99999 * 9^99999"}
+	for _, expr := range syntax {
+		_, err := bigcalc.New().Eval(expr)
+		var se *bigcalc.SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("%q: ожидали SyntaxError, получили %v", expr, err)
+		}
+	}
+}
+
+func TestVariables(t *testing.T) {
+	c := bigcalc.New()
+	steps := []struct {
+		expr string
+		want string
+	}{
+		{"x = 1/3", "0.33333333333333333333"},
+		{"y = z = 2", "2"},
+		{"x * 3", "1"},
+		{"ans + y + z", "5"},
+	}
+	for _, s := range steps {
+		got, err := c.EvalString(s.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", s.expr, err)
+		}
+		if got != s.want {
+			t.Errorf("%s: got=%s, want=%s", s.expr, got, s.want)
+		}
+	}
+	if vars := strings.Join(c.Vars(), ","); vars != "ans,x,y,z" {
+		t.Errorf("переменные: %s", vars)
+	}
+}
+
+func TestFormat_Precision(t *testing.T) {
+	c := bigcalc.New()
+	v, err := c.Eval("2/3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[int]string{0: "1", 2: "0.67", 5: "0.66667"}
+	for precision, want := range tests {
+		if got := bigcalc.Format(v, precision); got != want {
+			t.Errorf("precision=%d: got=%s, want=%s", precision, got, want)
+		}
+	}
+	if got := bigcalc.Format(v.Neg(v), 0); got != "-1" {
+		t.Errorf("got=%s", got)
+	}
+	small, _ := c.Eval("-1/1000")
+	if got := bigcalc.Format(small, 2); got != "0" {
+		t.Errorf("-0 должен выводиться как 0, got=%s", got)
+	}
+	if v.RatString() != "-2/3" {
+		t.Errorf("RatString: %s", v.RatString())
+	}
+}
@@ -0,0 +1,88 @@
+package bigcalc
+
+import (
+	"fmt"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp // + - * / % ^ ! ( ) =
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // позиция в исходной строке (в рунах), для сообщений об ошибках
+}
+
+// SyntaxError ошибка разбора выражения с позицией
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("синтаксическая ошибка в позиции %d: %s", e.Pos+1, e.Msg)
+}
+
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			i = scanNumber(runes, i)
+			tokens = append(tokens, token{kind: tokNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: string(runes[start:i]), pos: start})
+		case isOperator(r):
+			tokens = append(tokens, token{kind: tokOp, text: string(r), pos: i})
+			i++
+		default:
+			return nil, &SyntaxError{Pos: i, Msg: fmt.Sprintf("неожиданный символ %q", r)}
+		}
+	}
+	tokens = append(tokens, token{kind: tokEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+// scanNumber читает число вида 123, 1.25, .5, 1e-3 и возвращает позицию после него
+func scanNumber(runes []rune, i int) int {
+	for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == '_') {
+		i++
+	}
+	if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+		j := i + 1
+		if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+			j++
+		}
+		if j < len(runes) && unicode.IsDigit(runes[j]) {
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			i = j
+		}
+	}
+	return i
+}
+
+func isOperator(r rune) bool {
+	switch r {
+	case '+', '-', '*', '/', '%', '^', '!', '(', ')', '=':
+		return true
+	}
+	return false
+}
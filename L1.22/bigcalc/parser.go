@@ -0,0 +1,207 @@
+package bigcalc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrDivisionByZero деление или остаток от деления на ноль
+var ErrDivisionByZero = errors.New("деление на ноль")
+
+// parser рекурсивный спуск, вычисляющий значение по ходу разбора.
+// Приоритеты от низкого к высокому:
+//
+//	= (правоассоциативное, только после имени переменной)
+//	+ -
+//	* / %
+//	унарные + -
+//	^ (правоассоциативное, -2^2 = -4)
+//	! (постфиксный факториал)
+type parser struct {
+	calc   *Calculator
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isOp(op string) bool {
+	t := p.peek()
+	return t.kind == tokOp && t.text == op
+}
+
+func (p *parser) statement() (*big.Rat, error) {
+	if p.peek().kind == tokIdent && p.tokens[p.pos+1].kind == tokOp && p.tokens[p.pos+1].text == "=" {
+		name := p.next()
+		p.next()
+		if isReserved(name.text) {
+			return nil, &SyntaxError{Pos: name.pos, Msg: fmt.Sprintf("имя %q зарезервировано", name.text)}
+		}
+		v, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		p.calc.vars[name.text] = new(big.Rat).Set(v)
+		return v, nil
+	}
+	return p.expression()
+}
+
+func (p *parser) expression() (*big.Rat, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+") || p.isOp("-") {
+		op := p.next()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		if op.text == "+" {
+			left = new(big.Rat).Add(left, right)
+		} else {
+			left = new(big.Rat).Sub(left, right)
+		}
+		// у дробей знаменатели перемножаются, поэтому сумма тоже может вырасти
+		if err := p.calc.checkSize(left, op.pos); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) term() (*big.Rat, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*") || p.isOp("/") || p.isOp("%") {
+		op := p.next()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		switch op.text {
+		case "*":
+			left = new(big.Rat).Mul(left, right)
+		case "/":
+			if right.Sign() == 0 {
+				return nil, ErrDivisionByZero
+			}
+			left = new(big.Rat).Quo(left, right)
+		case "%":
+			left, err = mod(left, right)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := p.calc.checkSize(left, op.pos); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (*big.Rat, error) {
+	if p.isOp("-") {
+		p.next()
+		v, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Rat).Neg(v), nil
+	}
+	if p.isOp("+") {
+		p.next()
+		return p.unary()
+	}
+	return p.power()
+}
+
+func (p *parser) power() (*big.Rat, error) {
+	base, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOp("^") {
+		return base, nil
+	}
+	op := p.next()
+	// показатель может быть с унарным минусом: 2^-1
+	exp, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return p.calc.pow(base, exp, op.pos)
+}
+
+func (p *parser) postfix() (*big.Rat, error) {
+	v, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("!") {
+		op := p.next()
+		v, err = p.calc.factorial(v, op.pos)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func (p *parser) primary() (*big.Rat, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		v, ok := new(big.Rat).SetString(strings.ReplaceAll(t.text, "_", ""))
+		if !ok {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("неверное число %q", t.text)}
+		}
+		return v, nil
+	case tokIdent:
+		v, ok := p.calc.Get(t.text)
+		if !ok {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("неизвестная переменная %q", t.text)}
+		}
+		return v, nil
+	case tokOp:
+		if t.text == "(" {
+			v, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			if !p.isOp(")") {
+				return nil, &SyntaxError{Pos: p.peek().pos, Msg: "ожидалась )"}
+			}
+			p.next()
+			return v, nil
+		}
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("неожиданный оператор %q", t.text)}
+	default:
+		return nil, &SyntaxError{Pos: t.pos, Msg: "неожиданный конец выражения"}
+	}
+}
+
+// mod остаток от деления со знаком делимого, как оператор % в Go: a - b*trunc(a/b)
+func mod(a, b *big.Rat) (*big.Rat, error) {
+	if b.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	q := new(big.Rat).Quo(a, b)
+	trunc := new(big.Int).Quo(q.Num(), q.Denom())
+	return new(big.Rat).Sub(a, new(big.Rat).Mul(b, new(big.Rat).SetInt(trunc))), nil
+}
@@ -0,0 +1,97 @@
+package main
+
+// go run ./cmd/bigcalc -e "1/3 + 1/6"
+// go run ./cmd/bigcalc -p 5          REPL: x = 10^20 / 7, x % 3, 25!, :vars, :p 50, :q
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"L1.22/bigcalc"
+)
+
+func main() {
+	precision := flag.Int("p", bigcalc.DefaultPrecision, "количество знаков после запятой при выводе")
+	expr := flag.String("e", "", "вычислить выражение и выйти")
+	exact := flag.Bool("frac", false, "выводить результат обыкновенной дробью")
+	flag.Parse()
+
+	calc := bigcalc.New()
+	calc.Precision = *precision
+
+	if *expr != "" {
+		v, err := calc.Eval(*expr)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Ошибка:", err)
+			os.Exit(1)
+		}
+		fmt.Println(format(calc, v, *exact))
+		return
+	}
+
+	interactive := isTerminal(os.Stdin)
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		if interactive {
+			fmt.Print("> ")
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case line == ":q":
+			return
+		case line == ":vars":
+			for _, name := range calc.Vars() {
+				v, _ := calc.Get(name)
+				fmt.Printf("%s = %s\n", name, format(calc, v, *exact))
+			}
+			continue
+		case line == ":frac":
+			*exact = !*exact
+			continue
+		case strings.HasPrefix(line, ":p"):
+			n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, ":p")))
+			if err != nil || n < 0 {
+				fmt.Fprintln(os.Stderr, "Ошибка: ожидалось неотрицательное число знаков")
+				continue
+			}
+			calc.Precision = n
+			continue
+		}
+
+		v, err := calc.Eval(line)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Ошибка:", err)
+			continue
+		}
+		fmt.Println(format(calc, v, *exact))
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка чтения:", err)
+		os.Exit(1)
+	}
+}
+
+func format(calc *bigcalc.Calculator, v *big.Rat, exact bool) string {
+	if exact {
+		return v.RatString()
+	}
+	return bigcalc.Format(v, calc.Precision)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
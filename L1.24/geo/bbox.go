@@ -0,0 +1,59 @@
+package geo
+
+import (
+	"math"
+
+	points "L1.24/models"
+)
+
+// BoundingBox прямоугольник, стороны которого параллельны осям
+type BoundingBox struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// Bounds возвращает наименьший прямоугольник, содержащий все точки
+func Bounds(pts []*points.Point) (BoundingBox, bool) {
+	if len(pts) == 0 {
+		return BoundingBox{}, false
+	}
+	box := BoundingBox{MinX: pts[0].X(), MinY: pts[0].Y(), MaxX: pts[0].X(), MaxY: pts[0].Y()}
+	for _, p := range pts[1:] {
+		box = box.Extend(p)
+	}
+	return box, true
+}
+
+// Extend возвращает прямоугольник, расширенный до точки p
+func (b BoundingBox) Extend(p *points.Point) BoundingBox {
+	return BoundingBox{
+		MinX: math.Min(b.MinX, p.X()),
+		MinY: math.Min(b.MinY, p.Y()),
+		MaxX: math.Max(b.MaxX, p.X()),
+		MaxY: math.Max(b.MaxY, p.Y()),
+	}
+}
+
+// Contains проверяет, лежит ли точка внутри прямоугольника или на его границе
+func (b BoundingBox) Contains(p *points.Point) bool {
+	return p.X() >= b.MinX && p.X() <= b.MaxX && p.Y() >= b.MinY && p.Y() <= b.MaxY
+}
+
+// Intersects проверяет, пересекаются ли прямоугольники
+func (b BoundingBox) Intersects(o BoundingBox) bool {
+	return b.MinX <= o.MaxX && o.MinX <= b.MaxX && b.MinY <= o.MaxY && o.MinY <= b.MaxY
+}
+
+// Width ширина прямоугольника
+func (b BoundingBox) Width() float64 {
+	return b.MaxX - b.MinX
+}
+
+// Height высота прямоугольника
+func (b BoundingBox) Height() float64 {
+	return b.MaxY - b.MinY
+}
+
+// Center центр прямоугольника
+func (b BoundingBox) Center() *points.Point {
+	return points.NewPoint((b.MinX+b.MaxX)/2, (b.MinY+b.MaxY)/2)
+}
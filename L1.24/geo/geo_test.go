@@ -0,0 +1,207 @@
+package geo_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"L1.24/geo"
+	points "L1.24/models"
+)
+
+func pt(x, y float64) *points.Point {
+	return points.NewPoint(x, y)
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestPolygon_AreaCentroidContains(t *testing.T) {
+	square := geo.Polygon{pt(0, 0), pt(4, 0), pt(4, 4), pt(0, 4)}
+
+	if !almostEqual(square.Area(), 16) {
+		t.Errorf("площадь: got=%v, want=16", square.Area())
+	}
+	if !almostEqual(square.Perimeter(), 16) {
+		t.Errorf("периметр: got=%v, want=16", square.Perimeter())
+	}
+	c, err := square.Centroid()
+	if err != nil || !almostEqual(c.X(), 2) || !almostEqual(c.Y(), 2) {
+		t.Errorf("центр масс: got=%v err=%v", c, err)
+	}
+
+	tests := []struct {
+		p    *points.Point
+		want bool
+	}{
+		{pt(2, 2), true},
+		{pt(0, 2), true}, // на границе
+		{pt(4, 4), true}, // вершина
+		{pt(5, 2), false},
+		{pt(-1, -1), false},
+	}
+	for _, tt := range tests {
+		if got := square.Contains(tt.p); got != tt.want {
+			t.Errorf("Contains(%v, %v): got=%v, want=%v", tt.p.X(), tt.p.Y(), got, tt.want)
+		}
+	}
+
+	if _, err := (geo.Polygon{pt(0, 0), pt(1, 1), pt(2, 2)}).Centroid(); err == nil {
+		t.Error("ожидали ошибку для вырожденного многоугольника")
+	}
+}
+
+func TestPolygon_ConcaveContains(t *testing.T) {
+	// буква "П"
+	pg := geo.Polygon{pt(0, 0), pt(1, 0), pt(1, 2), pt(2, 2), pt(2, 0), pt(3, 0), pt(3, 3), pt(0, 3)}
+	if pg.Contains(pt(1.5, 1)) {
+		t.Error("точка в вырезе не должна принадлежать многоугольнику")
+	}
+	if !pg.Contains(pt(0.5, 1)) || !pg.Contains(pt(1.5, 2.5)) {
+		t.Error("точки внутри должны принадлежать многоугольнику")
+	}
+	if !almostEqual(pg.Area(), 7) {
+		t.Errorf("площадь: got=%v, want=7", pg.Area())
+	}
+}
+
+func TestSegment_Intersection(t *testing.T) {
+	tests := []struct {
+		name   string
+		s1, s2 geo.Segment
+		ok     bool
+		x, y   float64
+	}{
+		{"крест", geo.NewSegment(pt(0, 0), pt(2, 2)), geo.NewSegment(pt(0, 2), pt(2, 0)), true, 1, 1},
+		{"параллельные", geo.NewSegment(pt(0, 0), pt(2, 0)), geo.NewSegment(pt(0, 1), pt(2, 1)), false, 0, 0},
+		{"касание концом", geo.NewSegment(pt(0, 0), pt(2, 0)), geo.NewSegment(pt(2, 0), pt(3, 5)), true, 2, 0},
+		{"коллинеарные с наложением", geo.NewSegment(pt(0, 0), pt(3, 0)), geo.NewSegment(pt(2, 0), pt(5, 0)), true, 2, 0},
+		{"коллинеарные без наложения", geo.NewSegment(pt(0, 0), pt(1, 0)), geo.NewSegment(pt(2, 0), pt(3, 0)), false, 0, 0},
+		{"не доходят", geo.NewSegment(pt(0, 0), pt(1, 1)), geo.NewSegment(pt(0, 3), pt(3, 0)), false, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := tt.s1.Intersection(tt.s2)
+			if ok != tt.ok {
+				t.Fatalf("ok: got=%v, want=%v", ok, tt.ok)
+			}
+			if ok && (!almostEqual(p.X(), tt.x) || !almostEqual(p.Y(), tt.y)) {
+				t.Errorf("точка: got=(%v, %v), want=(%v, %v)", p.X(), p.Y(), tt.x, tt.y)
+			}
+		})
+	}
+}
+
+func TestConvexHull(t *testing.T) {
+	pts := []*points.Point{pt(0, 0), pt(2, 0), pt(1, 1), pt(2, 2), pt(0, 2), pt(1, 0), pt(0.5, 1.5)}
+	hull := geo.ConvexHull(pts)
+
+	if len(hull) != 4 {
+		t.Fatalf("ожидали 4 вершины, получили %d", len(hull))
+	}
+	if hull.SignedArea() <= 0 {
+		t.Error("вершины должны идти против часовой стрелки")
+	}
+	if !almostEqual(hull.Area(), 4) {
+		t.Errorf("площадь оболочки: got=%v, want=4", hull.Area())
+	}
+	for _, p := range pts {
+		if !hull.Contains(p) {
+			t.Errorf("точка (%v, %v) вне оболочки", p.X(), p.Y())
+		}
+	}
+
+	if got := geo.ConvexHull([]*points.Point{pt(1, 1), pt(1, 1)}); len(got) != 1 {
+		t.Errorf("дубликаты должны схлопываться, получили %d точек", len(got))
+	}
+	if got := geo.ConvexHull([]*points.Point{pt(3, 4), pt(3, 4), pt(3, 4), pt(3, 4)}); len(got) != 1 {
+		t.Errorf("оболочка одинаковых точек - одна точка, получили %d", len(got))
+	}
+	if got := geo.ConvexHull([]*points.Point{pt(0, 0), pt(0, 0), pt(2, 0), pt(2, 0), pt(0, 0)}); len(got) != 2 {
+		t.Errorf("оболочка двух различных точек - отрезок, получили %d точек", len(got))
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	box, ok := geo.Bounds([]*points.Point{pt(1, 5), pt(-2, 3), pt(4, -1)})
+	if !ok {
+		t.Fatal("ожидали прямоугольник")
+	}
+	want := geo.BoundingBox{MinX: -2, MinY: -1, MaxX: 4, MaxY: 5}
+	if box != want {
+		t.Errorf("got=%+v, want=%+v", box, want)
+	}
+	if !box.Contains(pt(0, 0)) || box.Contains(pt(5, 0)) {
+		t.Error("неверная проверка принадлежности")
+	}
+	if !box.Intersects(geo.BoundingBox{MinX: 4, MinY: 5, MaxX: 6, MaxY: 6}) {
+		t.Error("касающиеся прямоугольники пересекаются")
+	}
+	if _, ok := geo.Bounds(nil); ok {
+		t.Error("для пустого списка прямоугольника нет")
+	}
+}
+
+func TestKDTree_MatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	pts := make([]*points.Point, 500)
+	for i := range pts {
+		pts[i] = pt(rng.Float64()*100, rng.Float64()*100)
+	}
+	tree := geo.NewKDTree(pts)
+	if tree.Len() != len(pts) {
+		t.Fatalf("Len: got=%d", tree.Len())
+	}
+
+	for i := 0; i < 100; i++ {
+		target := pt(rng.Float64()*100, rng.Float64()*100)
+
+		best := math.Inf(1)
+		for _, p := range pts {
+			best = math.Min(best, p.Distance(target))
+		}
+		_, dist, ok := tree.Nearest(target)
+		if !ok || !almostEqual(dist, best) {
+			t.Fatalf("Nearest: got=%v, want=%v", dist, best)
+		}
+
+		knn := tree.KNearest(target, 5)
+		if len(knn) != 5 || !almostEqual(knn[0].Distance(target), best) {
+			t.Fatalf("KNearest: неверный первый элемент")
+		}
+		for j := 1; j < len(knn); j++ {
+			if knn[j].Distance(target) < knn[j-1].Distance(target) {
+				t.Fatalf("KNearest: результат не отсортирован")
+			}
+		}
+	}
+
+	box := geo.BoundingBox{MinX: 10, MinY: 10, MaxX: 30, MaxY: 40}
+	want := 0
+	for _, p := range pts {
+		if box.Contains(p) {
+			want++
+		}
+	}
+	if got := len(tree.InBox(box)); got != want {
+		t.Errorf("InBox: got=%d, want=%d", got, want)
+	}
+
+	if _, _, ok := geo.NewKDTree(nil).Nearest(pt(0, 0)); ok {
+		t.Error("в пустом дереве нет ближайшей точки")
+	}
+}
+
+func TestHaversine(t *testing.T) {
+	moscow := geo.NewLatLon(55.7558, 37.6173)
+	petersburg := geo.NewLatLon(59.9343, 30.3351)
+
+	d := geo.Haversine(moscow, petersburg)
+	if math.Abs(d-634_000) > 5_000 {
+		t.Errorf("Москва - Санкт-Петербург: got=%v м", d)
+	}
+	if geo.Haversine(moscow, moscow) != 0 {
+		t.Error("расстояние до себя должно быть 0")
+	}
+}
@@ -0,0 +1,31 @@
+package geo
+
+import (
+	"math"
+
+	points "L1.24/models"
+)
+
+// EarthRadius средний радиус Земли в метрах
+const EarthRadius = 6371008.8
+
+// NewLatLon создаёт точку из широты и долготы в градусах.
+// Для функций с географическими координатами x - долгота, y - широта
+func NewLatLon(lat, lon float64) *points.Point {
+	return points.NewPoint(lon, lat)
+}
+
+// Haversine расстояние по дуге большого круга между точками, созданными через NewLatLon, в метрах
+func Haversine(a, b *points.Point) float64 {
+	lat1, lat2 := toRadians(a.Y()), toRadians(b.Y())
+	dLat := lat2 - lat1
+	dLon := toRadians(b.X() - a.X())
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,55 @@
+package geo
+
+import (
+	"sort"
+
+	points "L1.24/models"
+)
+
+// ConvexHull строит выпуклую оболочку алгоритмом Эндрю (монотонная цепочка) за O(n*log(n)).
+// Вершины возвращаются против часовой стрелки, точки на сторонах оболочки не включаются
+func ConvexHull(pts []*points.Point) Polygon {
+	sorted := append([]*points.Point(nil), pts...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X() != sorted[j].X() {
+			return sorted[i].X() < sorted[j].X()
+		}
+		return sorted[i].Y() < sorted[j].Y()
+	})
+	// совпадающие точки убираются заранее, иначе из одинаковых точек получится
+	// вырожденная оболочка с повторяющимися вершинами
+	sorted = dedup(sorted)
+	if len(sorted) < 3 {
+		return Polygon(sorted)
+	}
+
+	hull := make([]*points.Point, 0, 2*len(sorted))
+	// нижняя цепочка
+	for _, p := range sorted {
+		for len(hull) >= 2 && orientation(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// верхняя цепочка
+	lower := len(hull) + 1
+	for i := len(sorted) - 2; i >= 0; i-- {
+		p := sorted[i]
+		for len(hull) >= lower && orientation(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	return Polygon(hull[:len(hull)-1])
+}
+
+func dedup(sorted []*points.Point) []*points.Point {
+	result := make([]*points.Point, 0, len(sorted))
+	for _, p := range sorted {
+		if len(result) > 0 && result[len(result)-1].Distance(p) < Epsilon {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package geo
+
+import (
+	"container/heap"
+	"sort"
+
+	points "L1.24/models"
+)
+
+type kdNode struct {
+	point       *points.Point
+	left, right *kdNode
+	axis        int // 0 - разбиение по x, 1 - по y
+}
+
+// KDTree двумерное k-d дерево для поиска ближайших точек.
+// Дерево строится один раз и не изменяется, поэтому безопасно для конкурентного чтения
+type KDTree struct {
+	root *kdNode
+	size int
+}
+
+// NewKDTree строит сбалансированное дерево по медианам за O(n*log^2(n))
+func NewKDTree(pts []*points.Point) *KDTree {
+	cp := append([]*points.Point(nil), pts...)
+	return &KDTree{root: buildKD(cp, 0), size: len(cp)}
+}
+
+func buildKD(pts []*points.Point, depth int) *kdNode {
+	if len(pts) == 0 {
+		return nil
+	}
+	axis := depth % 2
+	sort.Slice(pts, func(i, j int) bool {
+		return coord(pts[i], axis) < coord(pts[j], axis)
+	})
+	mid := len(pts) / 2
+	return &kdNode{
+		point: pts[mid],
+		axis:  axis,
+		left:  buildKD(pts[:mid], depth+1),
+		right: buildKD(pts[mid+1:], depth+1),
+	}
+}
+
+func coord(p *points.Point, axis int) float64 {
+	if axis == 0 {
+		return p.X()
+	}
+	return p.Y()
+}
+
+func distSq(a, b *points.Point) float64 {
+	dx, dy := a.X()-b.X(), a.Y()-b.Y()
+	return dx*dx + dy*dy
+}
+
+// Len количество точек в дереве
+func (t *KDTree) Len() int {
+	return t.size
+}
+
+// Nearest возвращает ближайшую к target точку и расстояние до неё
+func (t *KDTree) Nearest(target *points.Point) (*points.Point, float64, bool) {
+	res := t.KNearest(target, 1)
+	if len(res) == 0 {
+		return nil, 0, false
+	}
+	return res[0], res[0].Distance(target), true
+}
+
+// KNearest возвращает до k ближайших к target точек по возрастанию расстояния
+func (t *KDTree) KNearest(target *points.Point, k int) []*points.Point {
+	if k <= 0 || t.root == nil {
+		return nil
+	}
+	h := &maxHeap{}
+	searchKD(t.root, target, k, h)
+
+	result := make([]*points.Point, h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(h).(candidate).point
+	}
+	return result
+}
+
+func searchKD(n *kdNode, target *points.Point, k int, h *maxHeap) {
+	if n == nil {
+		return
+	}
+	d := distSq(n.point, target)
+	if h.Len() < k {
+		heap.Push(h, candidate{point: n.point, dist: d})
+	} else if d < (*h)[0].dist {
+		(*h)[0] = candidate{point: n.point, dist: d}
+		heap.Fix(h, 0)
+	}
+
+	diff := coord(target, n.axis) - coord(n.point, n.axis)
+	near, far := n.left, n.right
+	if diff > 0 {
+		near, far = far, near
+	}
+	searchKD(near, target, k, h)
+	// в дальнее поддерево идём, только если разделяющая прямая ближе худшего кандидата
+	if h.Len() < k || diff*diff < (*h)[0].dist {
+		searchKD(far, target, k, h)
+	}
+}
+
+// InBox возвращает все точки дерева внутри прямоугольника
+func (t *KDTree) InBox(box BoundingBox) []*points.Point {
+	var result []*points.Point
+	var walk func(n *kdNode)
+	walk = func(n *kdNode) {
+		if n == nil {
+			return
+		}
+		if box.Contains(n.point) {
+			result = append(result, n.point)
+		}
+		c := coord(n.point, n.axis)
+		lo, hi := box.MinX, box.MaxX
+		if n.axis == 1 {
+			lo, hi = box.MinY, box.MaxY
+		}
+		if lo <= c {
+			walk(n.left)
+		}
+		if hi >= c {
+			walk(n.right)
+		}
+	}
+	walk(t.root)
+	return result
+}
+
+type candidate struct {
+	point *points.Point
+	dist  float64
+}
+
+// maxHeap куча кандидатов, на вершине самый дальний
+type maxHeap []candidate
+
+func (h maxHeap) Len() int           { return len(h) }
+func (h maxHeap) Less(i, j int) bool { return h[i].dist > h[j].dist }
+func (h maxHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x any)        { *h = append(*h, x.(candidate)) }
+func (h *maxHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
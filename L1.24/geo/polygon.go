@@ -0,0 +1,88 @@
+package geo
+
+import (
+	"errors"
+	"math"
+
+	points "L1.24/models"
+)
+
+// ErrDegeneratePolygon возвращается для многоугольника нулевой площади
+var ErrDegeneratePolygon = errors.New("вырожденный многоугольник")
+
+// Polygon простой многоугольник, вершины перечислены по порядку обхода,
+// последняя вершина соединяется с первой
+type Polygon []*points.Point
+
+// SignedArea площадь по формуле шнурования: > 0 при обходе против часовой стрелки
+func (pg Polygon) SignedArea() float64 {
+	if len(pg) < 3 {
+		return 0
+	}
+	sum := 0.0
+	for i, p := range pg {
+		q := pg[(i+1)%len(pg)]
+		sum += p.X()*q.Y() - q.X()*p.Y()
+	}
+	return sum / 2
+}
+
+// Area площадь многоугольника
+func (pg Polygon) Area() float64 {
+	return math.Abs(pg.SignedArea())
+}
+
+// Perimeter периметр многоугольника
+func (pg Polygon) Perimeter() float64 {
+	if len(pg) < 2 {
+		return 0
+	}
+	sum := 0.0
+	for i, p := range pg {
+		sum += p.Distance(pg[(i+1)%len(pg)])
+	}
+	return sum
+}
+
+// Centroid центр масс многоугольника (как пластины, а не вершин)
+func (pg Polygon) Centroid() (*points.Point, error) {
+	area := pg.SignedArea()
+	if math.Abs(area) < Epsilon {
+		return nil, ErrDegeneratePolygon
+	}
+	cx, cy := 0.0, 0.0
+	for i, p := range pg {
+		q := pg[(i+1)%len(pg)]
+		cross := p.X()*q.Y() - q.X()*p.Y()
+		cx += (p.X() + q.X()) * cross
+		cy += (p.Y() + q.Y()) * cross
+	}
+	return points.NewPoint(cx/(6*area), cy/(6*area)), nil
+}
+
+// Contains проверяет, лежит ли точка внутри многоугольника (лучом вправо).
+// Точки на границе считаются принадлежащими многоугольнику
+func (pg Polygon) Contains(p *points.Point) bool {
+	if len(pg) < 3 {
+		return false
+	}
+	inside := false
+	for i, a := range pg {
+		b := pg[(i+1)%len(pg)]
+		if NewSegment(a, b).Contains(p) {
+			return true
+		}
+		if (a.Y() > p.Y()) != (b.Y() > p.Y()) {
+			x := a.X() + (p.Y()-a.Y())*(b.X()-a.X())/(b.Y()-a.Y())
+			if p.X() < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Bounds ограничивающий прямоугольник многоугольника
+func (pg Polygon) Bounds() (BoundingBox, bool) {
+	return Bounds(pg)
+}
@@ -0,0 +1,70 @@
+package geo
+
+import (
+	"math"
+
+	points "L1.24/models"
+)
+
+// Segment отрезок между точками A и B
+type Segment struct {
+	A, B *points.Point
+}
+
+// NewSegment - конструктор для Segment
+func NewSegment(a, b *points.Point) Segment {
+	return Segment{A: a, B: b}
+}
+
+// Length длина отрезка
+func (s Segment) Length() float64 {
+	return s.A.Distance(s.B)
+}
+
+// Contains проверяет, лежит ли точка p на отрезке
+func (s Segment) Contains(p *points.Point) bool {
+	return orientation(s.A, s.B, p) == 0 && onBox(s.A, s.B, p)
+}
+
+// onBox проверяет, что p лежит в прямоугольнике, натянутом на a и b
+func onBox(a, b, p *points.Point) bool {
+	return p.X() >= math.Min(a.X(), b.X())-Epsilon && p.X() <= math.Max(a.X(), b.X())+Epsilon &&
+		p.Y() >= math.Min(a.Y(), b.Y())-Epsilon && p.Y() <= math.Max(a.Y(), b.Y())+Epsilon
+}
+
+// Intersects проверяет, есть ли у отрезков общая точка (включая касание концами и наложение)
+func (s Segment) Intersects(o Segment) bool {
+	d1 := orientation(s.A, s.B, o.A)
+	d2 := orientation(s.A, s.B, o.B)
+	d3 := orientation(o.A, o.B, s.A)
+	d4 := orientation(o.A, o.B, s.B)
+
+	if d1 != d2 && d3 != d4 {
+		return true
+	}
+	return (d1 == 0 && onBox(s.A, s.B, o.A)) ||
+		(d2 == 0 && onBox(s.A, s.B, o.B)) ||
+		(d3 == 0 && onBox(o.A, o.B, s.A)) ||
+		(d4 == 0 && onBox(o.A, o.B, s.B))
+}
+
+// Intersection возвращает точку пересечения отрезков. Для наложенных
+// коллинеарных отрезков возвращается один из концов общей части
+func (s Segment) Intersection(o Segment) (*points.Point, bool) {
+	if !s.Intersects(o) {
+		return nil, false
+	}
+	r := VectorBetween(s.A, s.B)
+	q := VectorBetween(o.A, o.B)
+	denom := r.Cross(q)
+	if math.Abs(denom) < Epsilon {
+		for _, p := range []*points.Point{o.A, o.B, s.A, s.B} {
+			if s.Contains(p) && o.Contains(p) {
+				return p, true
+			}
+		}
+		return nil, false
+	}
+	t := VectorBetween(s.A, o.A).Cross(q) / denom
+	return r.Scale(t).Translate(s.A), true
+}
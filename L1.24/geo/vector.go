@@ -0,0 +1,68 @@
+package geo
+
+import (
+	"math"
+
+	points "L1.24/models"
+)
+
+// Epsilon допуск при сравнении координат
+const Epsilon = 1e-9
+
+// Vector вектор на плоскости
+type Vector struct {
+	X, Y float64
+}
+
+// VectorBetween возвращает вектор из a в b
+func VectorBetween(a, b *points.Point) Vector {
+	return Vector{X: b.X() - a.X(), Y: b.Y() - a.Y()}
+}
+
+// Add сумма векторов
+func (v Vector) Add(o Vector) Vector {
+	return Vector{X: v.X + o.X, Y: v.Y + o.Y}
+}
+
+// Sub разность векторов
+func (v Vector) Sub(o Vector) Vector {
+	return Vector{X: v.X - o.X, Y: v.Y - o.Y}
+}
+
+// Scale умножение на число
+func (v Vector) Scale(k float64) Vector {
+	return Vector{X: v.X * k, Y: v.Y * k}
+}
+
+// Dot скалярное произведение
+func (v Vector) Dot(o Vector) float64 {
+	return v.X*o.X + v.Y*o.Y
+}
+
+// Cross псевдоскалярное (косое) произведение: > 0, если o повёрнут против часовой стрелки от v
+func (v Vector) Cross(o Vector) float64 {
+	return v.X*o.Y - v.Y*o.X
+}
+
+// Len длина вектора
+func (v Vector) Len() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+// Translate возвращает точку p, сдвинутую на вектор v
+func (v Vector) Translate(p *points.Point) *points.Point {
+	return points.NewPoint(p.X()+v.X, p.Y()+v.Y)
+}
+
+// orientation возвращает знак поворота a -> b -> c: 1 против часовой, -1 по часовой, 0 на одной прямой
+func orientation(a, b, c *points.Point) int {
+	cross := VectorBetween(a, b).Cross(VectorBetween(a, c))
+	switch {
+	case cross > Epsilon:
+		return 1
+	case cross < -Epsilon:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -11,6 +11,16 @@ func NewPoint(x, y float64) *Point {
 	return &Point{x: x, y: y}
 }
 
+// X возвращает координату x
+func (p *Point) X() float64 {
+	return p.x
+}
+
+// Y возвращает координату y
+func (p *Point) Y() float64 {
+	return p.y
+}
+
 func (p *Point) Distance(other *Point) float64 {
 	return math.Sqrt(math.Pow(other.x-p.x, 2) + math.Pow((other.y-p.y), 2))
 }
@@ -0,0 +1,5 @@
+module L2.11
+
+go 1.24.6
+
+require golang.org/x/text v0.29.0
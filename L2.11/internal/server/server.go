@@ -0,0 +1,45 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"L2.11/pkg/anagram"
+)
+
+type lookupResponse struct {
+	Word     string   `json:"word"`
+	Anagrams []string `json:"anagrams"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHandler возвращает обработчик HTTP запросов к индексу:
+// GET /anagrams?word=... - анаграммы слова, GET /groups - все множества анаграмм
+func NewHandler(idx *anagram.Index) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /anagrams", func(w http.ResponseWriter, r *http.Request) {
+		word := r.URL.Query().Get("word")
+		if word == "" {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: "параметр word обязателен"})
+			return
+		}
+		anagrams := idx.Lookup(word)
+		if anagrams == nil {
+			anagrams = []string{}
+		}
+		writeJSON(w, http.StatusOK, lookupResponse{Word: anagram.Normalize(word), Anagrams: anagrams})
+	})
+	mux.HandleFunc("GET /groups", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, idx.Groups())
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,63 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"L2.11/internal/server"
+	"L2.11/pkg/anagram"
+)
+
+func newTestHandler() http.Handler {
+	idx := anagram.NewIndex()
+	idx.Add("пятак", "пятка", "тяпка", "стол")
+	return server.NewHandler(idx)
+}
+
+func TestLookup(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/anagrams?word="+url.QueryEscape("ПЯТАК"), nil)
+	w := httptest.NewRecorder()
+	newTestHandler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("код ответа %d", w.Code)
+	}
+	var resp struct {
+		Word     string   `json:"word"`
+		Anagrams []string `json:"anagrams"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Word != "пятак" || !reflect.DeepEqual(resp.Anagrams, []string{"пятка", "тяпка"}) {
+		t.Errorf("получили: %+v", resp)
+	}
+}
+
+func TestLookup_MissingWord(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/anagrams", nil)
+	w := httptest.NewRecorder()
+	newTestHandler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("ожидали 400, получили %d", w.Code)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/groups", nil)
+	w := httptest.NewRecorder()
+	newTestHandler().ServeHTTP(w, req)
+
+	var groups []anagram.Group
+	if err := json.NewDecoder(w.Body).Decode(&groups); err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || groups[0].Key != "пятак" {
+		t.Errorf("получили: %+v", groups)
+	}
+}
@@ -1,52 +1,51 @@
 package main
 
-//сложность - O(n * m * log(m))
+// echo "пятак пятка тяпка листок слиток столик стол" | go run main.go
+// go run main.go -dict words.txt -word пятак
+// go run main.go -dict words.txt -http :8080
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
+	"log"
+	"net/http"
 	"os"
-	"sort"
 	"strings"
-)
 
-type node struct {
-	baseWord  string
-	anagramms []string
-}
+	"L2.11/internal/server"
+	"L2.11/pkg/anagram"
+)
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	dict := flag.String("dict", "", "файл словаря, слова через пробел или по одному на строке")
+	word := flag.String("word", "", "вывести анаграммы только для этого слова")
+	addr := flag.String("http", "", "запустить HTTP сервер на адресе вместо вывода в stdout")
+	flag.Parse()
+
+	idx := anagram.NewIndex()
+	if *dict != "" {
+		if err := idx.LoadFile(*dict); err != nil {
+			log.Fatalln(err)
+		}
+	}
 
-	scanner.Scan()
-	line := scanner.Text()
-	line = strings.TrimSpace(line)
-	inputStr := strings.Fields(line)
-	if len(inputStr) == 0 {
-		return
+	if *addr != "" {
+		log.Printf("загружено слов: %d, сервер слушает %s\n", idx.Len(), *addr)
+		log.Fatalln(http.ListenAndServe(*addr, server.NewHandler(idx)))
 	}
 
-	mapa := make(map[string]*node)
-	for _, val := range inputStr { //цикл по всем словам в строке O(n)
-		sliceRune := []rune(val)
-		sort.Slice(sliceRune, func(i, j int) bool { // сортировка слайсов в цикле
-			// за O(m*log(m)) т.к. в go используется quickSort
-			return sliceRune[i] < sliceRune[j]
-		})
-		_, ok := mapa[string(sliceRune)]
-		if ok {
-			mapa[string(sliceRune)].anagramms = append(mapa[string(sliceRune)].anagramms, val)
-		} else {
-			nodeObj := &node{baseWord: val, anagramms: nil}
-			mapa[string(sliceRune)] = nodeObj
+	if *dict == "" {
+		if err := idx.Load(os.Stdin); err != nil {
+			log.Fatalln(err)
 		}
 	}
 
-	for _, val := range mapa {
-		if len(val.anagramms) == 0 {
-			continue
-		}
-		fmt.Printf("-\"%v\":[ %v ]\n", val.baseWord, val.anagramms)
+	if *word != "" {
+		fmt.Println(strings.Join(idx.Lookup(*word), " "))
+		return
 	}
 
+	for _, g := range idx.Groups() {
+		fmt.Printf("-\"%v\":[ %v ]\n", g.Key, strings.Join(g.Words, " "))
+	}
 }
@@ -0,0 +1,153 @@
+package anagram
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Group множество анаграмм. Key - первое добавленное слово множества
+type Group struct {
+	Key   string   `json:"key"`
+	Words []string `json:"words"`
+}
+
+type group struct {
+	key   string
+	words map[string]string // слово для сравнения -> слово для вывода
+}
+
+// Index хранит слова, сгруппированные по анаграммам. Безопасен для конкурентного использования
+type Index struct {
+	mu     sync.RWMutex
+	groups map[string]*group
+}
+
+// NewIndex - конструктор для Index
+func NewIndex() *Index {
+	return &Index{groups: make(map[string]*group)}
+}
+
+// Add добавляет слова в индекс. Слова, отличающиеся только регистром,
+// формой записи Unicode или ё/е, считаются одним словом
+func (idx *Index) Add(words ...string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, word := range words {
+		normalized := Normalize(word)
+		if normalized == "" {
+			continue
+		}
+		folded := fold(normalized)
+		sig := signature(folded)
+
+		g, ok := idx.groups[sig]
+		if !ok {
+			g = &group{key: normalized, words: make(map[string]string)}
+			idx.groups[sig] = g
+		}
+		if _, ok := g.words[folded]; !ok {
+			g.words[folded] = normalized
+		}
+	}
+}
+
+// Len возвращает количество различных слов в индексе
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	n := 0
+	for _, g := range idx.groups {
+		n += len(g.words)
+	}
+	return n
+}
+
+// Lookup возвращает отсортированные анаграммы слова из индекса, не включая само слово.
+// Слово не обязано присутствовать в индексе
+func (idx *Index) Lookup(word string) []string {
+	folded := fold(Normalize(word))
+	if folded == "" {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	g, ok := idx.groups[signature(folded)]
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(g.words))
+	for key, display := range g.words {
+		if key != folded {
+			result = append(result, display)
+		}
+	}
+	sortWords(result)
+	return result
+}
+
+// Groups возвращает множества из двух и более анаграмм, отсортированные по ключу.
+// Слова внутри множества тоже отсортированы
+func (idx *Index) Groups() []Group {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	result := make([]Group, 0, len(idx.groups))
+	for _, g := range idx.groups {
+		if len(g.words) < 2 {
+			continue
+		}
+		words := make([]string, 0, len(g.words))
+		for _, display := range g.words {
+			words = append(words, display)
+		}
+		sortWords(words)
+		result = append(result, Group{Key: g.key, Words: words})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return lessWord(result[i].Key, result[j].Key)
+	})
+	return result
+}
+
+// Load читает слова из r: по одному или несколько через пробел на строке
+func (idx *Index) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		idx.Add(strings.Fields(scanner.Text())...)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения словаря: %w", err)
+	}
+	return nil
+}
+
+// LoadFile загружает словарь из файла
+func (idx *Index) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть словарь: %w", err)
+	}
+	defer file.Close()
+	return idx.Load(file)
+}
+
+// lessWord сравнивает слова так, чтобы ё стояла рядом с е, а не после я
+func lessWord(a, b string) bool {
+	fa, fb := fold(a), fold(b)
+	if fa != fb {
+		return fa < fb
+	}
+	return a < b
+}
+
+func sortWords(words []string) {
+	sort.Slice(words, func(i, j int) bool {
+		return lessWord(words[i], words[j])
+	})
+}
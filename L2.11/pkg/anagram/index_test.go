@@ -0,0 +1,98 @@
+package anagram_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"L2.11/pkg/anagram"
+)
+
+func TestIndex_Groups(t *testing.T) {
+	idx := anagram.NewIndex()
+	idx.Add("пятак", "пятка", "тяпка", "листок", "слиток", "столик", "стол")
+
+	expected := []anagram.Group{
+		{Key: "листок", Words: []string{"листок", "слиток", "столик"}},
+		{Key: "пятак", Words: []string{"пятак", "пятка", "тяпка"}},
+	}
+	if got := idx.Groups(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ожидали: %v получили: %v", expected, got)
+	}
+}
+
+func TestIndex_CaseAndYo(t *testing.T) {
+	idx := anagram.NewIndex()
+	idx.Add("Ёлка", "елка", "ЁЛКА", "келА")
+
+	expected := []anagram.Group{{Key: "ёлка", Words: []string{"ёлка", "кела"}}}
+	if got := idx.Groups(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ожидали: %v получили: %v", expected, got)
+	}
+	if idx.Len() != 2 {
+		t.Errorf("ожидали 2 различных слова, получили %d", idx.Len())
+	}
+}
+
+func TestIndex_NFC(t *testing.T) {
+	idx := anagram.NewIndex()
+	// "й" в виде "и" + комбинирующий знак и в составном виде
+	idx.Add("йод", "йод", "дой")
+
+	if idx.Len() != 2 {
+		t.Errorf("ожидали 2 различных слова, получили %d", idx.Len())
+	}
+	got := idx.Lookup("йод")
+	if !reflect.DeepEqual(got, []string{"дой"}) {
+		t.Errorf("получили: %q", got)
+	}
+}
+
+func TestIndex_Lookup(t *testing.T) {
+	idx := anagram.NewIndex()
+	idx.Add("пятак", "пятка", "тяпка")
+
+	if got := idx.Lookup("Тяпка"); !reflect.DeepEqual(got, []string{"пятак", "пятка"}) {
+		t.Errorf("получили: %v", got)
+	}
+	if got := idx.Lookup("капят"); !reflect.DeepEqual(got, []string{"пятак", "пятка", "тяпка"}) {
+		t.Errorf("слово не из словаря: %v", got)
+	}
+	if got := idx.Lookup("стол"); got != nil {
+		t.Errorf("ожидали nil, получили: %v", got)
+	}
+}
+
+func TestIndex_LoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dict.txt")
+	content := "пятак\nпятка тяпка\n\n  листок \nслиток\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx := anagram.NewIndex()
+	if err := idx.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if idx.Len() != 5 {
+		t.Errorf("ожидали 5 слов, получили %d", idx.Len())
+	}
+	if err := idx.LoadFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("ожидали ошибку для несуществующего файла")
+	}
+}
+
+func TestIndex_Deterministic(t *testing.T) {
+	words := strings.Fields("тяпка пятак пятка слиток листок столик")
+	first := anagram.NewIndex()
+	first.Add(words...)
+	for i := 0; i < 20; i++ {
+		idx := anagram.NewIndex()
+		idx.Add(words...)
+		if !reflect.DeepEqual(idx.Groups(), first.Groups()) {
+			t.Fatal("порядок групп должен быть детерминированным")
+		}
+	}
+}
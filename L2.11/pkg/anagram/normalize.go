@@ -0,0 +1,29 @@
+package anagram
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize приводит слово к виду для вывода: NFC и нижний регистр (case folding)
+func Normalize(word string) string {
+	return cases.Fold().String(norm.NFC.String(strings.TrimSpace(word)))
+}
+
+// fold приводит нормализованное слово к виду для сравнения: дополнительно ё -> е
+func fold(normalized string) string {
+	return strings.ReplaceAll(normalized, "ё", "е")
+}
+
+// signature возвращает ключ группы анаграмм: отсортированные руны слова.
+// Сложность O(m*log(m)), где m - длина слова
+func signature(folded string) string {
+	runes := []rune(folded)
+	sort.Slice(runes, func(i, j int) bool {
+		return runes[i] < runes[j]
+	})
+	return string(runes)
+}
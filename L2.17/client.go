@@ -8,42 +8,55 @@ import (
 	"io"
 	"net"
 	"os"
-	"os/signal"
 	"sync"
-	"syscall"
 	"time"
 )
 
-func run(address string, timeout time.Duration) {
+// closeWriter реализуется соединениями, поддерживающими полузакрытие (TCP)
+type closeWriter interface {
+	CloseWrite() error
+}
+
+func run(ctx context.Context, address string, opts options, dump *trafficLog) error {
+	network := "tcp"
+	if opts.udp {
+		network = "udp"
+	}
+
 	dialer := &net.Dialer{}
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	dialCtx, cancel := context.WithTimeout(ctx, opts.timeout)
 	defer cancel()
 
-	conn, err := dialer.DialContext(ctx, "tcp", address)
+	conn, err := dialer.DialContext(dialCtx, network, address)
 	if err != nil {
-		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-			fmt.Fprintf(os.Stderr, "Ошибка: не удалось подключиться к %s за %s\n", address, timeout.String())
-			os.Exit(1)
+		if errors.Is(dialCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("не удалось подключиться к %s за %s", address, opts.timeout.String())
 		}
-		fmt.Fprintf(os.Stderr, "Ошибка подключения к %s: %v\n", address, err)
-		os.Exit(1)
+		return fmt.Errorf("подключение к %s: %w", address, err)
 	}
 	defer conn.Close()
 
-	var tcpConn *net.TCPConn
-	if c, ok := conn.(*net.TCPConn); ok {
-		tcpConn = c
+	if opts.timeout > 0 && !opts.udp {
+		_ = conn.SetDeadline(time.Now().Add(opts.timeout))
+	}
+
+	return bridge(ctx, dump.wrap(conn), opts)
+}
+
+// bridge связывает соединение с stdin/stdout либо с процессом из --exec
+// и возвращается, когда одна из сторон завершила обмен или пришёл сигнал
+func bridge(ctx context.Context, conn net.Conn, opts options) error {
+	if opts.execCmd != "" {
+		return attachExec(ctx, conn, opts.execCmd)
 	}
 
 	done := make(chan struct{})
 	var once sync.Once
 	closeDone := func() { once.Do(func() { close(done) }) }
 
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		select {
-		case <-sigs:
+		case <-ctx.Done():
 			_ = conn.Close()
 			closeDone()
 		case <-done:
@@ -58,21 +71,40 @@ func run(address string, timeout time.Duration) {
 		_, _ = io.Copy(writer, conn)
 	}()
 
-	reader := bufio.NewReader(os.Stdin)
-	if timeout > 0 {
-		_ = conn.SetDeadline(time.Now().Add(timeout))
+	inputDone := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReader(os.Stdin)
+		_, err := io.Copy(conn, reader)
+		inputDone <- err
+	}()
+
+	var err error
+	select {
+	case <-done:
+		return nil
+	case err = <-inputDone:
 	}
-	_, err = io.Copy(conn, reader)
-	if err == nil || errors.Is(err, io.EOF) {
-		if tcpConn != nil {
-			_ = tcpConn.CloseWrite()
-		} else {
-			_ = conn.Close()
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		_ = conn.Close()
+		<-done
+		return nil
+	}
+
+	if cw, ok := conn.(closeWriter); ok {
+		_ = cw.CloseWrite()
+		select {
+		case <-done:
+		case <-time.After(100 * time.Millisecond):
 		}
+		return nil
 	}
 
-	select {
-	case <-done:
-	case <-time.After(100 * time.Millisecond):
+	// у UDP нет признака конца потока: ждём ответы не дольше timeout,
+	// а в режиме --keep-open продолжаем принимать данные до сигнала
+	if !opts.keepOpen && opts.timeout > 0 {
+		_ = conn.SetReadDeadline(time.Now().Add(opts.timeout))
 	}
+	<-done
+	return nil
 }
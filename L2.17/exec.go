@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// attachExec запускает команду, подключая её stdin и stdout к соединению.
+// Соединение закрывается после завершения процесса
+func attachExec(ctx context.Context, conn net.Conn, command string) error {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return errors.New("пустая команда для --exec")
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = conn
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("ошибка подготовки команды %q: %w", command, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("ошибка запуска команды %q: %w", command, err)
+	}
+
+	go func() {
+		_, _ = io.Copy(stdin, conn)
+		_ = stdin.Close()
+	}()
+
+	err = cmd.Wait()
+	_ = conn.Close()
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("команда %q завершилась с ошибкой: %w", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// trafficLog пишет hex-дамп переданных и полученных данных.
+// Нулевой *trafficLog допустим и ничего не записывает
+type trafficLog struct {
+	mu sync.Mutex
+	w  io.WriteCloser
+}
+
+// openTrafficLog открывает файл для дампа; при пустом пути возвращает nil
+func openTrafficLog(path string) (*trafficLog, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть файл дампа %s: %w", path, err)
+	}
+	return &trafficLog{w: f}, nil
+}
+
+// Close закрывает файл дампа
+func (l *trafficLog) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Close()
+}
+
+// record записывает один блок данных: ">" — отправлено, "<" — получено
+func (l *trafficLog) record(direction string, peer net.Addr, p []byte) {
+	if l == nil || len(p) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.w, "%s %s %s %d bytes\n", time.Now().Format(time.RFC3339Nano), direction, peer, len(p))
+	fmt.Fprint(l.w, hex.Dump(p))
+}
+
+// wrap возвращает соединение, трафик которого попадает в дамп
+func (l *trafficLog) wrap(conn net.Conn) net.Conn {
+	if l == nil {
+		return conn
+	}
+	lc := &loggedConn{Conn: conn, log: l}
+	if _, ok := conn.(closeWriter); ok {
+		return &loggedStreamConn{lc}
+	}
+	return lc
+}
+
+type loggedConn struct {
+	net.Conn
+	log *trafficLog
+}
+
+func (c *loggedConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.log.record("<", c.RemoteAddr(), p[:n])
+	return n, err
+}
+
+func (c *loggedConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.log.record(">", c.RemoteAddr(), p[:n])
+	return n, err
+}
+
+// loggedStreamConn сохраняет возможность полузакрытия TCP-соединения
+type loggedStreamConn struct {
+	*loggedConn
+}
+
+func (c *loggedStreamConn) CloseWrite() error {
+	return c.Conn.(closeWriter).CloseWrite()
+}
@@ -1,34 +1,86 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 )
 
+// options хранит флаги запуска утилиты
+type options struct {
+	timeout  time.Duration
+	listen   string // -l, порт для режима сервера
+	udp      bool   // -u
+	keepOpen bool   // --keep-open
+	execCmd  string // --exec
+	hexdump  string // --hexdump
+}
+
 func usage() {
-	fmt.Fprintf(os.Stderr, "Usage: %s [--timeout=10s] host port\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "Usage: %s [--timeout=10s] [-u] [--exec=cmd] [--hexdump=file] host port\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s -l port [-u] [--keep-open] [--exec=cmd] [--hexdump=file] [bind_host]\n", os.Args[0])
 	fmt.Fprintln(os.Stderr, "Example: telnet --timeout=5s smtp.gmail.com 25")
+	fmt.Fprintln(os.Stderr, "Example: telnet -l 8080 --keep-open --exec=\"cat\"")
 	flag.PrintDefaults()
 }
 
 func main() {
-	timeout := flag.Duration("timeout", 10*time.Second, "")
+	var opts options
+	flag.DurationVar(&opts.timeout, "timeout", 10*time.Second, "")
+	flag.StringVar(&opts.listen, "l", "", "слушать указанный порт вместо подключения к серверу")
+	flag.BoolVar(&opts.udp, "u", false, "использовать UDP вместо TCP")
+	flag.BoolVar(&opts.keepOpen, "keep-open", false, "в режиме -l принимать несколько соединений, не завершаясь после первого")
+	flag.StringVar(&opts.execCmd, "exec", "", "запустить команду и подключить её stdin/stdout к соединению")
+	flag.StringVar(&opts.hexdump, "hexdump", "", "записывать hex-дамп трафика в указанный файл")
 	flag.Usage = usage
 	flag.Parse()
 
 	args := flag.Args()
-	if len(args) != 2 {
-		usage()
-		os.Exit(2)
+	var address string
+	if opts.listen != "" {
+		if len(args) > 1 {
+			usage()
+			os.Exit(2)
+		}
+		host := ""
+		if len(args) == 1 {
+			host = strings.TrimSpace(args[0])
+		}
+		address = net.JoinHostPort(host, strings.TrimSpace(opts.listen))
+	} else {
+		if len(args) != 2 || opts.keepOpen {
+			usage()
+			os.Exit(2)
+		}
+		host := strings.TrimSpace(args[0])
+		port := strings.TrimSpace(args[1])
+		address = net.JoinHostPort(host, port)
+	}
+
+	dump, err := openTrafficLog(opts.hexdump)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		os.Exit(1)
 	}
+	defer dump.Close()
 
-	host := strings.TrimSpace(args[0])
-	port := strings.TrimSpace(args[1])
-	address := net.JoinHostPort(host, port)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	run(address, *timeout)
+	if opts.listen != "" {
+		err = listen(ctx, address, opts, dump)
+	} else {
+		err = run(ctx, address, opts, dump)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		dump.Close()
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+)
+
+// listen запускает режим сервера (-l): принимает одно соединение,
+// либо, с --keep-open, обслуживает соединения до получения сигнала
+func listen(ctx context.Context, address string, opts options, dump *trafficLog) error {
+	if opts.udp {
+		return listenUDP(ctx, address, opts, dump)
+	}
+
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("не удалось слушать %s: %w", address, err)
+	}
+	defer ln.Close()
+	fmt.Fprintf(os.Stderr, "Ожидание подключений на %s\n", ln.Addr())
+
+	stop := context.AfterFunc(ctx, func() { _ = ln.Close() })
+	defer stop()
+
+	if !opts.keepOpen {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("ошибка приёма соединения: %w", err)
+		}
+		_ = ln.Close()
+		defer conn.Close()
+		fmt.Fprintf(os.Stderr, "Подключение от %s\n", conn.RemoteAddr())
+		return bridge(ctx, dump.wrap(conn), opts)
+	}
+
+	h := newHub()
+	if opts.execCmd == "" {
+		go func() { _, _ = io.Copy(h, os.Stdin) }()
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("ошибка приёма соединения: %w", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serveConn(ctx, dump.wrap(conn), opts, h)
+		}()
+	}
+}
+
+// serveConn обслуживает одно соединение в режиме --keep-open
+func serveConn(ctx context.Context, conn net.Conn, opts options, h *hub) {
+	defer conn.Close()
+	peer := conn.RemoteAddr()
+	fmt.Fprintf(os.Stderr, "Подключение от %s\n", peer)
+	defer fmt.Fprintf(os.Stderr, "Соединение с %s закрыто\n", peer)
+
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	if opts.execCmd != "" {
+		if err := attachExec(ctx, conn, opts.execCmd); err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		}
+		return
+	}
+
+	h.add(conn)
+	defer h.remove(conn)
+	_, _ = io.Copy(stdout, conn)
+}
+
+// hub рассылает данные из stdin всем активным соединениям
+type hub struct {
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+func newHub() *hub {
+	return &hub{conns: make(map[net.Conn]struct{})}
+}
+
+func (h *hub) add(conn net.Conn) {
+	h.mu.Lock()
+	h.conns[conn] = struct{}{}
+	h.mu.Unlock()
+}
+
+func (h *hub) remove(conn net.Conn) {
+	h.mu.Lock()
+	delete(h.conns, conn)
+	h.mu.Unlock()
+}
+
+// Write отправляет p во все соединения, ошибки отдельных соединений игнорируются
+func (h *hub) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for conn := range h.conns {
+		_, _ = conn.Write(p)
+	}
+	return len(p), nil
+}
+
+// syncWriter сериализует запись из нескольких соединений в один поток
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+var stdout io.Writer = &syncWriter{w: os.Stdout}
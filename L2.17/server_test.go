@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHub_BroadcastsToAllConns(t *testing.T) {
+	h := newHub()
+	a1, a2 := net.Pipe()
+	b1, b2 := net.Pipe()
+	defer a1.Close()
+	defer b1.Close()
+	h.add(a1)
+	h.add(b1)
+
+	results := make(chan string, 2)
+	for _, c := range []net.Conn{a2, b2} {
+		go func(c net.Conn) {
+			buf := make([]byte, 8)
+			n, _ := c.Read(buf)
+			results <- string(buf[:n])
+		}(c)
+	}
+
+	if _, err := h.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if got := <-results; got != "ping" {
+			t.Errorf("got=%q, want=%q", got, "ping")
+		}
+	}
+
+	h.remove(b1)
+	if len(h.conns) != 1 {
+		t.Errorf("ожидали 1 соединение после remove, получили %d", len(h.conns))
+	}
+}
+
+func TestAttachExec_EchoesThroughProcess(t *testing.T) {
+	if _, err := os.Stat("/bin/cat"); err != nil {
+		t.Skip("нет /bin/cat")
+	}
+	server, client := net.Pipe()
+	defer client.Close()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- attachExec(context.Background(), server, "/bin/cat") }()
+
+	go func() {
+		_, _ = client.Write([]byte("hello\n"))
+	}()
+	buf := make([]byte, 6)
+	_ = client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := io.ReadFull(client, buf); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(buf) != "hello\n" {
+		t.Errorf("got=%q", buf)
+	}
+	client.Close()
+	select {
+	case <-errCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("attachExec не завершился после закрытия соединения")
+	}
+}
+
+func TestTrafficLog_Records(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.txt")
+	dump, err := openTrafficLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := net.Pipe()
+	defer b.Close()
+	conn := dump.wrap(a)
+	go func() { _, _ = io.Copy(io.Discard, b) }()
+	if _, err := conn.Write([]byte("AB")); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	dump.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("41 42")) || !strings.Contains(string(data), "> pipe 2 bytes") {
+		t.Errorf("неожиданный дамп:\n%s", data)
+	}
+}
+
+func TestTrafficLog_NilIsNoop(t *testing.T) {
+	dump, err := openTrafficLog("")
+	if err != nil || dump != nil {
+		t.Fatalf("ожидали nil без ошибки, получили %v %v", dump, err)
+	}
+	a, b := net.Pipe()
+	defer a.Close()
+	defer b.Close()
+	if dump.wrap(a) != a {
+		t.Error("nil trafficLog не должен оборачивать соединение")
+	}
+}
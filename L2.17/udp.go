@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+)
+
+const maxDatagramSize = 64 * 1024
+
+// listenUDP ждёт первую датаграмму и связывает её отправителя со stdin/stdout.
+// С --keep-open принимаются датаграммы от любых клиентов, а ответы уходят
+// последнему отправителю
+func listenUDP(ctx context.Context, address string, opts options, dump *trafficLog) error {
+	if opts.keepOpen && opts.execCmd != "" {
+		return errors.New("--exec вместе с --keep-open не поддерживается в режиме UDP")
+	}
+
+	pc, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return fmt.Errorf("не удалось слушать %s: %w", address, err)
+	}
+	defer pc.Close()
+	fmt.Fprintf(os.Stderr, "Ожидание датаграмм на %s\n", pc.LocalAddr())
+
+	stop := context.AfterFunc(ctx, func() { _ = pc.Close() })
+	defer stop()
+
+	buf := make([]byte, maxDatagramSize)
+	n, peer, err := pc.ReadFrom(buf)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("ошибка чтения датаграммы: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Датаграмма от %s\n", peer)
+
+	conn := newUDPPeerConn(pc, peer, buf[:n], opts.keepOpen)
+	return bridge(ctx, dump.wrap(conn), opts)
+}
+
+// udpPeerConn представляет обмен с одним UDP-клиентом как net.Conn
+type udpPeerConn struct {
+	net.PacketConn
+
+	mu      sync.Mutex
+	peer    net.Addr
+	pending []byte
+	anyPeer bool
+	readBuf []byte
+}
+
+func newUDPPeerConn(pc net.PacketConn, peer net.Addr, first []byte, anyPeer bool) *udpPeerConn {
+	return &udpPeerConn{
+		PacketConn: pc,
+		peer:       peer,
+		pending:    append([]byte(nil), first...),
+		anyPeer:    anyPeer,
+		readBuf:    make([]byte, maxDatagramSize),
+	}
+}
+
+// Read возвращает данные очередной датаграммы. Датаграммы от посторонних
+// адресов отбрасываются, если не включён режим anyPeer
+func (c *udpPeerConn) Read(p []byte) (int, error) {
+	if len(c.pending) > 0 {
+		n := copy(p, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+	for {
+		n, addr, err := c.PacketConn.ReadFrom(c.readBuf)
+		if err != nil {
+			return 0, err
+		}
+		c.mu.Lock()
+		same := addr.String() == c.peer.String()
+		if !same && c.anyPeer {
+			c.peer = addr
+			same = true
+		}
+		c.mu.Unlock()
+		if !same {
+			continue
+		}
+		m := copy(p, c.readBuf[:n])
+		c.pending = c.readBuf[m:n]
+		return m, nil
+	}
+}
+
+// Write отправляет p текущему клиенту одной датаграммой
+func (c *udpPeerConn) Write(p []byte) (int, error) {
+	return c.PacketConn.WriteTo(p, c.RemoteAddr())
+}
+
+func (c *udpPeerConn) RemoteAddr() net.Addr {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.peer
+}
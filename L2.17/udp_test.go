@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func listenLocalUDP(t *testing.T) net.PacketConn {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket: %v", err)
+	}
+	t.Cleanup(func() { pc.Close() })
+	return pc
+}
+
+func TestUDPPeerConn_FirstPeerOnly(t *testing.T) {
+	server := listenLocalUDP(t)
+	first := listenLocalUDP(t)
+	other := listenLocalUDP(t)
+
+	conn := newUDPPeerConn(server, first.LocalAddr(), []byte("hello"), false)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	buf := make([]byte, 16)
+	n, err := conn.Read(buf)
+	if err != nil || string(buf[:n]) != "hello" {
+		t.Fatalf("первая датаграмма: got=%q err=%v", buf[:n], err)
+	}
+
+	if _, err := other.WriteTo([]byte("spam"), server.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.WriteTo([]byte("ok"), server.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	n, err = conn.Read(buf)
+	if err != nil || string(buf[:n]) != "ok" {
+		t.Fatalf("ожидали датаграмму от первого клиента: got=%q err=%v", buf[:n], err)
+	}
+
+	if _, err := conn.Write([]byte("reply")); err != nil {
+		t.Fatal(err)
+	}
+	_ = first.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err = first.ReadFrom(buf)
+	if err != nil || string(buf[:n]) != "reply" {
+		t.Fatalf("ответ клиенту: got=%q err=%v", buf[:n], err)
+	}
+}
+
+func TestUDPPeerConn_AnyPeerSwitchesReplies(t *testing.T) {
+	server := listenLocalUDP(t)
+	first := listenLocalUDP(t)
+	second := listenLocalUDP(t)
+
+	conn := newUDPPeerConn(server, first.LocalAddr(), nil, true)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	if _, err := second.WriteTo([]byte("hi"), server.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 16)
+	n, err := conn.Read(buf)
+	if err != nil || string(buf[:n]) != "hi" {
+		t.Fatalf("got=%q err=%v", buf[:n], err)
+	}
+	if conn.RemoteAddr().String() != second.LocalAddr().String() {
+		t.Fatalf("ожидали переключение на %s, получили %s", second.LocalAddr(), conn.RemoteAddr())
+	}
+}
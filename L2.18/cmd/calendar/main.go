@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"calendar/internal/app"
 	"calendar/internal/config"
@@ -13,14 +18,26 @@ func main() {
 
 	APIConfig := config.LoadAPIConfig("../../environment/.env")
 
-	server, err := app.NewServer("../../environment/.env")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server, err := app.NewServer(ctx, "../../environment/.env")
 	if err != nil {
 		log.Panicln(err)
 	}
 
+	httpServer := &http.Server{Addr: ":" + APIConfig.Port, Handler: server}
+	// по сигналу останавливаем и сервер, и планировщик напоминаний
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
 	log.Println("Запускаем сервер на порту ", APIConfig.Port)
-	err = http.ListenAndServe(":"+APIConfig.Port, server)
-	if err != nil {
+	err = httpServer.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
 		log.Panicln("запуск сервера не удался", err)
 	}
 
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"calendar/internal/auth"
+	"calendar/internal/config"
+	"calendar/internal/repository/postgres"
+)
+
+// token выпускает API-токен пользователю и печатает его; в БД сохраняется только хэш.
+// С -revoke отзывает ранее выпущенный токен
+func main() {
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	userID := flag.Int("user", 0, "id пользователя, которому выпускается токен")
+	name := flag.String("name", "", "название токена, например имя клиента")
+	ttl := flag.Duration("ttl", 0, "срок действия токена, 0 - бессрочный")
+	revoke := flag.String("revoke", "", "отозвать указанный токен вместо выпуска нового")
+	envPath := flag.String("env", "../../environment/.env", "путь к .env с настройками БД")
+	flag.Parse()
+
+	if *revoke == "" && *userID <= 0 {
+		log.Fatalln("нужно указать -user или -revoke")
+	}
+
+	DBConfig, err := config.LoadDBPSQLConfig(*envPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	repo, err := postgres.NewPostgresStorage(DBConfig)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if *revoke != "" {
+		if err := repo.RevokeToken(context.Background(), auth.HashToken(*revoke)); err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Println("токен отозван")
+		return
+	}
+
+	token, hash, err := auth.NewToken()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	var expiresAt *time.Time
+	if *ttl > 0 {
+		t := time.Now().Add(*ttl)
+		expiresAt = &t
+	}
+	if err := repo.CreateToken(context.Background(), *userID, hash, *name, expiresAt); err != nil {
+		log.Fatalln(err)
+	}
+
+	fmt.Println(token)
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -13,8 +14,9 @@ import (
 	"calendar/internal/service"
 )
 
-// NewServer содержит логику для создания сервера чтобы вынести ее из main
-func NewServer(envPath string) (http.Handler, error) {
+// NewServer содержит логику для создания сервера чтобы вынести ее из main.
+// Вместе с сервером запускается планировщик напоминаний, он работает до отмены ctx
+func NewServer(ctx context.Context, envPath string) (http.Handler, error) {
 	DBConfig, err := config.LoadDBPSQLConfig(envPath)
 	if err != nil {
 		return nil, err
@@ -25,6 +27,19 @@ func NewServer(envPath string) (http.Handler, error) {
 		return nil, err
 	}
 
+	reminderConfig, err := config.LoadReminderConfig(envPath)
+	if err != nil {
+		return nil, err
+	}
+	reminderNotifier, err := NewNotifier(reminderConfig)
+	if err != nil {
+		return nil, err
+	}
+	scheduler, err := NewScheduler(repo, reminderNotifier, reminderConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	svc, err := service.NewDefaultService(repo)
 	if err != nil {
 		return nil, err
@@ -38,12 +53,43 @@ func NewServer(envPath string) (http.Handler, error) {
 	router := chi.NewRouter()
 	router.Use(middleware.LoggingMiddleware)
 
-	router.Post("/create_event", handler.CreateEvent)
-	router.Post("/update_event", handler.UpdateEvent)
-	router.Post("/delete_event", handler.DeleteEvent)
-	router.Get("/events_for_day", handler.EventsForDay)
-	router.Get("/events_for_week", handler.EventsForWeek)
-	router.Get("/events_for_month", handler.EventsForMonth)
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(repo, false))
+
+		r.Post("/create_event", handler.CreateEvent)
+		r.Post("/update_event", handler.UpdateEvent)
+		r.Post("/delete_event", handler.DeleteEvent)
+		r.Get("/events_for_day", handler.EventsForDay)
+		r.Get("/events_for_week", handler.EventsForWeek)
+		r.Get("/events_for_month", handler.EventsForMonth)
+		r.Post("/import", handler.ImportCalendar)
+		r.Post("/users/{id}/time_zone", handler.SetTimeZone)
+		r.Get("/free_busy", handler.FreeBusy)
+		r.Get("/events/search", handler.SearchEvents)
+		r.Post("/events/{id}/rsvp", handler.RespondToEvent)
+		r.Get("/users/{id}/shares", handler.CalendarShares)
+		r.Post("/users/{id}/shares", handler.ShareCalendar)
+		r.Delete("/users/{id}/shares/{user_id}", handler.UnshareCalendar)
+
+		r.Route("/v2/events", func(r chi.Router) {
+			r.Get("/", handler.ListEventsV2)
+			r.Post("/", handler.CreateEventV2)
+			r.Get("/{id}", handler.GetEventV2)
+			r.Patch("/{id}", handler.PatchEventV2)
+			r.Delete("/{id}", handler.DeleteEventV2)
+		})
+	})
+
+	// календарные клиенты подписываются по ссылке, поэтому токен допускается в ?token=
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(repo, true))
+
+		r.Get("/users/{id}/calendar.ics", handler.ExportCalendar)
+	})
+
+	router.Get("/openapi.json", handler.OpenAPI)
+
+	go scheduler.Run(ctx)
 
 	return router, nil
 }
@@ -0,0 +1,200 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"calendar/internal/config"
+	"calendar/internal/models"
+	"calendar/internal/notifier"
+	"calendar/internal/recurrence"
+)
+
+// notifyTimeout ограничение на отправку одного напоминания
+const notifyTimeout = 30 * time.Second
+
+// maxRetryDelay верхняя граница паузы между повторными попытками отправки
+const maxRetryDelay = 30 * time.Minute
+
+// ReminderStorage методы хранилища, которые нужны планировщику напоминаний
+type ReminderStorage interface {
+	ClaimReminders(ctx context.Context, limit int, lease time.Duration) ([]*models.DueReminder, error)
+	SaveReminder(ctx context.Context, r *models.Reminder) error
+	RecordDelivery(ctx context.Context, d *models.ReminderDelivery) error
+}
+
+// Scheduler периодически забирает из БД напоминания, которые пора отправить, доставляет их
+// через Notifier и планирует следующее вхождение. Напоминания захватываются с арендой,
+// поэтому планировщик можно запускать в нескольких экземплярах сервиса
+type Scheduler struct {
+	repo        ReminderStorage
+	notifier    notifier.Notifier
+	interval    time.Duration
+	batch       int
+	maxAttempts int
+	lease       time.Duration
+	now         func() time.Time
+}
+
+// NewScheduler создаёт планировщик напоминаний
+func NewScheduler(repo ReminderStorage, n notifier.Notifier, cfg *config.ReminderConfig) (*Scheduler, error) {
+	if repo == nil || n == nil || cfg == nil {
+		return nil, fmt.Errorf("хранилище, канал доставки и настройки планировщика не должны быть nil")
+	}
+	return &Scheduler{
+		repo:        repo,
+		notifier:    n,
+		interval:    cfg.PollInterval,
+		batch:       cfg.BatchSize,
+		maxAttempts: cfg.MaxAttempts,
+		// аренды хватает, чтобы отправить всю порцию, даже если каждая отправка упрётся в таймаут
+		lease: time.Duration(cfg.BatchSize)*notifyTimeout + cfg.PollInterval,
+		now:   time.Now,
+	}, nil
+}
+
+// NewNotifier создаёт канал доставки напоминаний, выбранный в настройках
+func NewNotifier(cfg *config.ReminderConfig) (notifier.Notifier, error) {
+	switch cfg.Notifier {
+	case "", "log":
+		return notifier.NewLogNotifier(nil), nil
+	case "webhook":
+		return notifier.NewWebhookNotifier(cfg.WebhookURL, nil)
+	case "smtp":
+		return notifier.NewSMTPNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPFrom)
+	default:
+		return nil, fmt.Errorf("неизвестный канал доставки напоминаний %q", cfg.Notifier)
+	}
+}
+
+// Run обрабатывает напоминания каждые interval, пока не отменён ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		// полная порция значит, что в очереди могут остаться напоминания, их берём сразу
+		for {
+			n, err := s.Tick(ctx)
+			if err != nil {
+				log.Println("планировщик напоминаний:", err)
+			}
+			if err != nil || n < s.batch || ctx.Err() != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick захватывает и обрабатывает одну порцию напоминаний, возвращает их число
+func (s *Scheduler) Tick(ctx context.Context) (int, error) {
+	due, err := s.repo.ClaimReminders(ctx, s.batch, s.lease)
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range due {
+		if err := s.process(ctx, d); err != nil {
+			log.Printf("напоминание %d: %v", d.ID, err)
+		}
+	}
+	return len(due), nil
+}
+
+// process отправляет напоминание, если подошло его время, и рассчитывает следующее
+func (s *Scheduler) process(ctx context.Context, d *models.DueReminder) error {
+	now := s.now()
+	r := d.Reminder
+
+	if r.FireAt != nil && r.OccurrenceAt != nil && !r.FireAt.After(now) {
+		// о начавшемся событии напоминать поздно, например если сервис долго был остановлен
+		if r.OccurrenceAt.After(now) {
+			err := s.deliver(ctx, d)
+			if err != nil && r.Attempts+1 < s.maxAttempts {
+				r.Attempts++
+				retry := now.Add(retryDelay(r.Attempts))
+				r.FireAt = &retry
+				return s.repo.SaveReminder(ctx, &r)
+			}
+		}
+		r.LastOccurrenceAt = r.OccurrenceAt
+		r.Attempts = 0
+	}
+
+	s.plan(&r, d, now)
+	return s.repo.SaveReminder(ctx, &r)
+}
+
+// plan рассчитывает время отправки для следующего вхождения, о котором ещё не напоминали
+func (s *Scheduler) plan(r *models.Reminder, d *models.DueReminder, now time.Time) {
+	loc, err := time.LoadLocation(d.UserTimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	after := now
+	if r.LastOccurrenceAt != nil && r.LastOccurrenceAt.After(after) {
+		after = *r.LastOccurrenceAt
+	}
+
+	start, ok := recurrence.NextStart(d.Event, d.Overridden, after, loc)
+	if !ok {
+		r.FireAt, r.OccurrenceAt, r.Done = nil, nil, true
+		return
+	}
+	fireAt := start.Add(-time.Duration(r.Offset))
+	r.FireAt, r.OccurrenceAt, r.Done = &fireAt, &start, false
+}
+
+// deliver отправляет напоминание и записывает попытку в журнал
+func (s *Scheduler) deliver(ctx context.Context, d *models.DueReminder) error {
+	n := notifier.Notification{
+		ReminderID: d.ID,
+		EventID:    d.EventID,
+		UserID:     d.Event.UserID,
+		Email:      d.UserEmail,
+		Title:      d.Event.Title,
+		Start:      *d.OccurrenceAt,
+		AllDay:     d.Event.AllDay,
+		Before:     d.Offset,
+	}
+	// время показывается в поясе события, а дата события на весь день - в поясе пользователя
+	tz := d.Event.TimeZone
+	if d.Event.AllDay {
+		tz = d.UserTimeZone
+	}
+	if loc, err := time.LoadLocation(tz); err == nil {
+		n.Start = n.Start.In(loc)
+	}
+
+	notifyCtx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	err := s.notifier.Notify(notifyCtx, n)
+	cancel()
+
+	delivery := &models.ReminderDelivery{
+		ReminderID:   d.ID,
+		OccurrenceAt: *d.OccurrenceAt,
+		Attempt:      d.Attempts + 1,
+		Notifier:     s.notifier.Name(),
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	if recErr := s.repo.RecordDelivery(ctx, delivery); recErr != nil {
+		log.Printf("напоминание %d: %v", d.ID, recErr)
+	}
+	return err
+}
+
+// retryDelay пауза перед attempt-й повторной попыткой: 1, 2, 4... минуты, но не больше maxRetryDelay
+func retryDelay(attempt int) time.Duration {
+	delay := time.Minute << (attempt - 1)
+	if delay <= 0 || delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
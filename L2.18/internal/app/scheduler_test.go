@@ -0,0 +1,144 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"calendar/internal/config"
+	"calendar/internal/models"
+	"calendar/internal/notifier"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReminderStorage хранит одно напоминание в памяти
+type fakeReminderStorage struct {
+	due        *models.DueReminder
+	saved      []models.Reminder
+	deliveries []*models.ReminderDelivery
+}
+
+func (f *fakeReminderStorage) ClaimReminders(ctx context.Context, limit int, lease time.Duration) ([]*models.DueReminder, error) {
+	return []*models.DueReminder{f.due}, nil
+}
+
+func (f *fakeReminderStorage) SaveReminder(ctx context.Context, r *models.Reminder) error {
+	f.saved = append(f.saved, *r)
+	f.due.Reminder = *r
+	return nil
+}
+
+func (f *fakeReminderStorage) RecordDelivery(ctx context.Context, d *models.ReminderDelivery) error {
+	f.deliveries = append(f.deliveries, d)
+	return nil
+}
+
+type fakeNotifier struct {
+	err  error
+	sent []notifier.Notification
+}
+
+func (f *fakeNotifier) Name() string { return "fake" }
+
+func (f *fakeNotifier) Notify(ctx context.Context, n notifier.Notification) error {
+	f.sent = append(f.sent, n)
+	return f.err
+}
+
+func newTestScheduler(t *testing.T, repo ReminderStorage, n notifier.Notifier, now *time.Time) *Scheduler {
+	s, err := NewScheduler(repo, n, &config.ReminderConfig{PollInterval: time.Second, BatchSize: 10, MaxAttempts: 2})
+	require.NoError(t, err)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestScheduler_SeriesReminder(t *testing.T) {
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	event := &models.Event{
+		ID:       1,
+		UserID:   1,
+		Title:    "Стендап",
+		Start:    time.Date(2025, 10, 20, 10, 0, 0, 0, moscow),
+		End:      time.Date(2025, 10, 20, 10, 15, 0, 0, moscow),
+		TimeZone: "Europe/Moscow",
+		RRule:    "FREQ=DAILY;COUNT=2",
+	}
+	repo := &fakeReminderStorage{due: &models.DueReminder{
+		Reminder:     models.Reminder{ID: 7, EventID: 1, Offset: models.ReminderOffset(15 * time.Minute)},
+		Event:        event,
+		UserTimeZone: "Europe/Moscow",
+	}}
+	n := &fakeNotifier{}
+	now := time.Date(2025, 10, 19, 12, 0, 0, 0, moscow)
+	s := newTestScheduler(t, repo, n, &now)
+
+	// первый проход только рассчитывает время отправки
+	_, err := s.Tick(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, repo.due.FireAt)
+	assert.Equal(t, time.Date(2025, 10, 20, 9, 45, 0, 0, moscow), *repo.due.FireAt)
+	assert.Empty(t, n.sent)
+
+	now = time.Date(2025, 10, 20, 9, 46, 0, 0, moscow)
+	_, err = s.Tick(context.Background())
+	require.NoError(t, err)
+	require.Len(t, n.sent, 1)
+	assert.Equal(t, "Стендап", n.sent[0].Title)
+	require.Len(t, repo.deliveries, 1)
+	assert.Empty(t, repo.deliveries[0].Error)
+	// следующее напоминание - о втором вхождении
+	assert.Equal(t, time.Date(2025, 10, 21, 9, 45, 0, 0, moscow), *repo.due.FireAt)
+
+	now = time.Date(2025, 10, 21, 9, 50, 0, 0, moscow)
+	_, err = s.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, n.sent, 2)
+	assert.True(t, repo.due.Done, "у серии больше нет вхождений")
+}
+
+func TestScheduler_RetryAndGiveUp(t *testing.T) {
+	start := time.Date(2025, 10, 20, 10, 0, 0, 0, time.UTC)
+	fireAt := start.Add(-time.Hour)
+	repo := &fakeReminderStorage{due: &models.DueReminder{
+		Reminder: models.Reminder{ID: 7, EventID: 1, Offset: models.ReminderOffset(time.Hour), FireAt: &fireAt, OccurrenceAt: &start},
+		Event:    &models.Event{ID: 1, UserID: 1, Title: "Встреча", Start: start, End: start, TimeZone: "UTC"},
+	}}
+	n := &fakeNotifier{err: errors.New("недоступен")}
+	now := fireAt
+	s := newTestScheduler(t, repo, n, &now)
+
+	_, err := s.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.due.Attempts)
+	assert.Equal(t, now.Add(time.Minute), *repo.due.FireAt)
+
+	now = now.Add(time.Minute)
+	_, err = s.Tick(context.Background())
+	require.NoError(t, err)
+	require.Len(t, repo.deliveries, 2)
+	assert.Equal(t, 2, repo.deliveries[1].Attempt)
+	assert.Equal(t, "недоступен", repo.deliveries[1].Error)
+	// попытки исчерпаны, у разового события других вхождений нет
+	assert.True(t, repo.due.Done)
+	assert.Equal(t, start, *repo.due.LastOccurrenceAt)
+}
+
+func TestScheduler_SkipStarted(t *testing.T) {
+	start := time.Date(2025, 10, 20, 10, 0, 0, 0, time.UTC)
+	fireAt := start.Add(-15 * time.Minute)
+	repo := &fakeReminderStorage{due: &models.DueReminder{
+		Reminder: models.Reminder{ID: 7, EventID: 1, Offset: models.ReminderOffset(15 * time.Minute), FireAt: &fireAt, OccurrenceAt: &start},
+		Event:    &models.Event{ID: 1, UserID: 1, Title: "Встреча", Start: start, End: start, TimeZone: "UTC"},
+	}}
+	n := &fakeNotifier{}
+	now := start.Add(time.Hour)
+	s := newTestScheduler(t, repo, n, &now)
+
+	_, err := s.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, n.sent, "о начавшемся событии не напоминаем")
+	assert.True(t, repo.due.Done)
+}
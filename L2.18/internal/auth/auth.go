@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// tokenPrefix помогает отличать токены календаря от прочих секретов, например в логах
+const tokenPrefix = "cal_"
+
+type userKey struct{}
+
+// WithUser возвращает контекст с аутентифицированным пользователем
+func WithUser(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// UserID возвращает пользователя, положенного в контекст middleware аутентификации
+func UserID(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(userKey{}).(int)
+	return id, ok && id > 0
+}
+
+// NewToken генерирует новый токен. Пользователю отдаётся token, в БД хранится только hash
+func NewToken() (token, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("не удалось сгенерировать токен: %w", err)
+	}
+	token = tokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashToken(token), nil
+}
+
+// HashToken возвращает хэш токена, по которому он ищется в БД
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// ReminderConfig настройки планировщика и канала доставки напоминаний
+type ReminderConfig struct {
+	// Notifier канал доставки: log, webhook или smtp
+	Notifier     string
+	PollInterval time.Duration
+	BatchSize    int
+	// MaxAttempts сколько раз пытаться отправить одно напоминание
+	MaxAttempts int
+
+	WebhookURL string
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+	SMTPFrom     string
+}
+
+// LoadReminderConfig возвращает настройки напоминаний, по умолчанию они пишутся в лог
+func LoadReminderConfig(path string) (*ReminderConfig, error) {
+	_ = godotenv.Load(path)
+
+	interval, err := time.ParseDuration(getEnvHelper("REMINDER_POLL_INTERVAL", "10s"))
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("некорректный REMINDER_POLL_INTERVAL")
+	}
+	batch, err := strconv.Atoi(getEnvHelper("REMINDER_BATCH_SIZE", "100"))
+	if err != nil || batch <= 0 {
+		return nil, fmt.Errorf("некорректный REMINDER_BATCH_SIZE")
+	}
+	attempts, err := strconv.Atoi(getEnvHelper("REMINDER_MAX_ATTEMPTS", "5"))
+	if err != nil || attempts <= 0 {
+		return nil, fmt.Errorf("некорректный REMINDER_MAX_ATTEMPTS")
+	}
+
+	return &ReminderConfig{
+		Notifier:     getEnvHelper("REMINDER_NOTIFIER", "log"),
+		PollInterval: interval,
+		BatchSize:    batch,
+		MaxAttempts:  attempts,
+		WebhookURL:   getEnvHelper("REMINDER_WEBHOOK_URL", ""),
+		SMTPHost:     getEnvHelper("SMTP_HOST", ""),
+		SMTPPort:     getEnvHelper("SMTP_PORT", "587"),
+		SMTPUser:     getEnvHelper("SMTP_USER", ""),
+		SMTPPassword: getEnvHelper("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnvHelper("SMTP_FROM", ""),
+	}, nil
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"calendar/internal/service"
+)
+
+// коды ошибок в ответах API v2
+const (
+	codeInvalidArgument    = "invalid_argument"
+	codeUnauthenticated    = "unauthenticated"
+	codePermissionDenied   = "permission_denied"
+	codeNotFound           = "not_found"
+	codeConflict           = "conflict"
+	codePreconditionFailed = "precondition_failed"
+	codeInternal           = "internal"
+)
+
+// internalErrorMessage показывается вместо текста внутренних ошибок, сами ошибки пишутся в лог
+const internalErrorMessage = "internal error"
+
+// errorStatusHelper выбирает код ответа и код ошибки по типу ошибки сервиса.
+// Текст внутренних ошибок (БД и т.п.) клиенту не показывается.
+func errorStatusHelper(err error) (int, string, string) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return http.StatusBadRequest, codeInvalidArgument, err.Error()
+	case errors.Is(err, service.ErrUnauthorized):
+		return http.StatusUnauthorized, codeUnauthenticated, err.Error()
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden, codePermissionDenied, err.Error()
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound, codeNotFound, err.Error()
+	case errors.Is(err, service.ErrConflict):
+		return http.StatusConflict, codeConflict, err.Error()
+	case errors.Is(err, service.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed, codePreconditionFailed, err.Error()
+	default:
+		log.Println("ошибка сервиса:", err)
+		return http.StatusInternalServerError, codeInternal, internalErrorMessage
+	}
+}
+
+// WriteError отвечает ошибкой {"error":"..."}. Сообщение кодируется как JSON,
+// поэтому кавычки и переводы строк в нём не ломают ответ. Используется и в middleware,
+// чтобы все ошибки API имели одинаковый формат.
+func WriteError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+func writeErrorHelper(w http.ResponseWriter, status int, msg string) {
+	WriteError(w, status, msg)
+}
+
+// serviceErrorHelper отвечает ошибкой сервиса с кодом, соответствующим её типу.
+func serviceErrorHelper(w http.ResponseWriter, err error) {
+	status, _, msg := errorStatusHelper(err)
+	writeErrorHelper(w, status, msg)
+}
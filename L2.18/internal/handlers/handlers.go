@@ -3,14 +3,25 @@ package handlers
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
+	"mime"
 	"strconv"
+	"strings"
 	"time"
 
+	"calendar/internal/auth"
+	"calendar/internal/ical"
 	"calendar/internal/models"
 	"calendar/internal/service"
 	"net/http"
+
+	"github.com/go-chi/chi/v5"
 )
 
+// maxImportSize ограничение на размер импортируемого .ics файла
+const maxImportSize = 10 << 20
+
 // Handler описывает интерфейс всех HTTP-обработчиков для событий календаря.
 type Handler interface {
 	CreateEvent(w http.ResponseWriter, r *http.Request)
@@ -19,6 +30,21 @@ type Handler interface {
 	EventsForDay(w http.ResponseWriter, r *http.Request)
 	EventsForWeek(w http.ResponseWriter, r *http.Request)
 	EventsForMonth(w http.ResponseWriter, r *http.Request)
+	ExportCalendar(w http.ResponseWriter, r *http.Request)
+	ImportCalendar(w http.ResponseWriter, r *http.Request)
+	SetTimeZone(w http.ResponseWriter, r *http.Request)
+	FreeBusy(w http.ResponseWriter, r *http.Request)
+	RespondToEvent(w http.ResponseWriter, r *http.Request)
+	ShareCalendar(w http.ResponseWriter, r *http.Request)
+	UnshareCalendar(w http.ResponseWriter, r *http.Request)
+	CalendarShares(w http.ResponseWriter, r *http.Request)
+	SearchEvents(w http.ResponseWriter, r *http.Request)
+	ListEventsV2(w http.ResponseWriter, r *http.Request)
+	GetEventV2(w http.ResponseWriter, r *http.Request)
+	CreateEventV2(w http.ResponseWriter, r *http.Request)
+	PatchEventV2(w http.ResponseWriter, r *http.Request)
+	DeleteEventV2(w http.ResponseWriter, r *http.Request)
+	OpenAPI(w http.ResponseWriter, r *http.Request)
 }
 
 // DefaultHandler реализует интерфейс Handler.
@@ -37,53 +63,92 @@ func NewDefaultHandler(service service.Service) (*DefaultHandler, error) {
 }
 
 // CreateEvent обрабатывает POST /create_event и создаёт новое событие.
+// ?on_conflict=reject отклоняет событие, пересекающееся с уже существующими (409),
+// ?on_conflict=flag создаёт его и возвращает пересечения в поле conflicts.
 func (dh *DefaultHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 	var event models.Event
 
 	err := json.NewDecoder(r.Body).Decode(&event)
 	if err != nil {
-		http.Error(w, `{"error":"invalid JSON"}`, http.StatusBadRequest)
-		fmt.Println("фейлим1")
+		writeErrorHelper(w, http.StatusBadRequest, "invalid JSON")
 		return
 	}
+	defaultUserHelper(r, &event.UserID)
 
 	err = validateEventInputHelper(&event)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err.Error()), http.StatusBadRequest)
-		fmt.Println("фейлим2")
+		writeErrorHelper(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	onConflict := r.URL.Query().Get("on_conflict")
+	var conflicts []*models.Event
+	switch onConflict {
+	case "":
+	case "reject", "flag":
+		conflicts, err = dh.svc.FindConflicts(r.Context(), &event)
+		if err != nil {
+			serviceErrorHelper(w, err)
+			return
+		}
+	default:
+		writeErrorHelper(w, http.StatusBadRequest, "on_conflict must be reject or flag")
+		return
+	}
+
+	if onConflict == "reject" && len(conflicts) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]any{"error": "event overlaps existing events", "conflicts": conflicts})
 		return
 	}
 
 	created, err := dh.svc.CreateEvent(r.Context(), &event)
 	if err != nil {
-		http.Error(w, `{"error":"`+err.Error()+`"}`, http.StatusServiceUnavailable)
-		fmt.Println("фейлим3")
+		serviceErrorHelper(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if onConflict == "flag" {
+		json.NewEncoder(w).Encode(map[string]any{"result": created, "conflicts": conflicts})
+		return
+	}
 	json.NewEncoder(w).Encode(map[string]any{"result": created})
 }
 
 // UpdateEvent обрабатывает POST /update_event и обновляет существующее событие.
+// Для повторяющихся событий ?scope=this|following&recurrence_id=YYYY-MM-DD ограничивает
+// изменение одним вхождением или вхождениями начиная с указанного.
 func (dh *DefaultHandler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
 	var event models.Event
 
 	err := json.NewDecoder(r.Body).Decode(&event)
 	if err != nil {
-		http.Error(w, `{"error":"invalid JSON"}`, http.StatusBadRequest)
+		writeErrorHelper(w, http.StatusBadRequest, "invalid JSON")
 		return
 	}
+	defaultUserHelper(r, &event.UserID)
 
 	err = validateEventInputHelper(&event)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err.Error()), http.StatusBadRequest)
+		writeErrorHelper(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	err = dh.svc.UpdateEvent(r.Context(), &event)
+	scope, recurrenceID, err := scopeParamsHelper(r)
 	if err != nil {
-		http.Error(w, `{"error":"`+err.Error()+`"}`, http.StatusServiceUnavailable)
+		writeErrorHelper(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if scope == models.ScopeAll {
+		err = dh.svc.UpdateEvent(r.Context(), &event)
+	} else {
+		err = dh.svc.UpdateOccurrences(r.Context(), &event, recurrenceID, scope)
+	}
+	if err != nil {
+		serviceErrorHelper(w, err)
 		return
 	}
 
@@ -92,17 +157,29 @@ func (dh *DefaultHandler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
 }
 
 // DeleteEvent обрабатывает POST /delete_event и удаляет событие по ID.
+// Параметры scope и recurrence_id работают так же, как в UpdateEvent.
 func (dh *DefaultHandler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 	idStr := r.URL.Query().Get("id")
 	id, err := strconv.Atoi(idStr)
 
 	if err != nil {
-		http.Error(w, `{"error":"invalid id"}`, http.StatusBadRequest)
+		writeErrorHelper(w, http.StatusBadRequest, "invalid id")
 		return
 	}
 
-	if err := dh.svc.DeleteEvent(r.Context(), id); err != nil {
-		http.Error(w, `{"error":"`+err.Error()+`"}`, http.StatusServiceUnavailable)
+	scope, recurrenceID, err := scopeParamsHelper(r)
+	if err != nil {
+		writeErrorHelper(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if scope == models.ScopeAll {
+		err = dh.svc.DeleteEvent(r.Context(), id, 0)
+	} else {
+		err = dh.svc.DeleteOccurrences(r.Context(), id, recurrenceID, scope)
+	}
+	if err != nil {
+		serviceErrorHelper(w, err)
 		return
 	}
 
@@ -112,17 +189,17 @@ func (dh *DefaultHandler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 
 // EventsForDay обрабатывает GET /events_for_day и возвращает события за конкретный день.
 func (dh *DefaultHandler) EventsForDay(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.Atoi(r.URL.Query().Get("user_id"))
+	userID := userIDParamHelper(r)
 	dateStr := r.URL.Query().Get("date")
 
 	if _, err := time.Parse("2006-01-02", dateStr); err != nil {
-		http.Error(w, `{"error":"invalid date format"}`, http.StatusBadRequest)
+		writeErrorHelper(w, http.StatusBadRequest, "invalid date format")
 		return
 	}
 
 	events, err := dh.svc.EventsForDay(r.Context(), userID, dateStr)
 	if err != nil {
-		http.Error(w, `{"error":"`+err.Error()+`"}`, http.StatusServiceUnavailable)
+		serviceErrorHelper(w, err)
 		return
 	}
 
@@ -132,17 +209,17 @@ func (dh *DefaultHandler) EventsForDay(w http.ResponseWriter, r *http.Request) {
 
 // EventsForWeek обрабатывает GET /events_for_week и возвращает события за неделю.
 func (dh *DefaultHandler) EventsForWeek(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.Atoi(r.URL.Query().Get("user_id"))
+	userID := userIDParamHelper(r)
 	dateStr := r.URL.Query().Get("date")
 
 	if _, err := time.Parse("2006-01-02", dateStr); err != nil {
-		http.Error(w, `{"error":"invalid date format"}`, http.StatusBadRequest)
+		writeErrorHelper(w, http.StatusBadRequest, "invalid date format")
 		return
 	}
 
 	events, err := dh.svc.EventsForWeek(r.Context(), userID, dateStr)
 	if err != nil {
-		http.Error(w, `{"error":"`+err.Error()+`"}`, http.StatusServiceUnavailable)
+		serviceErrorHelper(w, err)
 		return
 	}
 
@@ -152,17 +229,17 @@ func (dh *DefaultHandler) EventsForWeek(w http.ResponseWriter, r *http.Request)
 
 // EventsForMonth обрабатывает GET /events_for_month и возвращает события за месяц.
 func (dh *DefaultHandler) EventsForMonth(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.Atoi(r.URL.Query().Get("user_id"))
+	userID := userIDParamHelper(r)
 	dateStr := r.URL.Query().Get("date")
 
 	if _, err := time.Parse("2006-01-02", dateStr); err != nil {
-		http.Error(w, `{"error":"invalid date format"}`, http.StatusBadRequest)
+		writeErrorHelper(w, http.StatusBadRequest, "invalid date format")
 		return
 	}
 
 	events, err := dh.svc.EventsForMonth(r.Context(), userID, dateStr)
 	if err != nil {
-		http.Error(w, `{"error":"`+err.Error()+`"}`, http.StatusServiceUnavailable)
+		serviceErrorHelper(w, err)
 		return
 	}
 
@@ -170,6 +247,167 @@ func (dh *DefaultHandler) EventsForMonth(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(map[string]any{"result": events})
 }
 
+// ExportCalendar обрабатывает GET /users/{id}/calendar.ics и отдаёт календарь пользователя
+// в формате iCalendar, на который можно подписаться из десктопных клиентов.
+func (dh *DefaultHandler) ExportCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || userID <= 0 {
+		writeErrorHelper(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	events, err := dh.svc.ExportEvents(r.Context(), userID)
+	if err != nil {
+		serviceErrorHelper(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="calendar.ics"`)
+	if err := ical.Encode(w, fmt.Sprintf("Календарь пользователя %d", userID), events); err != nil {
+		log.Printf("не удалось записать календарь: %v", err)
+	}
+}
+
+// ImportCalendar обрабатывает POST /import?user_id= и загружает события из .ics файла,
+// переданного телом запроса или полем file формы multipart/form-data.
+func (dh *DefaultHandler) ImportCalendar(w http.ResponseWriter, r *http.Request) {
+	userID := userIDParamHelper(r)
+	if userID <= 0 {
+		writeErrorHelper(w, http.StatusBadRequest, "user_id must be positive")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	var src io.Reader = r.Body
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			writeErrorHelper(w, http.StatusBadRequest, "multipart form must contain file field")
+			return
+		}
+		defer file.Close()
+		src = file
+	}
+
+	events, err := ical.Decode(src)
+	if err != nil {
+		writeErrorHelper(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	imported, err := dh.svc.ImportEvents(r.Context(), userID, events)
+	if err != nil {
+		serviceErrorHelper(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"result": map[string]int{"imported": imported}})
+}
+
+// SetTimeZone обрабатывает POST /users/{id}/time_zone с телом {"time_zone":"Europe/Moscow"}
+// и задаёт пояс, в котором считаются дни, недели и месяцы пользователя.
+func (dh *DefaultHandler) SetTimeZone(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || userID <= 0 {
+		writeErrorHelper(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	var body struct {
+		TimeZone string `json:"time_zone"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErrorHelper(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	if _, err := time.LoadLocation(body.TimeZone); err != nil || body.TimeZone == "" {
+		writeErrorHelper(w, http.StatusBadRequest, "unknown time zone")
+		return
+	}
+
+	if err := dh.svc.SetUserTimeZone(r.Context(), userID, body.TimeZone); err != nil {
+		serviceErrorHelper(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"result": "ok"})
+}
+
+// FreeBusy обрабатывает GET /free_busy?user_ids=1,2&from=&to=&duration=30m&limit=10 и возвращает
+// объединённую занятость пользователей и свободные слоты указанной длительности.
+// from и to задаются в RFC 3339.
+func (dh *DefaultHandler) FreeBusy(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var userIDs []int
+	for _, part := range strings.Split(q.Get("user_ids"), ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id <= 0 {
+			writeErrorHelper(w, http.StatusBadRequest, "user_ids must be a comma-separated list of positive ids")
+			return
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	from, errFrom := time.Parse(time.RFC3339, q.Get("from"))
+	to, errTo := time.Parse(time.RFC3339, q.Get("to"))
+	if errFrom != nil || errTo != nil || !from.Before(to) {
+		writeErrorHelper(w, http.StatusBadRequest, "from and to must be RFC 3339 timestamps, from before to")
+		return
+	}
+
+	duration := 30 * time.Minute
+	if v := q.Get("duration"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			writeErrorHelper(w, http.StatusBadRequest, "invalid duration")
+			return
+		}
+		duration = d
+	}
+
+	limit := 10
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeErrorHelper(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	fb, err := dh.svc.FreeBusy(r.Context(), userIDs, from, to, duration, limit)
+	if err != nil {
+		serviceErrorHelper(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"result": fb})
+}
+
+// userIDParamHelper читает user_id из строки запроса; если он не указан, берётся
+// пользователь, которому принадлежит токен.
+func userIDParamHelper(r *http.Request) int {
+	raw := r.URL.Query().Get("user_id")
+	if raw == "" {
+		userID, _ := auth.UserID(r.Context())
+		return userID
+	}
+	userID, _ := strconv.Atoi(raw)
+	return userID
+}
+
+// defaultUserHelper подставляет владельца токена, если user_id не передан в теле.
+func defaultUserHelper(r *http.Request, userID *int) {
+	if *userID == 0 {
+		*userID, _ = auth.UserID(r.Context())
+	}
+}
+
 // validateEventInputHelper проверяет базовую валидность полей события.
 func validateEventInputHelper(event *models.Event) error {
 	if event.UserID <= 0 {
@@ -178,8 +416,29 @@ func validateEventInputHelper(event *models.Event) error {
 	if event.Title == "" {
 		return fmt.Errorf("title cannot be empty")
 	}
-	if event.Date.IsZero() {
-		return fmt.Errorf("date must be set")
+	if event.Start.IsZero() {
+		return fmt.Errorf("start must be set")
+	}
+	if !event.End.IsZero() && event.End.Before(event.Start) {
+		return fmt.Errorf("end must not be before start")
 	}
 	return nil
 }
+
+// scopeParamsHelper читает параметры scope и recurrence_id; без scope изменение касается всей серии.
+func scopeParamsHelper(r *http.Request) (models.Scope, time.Time, error) {
+	scope := models.Scope(r.URL.Query().Get("scope"))
+	switch scope {
+	case "", models.ScopeAll:
+		return models.ScopeAll, time.Time{}, nil
+	case models.ScopeThis, models.ScopeFollowing:
+	default:
+		return "", time.Time{}, fmt.Errorf("scope must be one of all, this, following")
+	}
+
+	recurrenceID, err := time.Parse("2006-01-02", r.URL.Query().Get("recurrence_id"))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("recurrence_id must be a date in format YYYY-MM-DD")
+	}
+	return scope, recurrenceID, nil
+}
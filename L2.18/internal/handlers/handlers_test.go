@@ -2,16 +2,21 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"calendar/internal/auth"
 	"calendar/internal/handlers"
 	"calendar/internal/models"
+	"calendar/internal/service"
 	"calendar/internal/service/mocks"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -26,7 +31,7 @@ func TestCreateEvent_Success(t *testing.T) {
 	event := models.Event{
 		ID:     1,
 		UserID: 1,
-		Date:   time.Now().Add(24 * time.Hour),
+		Start:  time.Now().Add(24 * time.Hour),
 		Title:  "Встреча",
 	}
 
@@ -72,7 +77,7 @@ func TestCreateEvent_InvalidData(t *testing.T) {
 	// отсутствует title → должна быть ошибка валидации
 	event := models.Event{
 		UserID: 1,
-		Date:   time.Now().Add(24 * time.Hour),
+		Start:  time.Now().Add(24 * time.Hour),
 	}
 
 	body, _ := json.Marshal(event)
@@ -96,7 +101,7 @@ func TestEventsForDay_Success(t *testing.T) {
 	dateStr := "2025-10-21"
 
 	expectedEvents := []*models.Event{
-		{ID: 1, UserID: userID, Date: time.Now(), Title: "Встреча"},
+		{ID: 1, UserID: userID, Start: time.Now(), Title: "Встреча"},
 	}
 
 	mockSvc.EXPECT().
@@ -128,3 +133,291 @@ func TestEventsForDay_InvalidDate(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.Contains(t, w.Body.String(), "invalid date format")
 }
+
+func TestUpdateEvent_ThisOccurrence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	event := models.Event{
+		ID:     1,
+		UserID: 1,
+		Start:  time.Now().Add(48 * time.Hour),
+		Title:  "Стендап (перенесён)",
+	}
+	recurrenceID, _ := time.Parse("2006-01-02", "2025-10-23")
+
+	// изменение одного вхождения не должно трогать всю серию
+	mockSvc.EXPECT().
+		UpdateOccurrences(gomock.Any(), gomock.Any(), recurrenceID, models.ScopeThis).
+		Return(nil)
+
+	body, _ := json.Marshal(event)
+	req := httptest.NewRequest(http.MethodPost, "/update_event?scope=this&recurrence_id=2025-10-23", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.UpdateEvent(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestDeleteEvent_InvalidScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodPost, "/delete_event?id=1&scope=following", nil)
+	w := httptest.NewRecorder()
+
+	handler.DeleteEvent(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "recurrence_id")
+}
+
+func TestExportCalendar_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	mockSvc.EXPECT().
+		ExportEvents(gomock.Any(), 1).
+		Return([]*models.Event{{ID: 1, UID: "meeting@calendar", UserID: 1, Start: time.Now(), Title: "Встреча"}}, nil)
+
+	router := chi.NewRouter()
+	router.Get("/users/{id}/calendar.ics", handler.ExportCalendar)
+	req := httptest.NewRequest(http.MethodGet, "/users/1/calendar.ics", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/calendar")
+	assert.Contains(t, w.Body.String(), "UID:meeting@calendar")
+	assert.Contains(t, w.Body.String(), "SUMMARY:Встреча")
+}
+
+func TestImportCalendar_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	mockSvc.EXPECT().
+		ImportEvents(gomock.Any(), 2, gomock.Len(1)).
+		Return(1, nil)
+
+	body := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:x@example.com\r\nDTSTART;VALUE=DATE:20251020\r\nSUMMARY:Встреча\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	req := httptest.NewRequest(http.MethodPost, "/import?user_id=2", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "text/calendar")
+	w := httptest.NewRecorder()
+
+	handler.ImportCalendar(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"imported":1`)
+}
+
+func TestCreateEvent_RejectConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	start := time.Now().Add(24 * time.Hour)
+	event := models.Event{UserID: 1, Start: start, End: start.Add(time.Hour), Title: "Встреча"}
+
+	// при пересечении событие не должно создаваться
+	mockSvc.EXPECT().
+		FindConflicts(gomock.Any(), gomock.Any()).
+		Return([]*models.Event{{ID: 7, UserID: 1, Start: start, End: start.Add(30 * time.Minute), Title: "Созвон"}}, nil)
+
+	body, _ := json.Marshal(event)
+	req := httptest.NewRequest(http.MethodPost, "/create_event?on_conflict=reject", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateEvent(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"Созвон"`)
+}
+
+func TestFreeBusy_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	from := time.Date(2025, 10, 21, 9, 0, 0, 0, time.UTC)
+	to := from.Add(8 * time.Hour)
+	mockSvc.EXPECT().
+		FreeBusy(gomock.Any(), []int{1, 2}, from, to, time.Hour, 10).
+		Return(&models.FreeBusy{Busy: []models.Interval{{Start: from, End: from.Add(time.Hour)}}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/free_busy?user_ids=1,2&from=2025-10-21T09:00:00Z&to=2025-10-21T17:00:00Z&duration=1h", nil)
+	w := httptest.NewRecorder()
+
+	handler.FreeBusy(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"busy"`)
+}
+
+func TestFreeBusy_InvalidUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodGet, "/free_busy?user_ids=1,x&from=2025-10-21T09:00:00Z&to=2025-10-21T17:00:00Z", nil)
+	w := httptest.NewRecorder()
+
+	handler.FreeBusy(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeleteEvent_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	mockSvc.EXPECT().
+		DeleteEvent(gomock.Any(), 7, 0).
+		Return(fmt.Errorf("%w: событие 7", service.ErrForbidden))
+
+	req := httptest.NewRequest(http.MethodPost, "/delete_event?id=7", nil)
+	req = req.WithContext(auth.WithUser(context.Background(), 1))
+	w := httptest.NewRecorder()
+
+	handler.DeleteEvent(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestEventsForDay_DefaultUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	// без user_id берётся владелец токена
+	mockSvc.EXPECT().
+		EventsForDay(gomock.Any(), 5, "2025-10-21").
+		Return([]*models.Event{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/events_for_day?date=2025-10-21", nil)
+	req = req.WithContext(auth.WithUser(context.Background(), 5))
+	w := httptest.NewRecorder()
+
+	handler.EventsForDay(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRespondToEvent_InvalidStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	router := chi.NewRouter()
+	router.Post("/events/{id}/rsvp", handler.RespondToEvent)
+
+	req := httptest.NewRequest(http.MethodPost, "/events/7/rsvp", bytes.NewReader([]byte(`{"status":"maybe"}`)))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestShareCalendar_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	mockSvc.EXPECT().
+		ShareCalendar(gomock.Any(), &models.CalendarShare{OwnerID: 1, UserID: 2, Permission: models.PermissionWrite}).
+		Return(nil)
+
+	router := chi.NewRouter()
+	router.Post("/users/{id}/shares", handler.ShareCalendar)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/1/shares", bytes.NewReader([]byte(`{"user_id":2,"permission":"write"}`)))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"permission":"write"`)
+}
+
+func TestSearchEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	mockSvc.EXPECT().
+		SearchEvents(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, s models.EventSearch) (*models.EventSearchResult, error) {
+			assert.Equal(t, 1, s.UserID, "user_id берётся из токена")
+			assert.Equal(t, "ретро спринта", s.Query)
+			assert.Equal(t, []string{"работа", "команда"}, s.Tags)
+			assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), s.From)
+			assert.True(t, s.To.IsZero())
+			assert.Equal(t, models.SortStart, s.Sort)
+			assert.Equal(t, 20, s.Limit)
+			assert.Equal(t, 40, s.Offset)
+			return &models.EventSearchResult{Events: []*models.Event{{ID: 3, Title: "Ретро спринта"}}, Total: 41}, nil
+		})
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/events/search?q=%D1%80%D0%B5%D1%82%D1%80%D0%BE+%D1%81%D0%BF%D1%80%D0%B8%D0%BD%D1%82%D0%B0&tags=работа,команда&from=2025-01-01T00:00:00Z&sort=start&limit=20&offset=40", nil)
+	req = req.WithContext(auth.WithUser(context.Background(), 1))
+	w := httptest.NewRecorder()
+
+	handler.SearchEvents(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Result models.EventSearchResult `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 41, body.Result.Total)
+	assert.Len(t, body.Result.Events, 1)
+}
+
+func TestSearchEvents_InvalidTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/search?q=x&to=завтра", nil)
+	req = req.WithContext(auth.WithUser(context.Background(), 1))
+	w := httptest.NewRecorder()
+
+	handler.SearchEvents(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"calendar/internal/models"
+	"calendar/internal/openapi"
+)
+
+// specOnce строит спецификацию один раз, она не меняется во время работы
+var specOnce = sync.OnceValue(OpenAPISpec)
+
+// OpenAPISpec описывает API v2. Схемы тел строятся по тем же типам, которыми кодируются
+// ответы, поэтому спецификация не расходится с кодом.
+func OpenAPISpec() *openapi.Document {
+	doc := openapi.New("Calendar API", "2.0.0")
+	doc.Components.SecuritySchemes = map[string]openapi.SecurityScheme{
+		"bearer": {Type: "http", Scheme: "bearer"},
+		"apiKey": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+	}
+	doc.Security = []map[string][]string{{"bearer": {}}, {"apiKey": {}}}
+
+	event := doc.SchemaOf(models.Event{})
+	doc.ReadOnly("Event", "id", "version", "created_at", "updated_at")
+	errorResponse := openapi.Response{Description: "ошибка", Content: openapi.JSON(doc.SchemaOf(apiError{}))}
+	etagHeader := map[string]openapi.Header{
+		"ETag": {Description: "версия события для If-Match", Schema: &openapi.Schema{Type: "string"}},
+	}
+	str := func() *openapi.Schema { return &openapi.Schema{Type: "string"} }
+	idParam := openapi.Parameter{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer"}}
+	ifMatch := openapi.Parameter{Name: "If-Match", In: "header", Description: "ETag, полученный при чтении события", Schema: str()}
+	scopeParams := []openapi.Parameter{
+		{Name: "scope", In: "query", Schema: &openapi.Schema{Type: "string", Enum: []string{"all", "this", "following"}}},
+		{Name: "recurrence_id", In: "query", Description: "дата вхождения серии, YYYY-MM-DD", Schema: &openapi.Schema{Type: "string", Format: "date"}},
+	}
+	withErrors := func(responses map[string]openapi.Response, codes ...string) map[string]openapi.Response {
+		for _, code := range append(codes, "400", "401", "403", "500") {
+			responses[code] = errorResponse
+		}
+		return responses
+	}
+
+	doc.Add(http.MethodGet, "/v2/events", &openapi.Operation{
+		Summary:     "Страница событий пользователя в диапазоне, серии развёрнуты во вхождения",
+		OperationID: "listEvents",
+		Parameters: []openapi.Parameter{
+			{Name: "user_id", In: "query", Description: "по умолчанию владелец токена", Schema: &openapi.Schema{Type: "integer"}},
+			{Name: "from", In: "query", Description: "по умолчанию текущий момент", Schema: &openapi.Schema{Type: "string", Format: "date-time"}},
+			{Name: "to", In: "query", Description: "по умолчанию from + 30 дней", Schema: &openapi.Schema{Type: "string", Format: "date-time"}},
+			{Name: "cursor", In: "query", Description: "next_cursor предыдущей страницы", Schema: str()},
+			{Name: "limit", In: "query", Description: "по умолчанию 50", Schema: &openapi.Schema{Type: "integer"}},
+		},
+		Responses: withErrors(map[string]openapi.Response{
+			"200": {Description: "страница событий", Content: openapi.JSON(doc.SchemaOf(models.EventPage{}))},
+		}),
+	})
+	doc.Add(http.MethodPost, "/v2/events", &openapi.Operation{
+		Summary:     "Создать событие",
+		OperationID: "createEvent",
+		Parameters: []openapi.Parameter{
+			{Name: "on_conflict", In: "query", Description: "reject - отклонить при пересечении", Schema: &openapi.Schema{Type: "string", Enum: []string{"reject"}}},
+		},
+		RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(event)},
+		Responses: withErrors(map[string]openapi.Response{
+			"201": {Description: "событие создано", Headers: etagHeader, Content: openapi.JSON(event)},
+		}, "409"),
+	})
+	doc.Add(http.MethodGet, "/v2/events/{id}", &openapi.Operation{
+		Summary:     "Получить событие",
+		OperationID: "getEvent",
+		Parameters:  []openapi.Parameter{idParam, {Name: "If-None-Match", In: "header", Schema: str()}},
+		Responses: withErrors(map[string]openapi.Response{
+			"200": {Description: "событие", Headers: etagHeader, Content: openapi.JSON(event)},
+			"304": {Description: "событие не изменилось"},
+		}, "404"),
+	})
+	doc.Add(http.MethodPatch, "/v2/events/{id}", &openapi.Operation{
+		Summary:     "Изменить переданные поля события",
+		OperationID: "patchEvent",
+		Parameters:  append([]openapi.Parameter{idParam, ifMatch}, scopeParams...),
+		RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(doc.SchemaOf(eventPatch{}))},
+		Responses: withErrors(map[string]openapi.Response{
+			"200": {Description: "изменённое событие", Headers: etagHeader, Content: openapi.JSON(event)},
+		}, "404", "409", "412"),
+	})
+	doc.Add(http.MethodDelete, "/v2/events/{id}", &openapi.Operation{
+		Summary:     "Удалить событие",
+		OperationID: "deleteEvent",
+		Parameters:  append([]openapi.Parameter{idParam, ifMatch}, scopeParams...),
+		Responses: withErrors(map[string]openapi.Response{
+			"204": {Description: "событие удалено"},
+		}, "404", "412"),
+	})
+	return doc
+}
+
+// OpenAPI обрабатывает GET /openapi.json и отдаёт спецификацию API v2.
+func (dh *DefaultHandler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(specOnce())
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"calendar/internal/models"
+)
+
+// SearchEvents обрабатывает GET /events/search?q=&from=&to=&tags=&sort=&limit=&offset= и ищет
+// события по тексту названия и описания, меткам (через запятую, нужны все) и времени.
+// sort принимает relevance, start или -start, limit по умолчанию 50.
+func (dh *DefaultHandler) SearchEvents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	search := models.EventSearch{
+		UserID: userIDParamHelper(r),
+		Query:  q.Get("q"),
+		Sort:   models.SearchSort(q.Get("sort")),
+		Limit:  defaultPageSize,
+	}
+	if search.UserID <= 0 {
+		writeErrorHelper(w, http.StatusBadRequest, "user_id must be a positive integer")
+		return
+	}
+
+	for name, dst := range map[string]*time.Time{"from": &search.From, "to": &search.To} {
+		if raw := q.Get(name); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				writeErrorHelper(w, http.StatusBadRequest, name+" must be in RFC 3339 format")
+				return
+			}
+			*dst = t
+		}
+	}
+	for name, dst := range map[string]*int{"limit": &search.Limit, "offset": &search.Offset} {
+		if raw := q.Get(name); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				writeErrorHelper(w, http.StatusBadRequest, name+" must be an integer")
+				return
+			}
+			*dst = n
+		}
+	}
+	if raw := q.Get("tags"); raw != "" {
+		search.Tags = strings.Split(raw, ",")
+	}
+
+	result, err := dh.svc.SearchEvents(r.Context(), search)
+	if err != nil {
+		serviceErrorHelper(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"result": result})
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"calendar/internal/models"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RespondToEvent обрабатывает POST /events/{id}/rsvp с телом {"status":"accepted"} и сохраняет
+// ответ текущего пользователя на приглашение: needs-action, accepted, declined или tentative.
+func (dh *DefaultHandler) RespondToEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || eventID <= 0 {
+		writeErrorHelper(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var body struct {
+		Status models.AttendeeStatus `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErrorHelper(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	if err := body.Status.Validate(); err != nil {
+		writeErrorHelper(w, http.StatusBadRequest, "status must be one of needs-action, accepted, declined, tentative")
+		return
+	}
+
+	if err := dh.svc.RespondToEvent(r.Context(), eventID, body.Status); err != nil {
+		serviceErrorHelper(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"result": "ok"})
+}
+
+// ShareCalendar обрабатывает POST /users/{id}/shares с телом {"user_id":2,"permission":"read"}
+// и открывает пользователю доступ к календарю на чтение или запись.
+func (dh *DefaultHandler) ShareCalendar(w http.ResponseWriter, r *http.Request) {
+	ownerID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || ownerID <= 0 {
+		writeErrorHelper(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	var share models.CalendarShare
+	if err := json.NewDecoder(r.Body).Decode(&share); err != nil {
+		writeErrorHelper(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	share.OwnerID = ownerID
+	if share.UserID <= 0 || share.UserID == ownerID {
+		writeErrorHelper(w, http.StatusBadRequest, "user_id must be another user")
+		return
+	}
+	if err := share.Permission.Validate(); err != nil {
+		writeErrorHelper(w, http.StatusBadRequest, "permission must be read or write")
+		return
+	}
+
+	if err := dh.svc.ShareCalendar(r.Context(), &share); err != nil {
+		serviceErrorHelper(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"result": share})
+}
+
+// UnshareCalendar обрабатывает DELETE /users/{id}/shares/{user_id} и закрывает доступ к календарю.
+func (dh *DefaultHandler) UnshareCalendar(w http.ResponseWriter, r *http.Request) {
+	ownerID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || ownerID <= 0 {
+		writeErrorHelper(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	userID, err := strconv.Atoi(chi.URLParam(r, "user_id"))
+	if err != nil || userID <= 0 {
+		writeErrorHelper(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	if err := dh.svc.UnshareCalendar(r.Context(), ownerID, userID); err != nil {
+		serviceErrorHelper(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"result": "ok"})
+}
+
+// CalendarShares обрабатывает GET /users/{id}/shares и возвращает, кому открыт календарь.
+func (dh *DefaultHandler) CalendarShares(w http.ResponseWriter, r *http.Request) {
+	ownerID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || ownerID <= 0 {
+		writeErrorHelper(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	shares, err := dh.svc.CalendarShares(r.Context(), ownerID)
+	if err != nil {
+		serviceErrorHelper(w, err)
+		return
+	}
+	if shares == nil {
+		shares = []*models.CalendarShare{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"result": shares})
+}
@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"calendar/internal/models"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// параметры списка событий v2 по умолчанию
+const (
+	defaultPageSize  = 50
+	defaultListRange = 30 * 24 * time.Hour
+)
+
+// apiError тело ответа с ошибкой в API v2
+type apiError struct {
+	Error apiErrorBody `json:"error"`
+}
+
+// apiErrorBody машиночитаемый код и текст ошибки
+type apiErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// eventPatch поля события, которые можно изменить через PATCH. Поля, которых нет
+// в теле запроса, не меняются.
+type eventPatch struct {
+	Title       *string                  `json:"title"`
+	Description *string                  `json:"description"`
+	Tags        *[]string                `json:"tags"`
+	Start       *time.Time               `json:"start"`
+	End         *time.Time               `json:"end"`
+	AllDay      *bool                    `json:"all_day"`
+	TimeZone    *string                  `json:"time_zone"`
+	RRule       *string                  `json:"rrule"`
+	ExDates     *[]time.Time             `json:"exdates"`
+	Reminders   *[]models.ReminderOffset `json:"reminders"`
+	Attendees   *[]models.Attendee       `json:"attendees"`
+}
+
+// apply переносит заданные поля в событие.
+func (p *eventPatch) apply(event *models.Event) {
+	if p.Title != nil {
+		event.Title = *p.Title
+	}
+	if p.Description != nil {
+		event.Description = *p.Description
+	}
+	if p.Tags != nil {
+		event.Tags = *p.Tags
+	}
+	if p.Start != nil {
+		event.Start = *p.Start
+	}
+	if p.End != nil {
+		event.End = *p.End
+	}
+	if p.AllDay != nil {
+		event.AllDay = *p.AllDay
+	}
+	if p.TimeZone != nil {
+		event.TimeZone = *p.TimeZone
+	}
+	if p.RRule != nil {
+		event.RRule = *p.RRule
+	}
+	if p.ExDates != nil {
+		event.ExDates = *p.ExDates
+	}
+	if p.Reminders != nil {
+		event.Reminders = *p.Reminders
+	}
+	if p.Attendees != nil {
+		event.Attendees = *p.Attendees
+	}
+}
+
+// ListEventsV2 обрабатывает GET /v2/events и возвращает страницу событий пользователя
+// в диапазоне [from, to) (по умолчанию 30 дней от текущего момента). Следующая страница
+// запрашивается с cursor из next_cursor.
+func (dh *DefaultHandler) ListEventsV2(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	userID := userIDParamHelper(r)
+	if userID <= 0 {
+		writeProblemHelper(w, http.StatusBadRequest, codeInvalidArgument, "user_id must be a positive integer")
+		return
+	}
+
+	from := time.Now()
+	if raw := q.Get("from"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeProblemHelper(w, http.StatusBadRequest, codeInvalidArgument, "from must be in RFC 3339 format")
+			return
+		}
+		from = t
+	}
+	to := from.Add(defaultListRange)
+	if raw := q.Get("to"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeProblemHelper(w, http.StatusBadRequest, codeInvalidArgument, "to must be in RFC 3339 format")
+			return
+		}
+		to = t
+	}
+	limit := defaultPageSize
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			writeProblemHelper(w, http.StatusBadRequest, codeInvalidArgument, "limit must be an integer")
+			return
+		}
+		limit = n
+	}
+
+	page, err := dh.svc.ListEvents(r.Context(), userID, from, to, q.Get("cursor"), limit)
+	if err != nil {
+		serviceProblemHelper(w, err)
+		return
+	}
+	writeJSONHelper(w, http.StatusOK, page)
+}
+
+// GetEventV2 обрабатывает GET /v2/events/{id}. ETag ответа передаётся в If-Match
+// при изменении и удалении события.
+func (dh *DefaultHandler) GetEventV2(w http.ResponseWriter, r *http.Request) {
+	id, ok := eventIDHelper(w, r)
+	if !ok {
+		return
+	}
+	event, err := dh.svc.GetEvent(r.Context(), id)
+	if err != nil {
+		serviceProblemHelper(w, err)
+		return
+	}
+	etag := eventETag(event)
+	w.Header().Set("ETag", etag)
+	if etagMatchesHelper(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSONHelper(w, http.StatusOK, event)
+}
+
+// CreateEventV2 обрабатывает POST /v2/events и отвечает 201 с созданным событием.
+// ?on_conflict=reject отклоняет событие, пересекающееся с существующими (409).
+func (dh *DefaultHandler) CreateEventV2(w http.ResponseWriter, r *http.Request) {
+	var event models.Event
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&event); err != nil {
+		writeProblemHelper(w, http.StatusBadRequest, codeInvalidArgument, "invalid JSON: "+err.Error())
+		return
+	}
+	defaultUserHelper(r, &event.UserID)
+	if err := validateEventInputHelper(&event); err != nil {
+		writeProblemHelper(w, http.StatusBadRequest, codeInvalidArgument, err.Error())
+		return
+	}
+
+	switch r.URL.Query().Get("on_conflict") {
+	case "":
+	case "reject":
+		conflicts, err := dh.svc.FindConflicts(r.Context(), &event)
+		if err != nil {
+			serviceProblemHelper(w, err)
+			return
+		}
+		if len(conflicts) > 0 {
+			writeProblemHelper(w, http.StatusConflict, codeConflict,
+				fmt.Sprintf("event overlaps %d existing events", len(conflicts)))
+			return
+		}
+	default:
+		writeProblemHelper(w, http.StatusBadRequest, codeInvalidArgument, "on_conflict must be reject")
+		return
+	}
+
+	created, err := dh.svc.CreateEvent(r.Context(), &event)
+	if err != nil {
+		serviceProblemHelper(w, err)
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/v2/events/%d", created.ID))
+	w.Header().Set("ETag", eventETag(created))
+	writeJSONHelper(w, http.StatusCreated, created)
+}
+
+// PatchEventV2 обрабатывает PATCH /v2/events/{id} и меняет только переданные поля.
+// С If-Match событие меняется, только если его не изменили после получения ETag (иначе 412).
+// Параметры scope и recurrence_id работают так же, как в v1.
+func (dh *DefaultHandler) PatchEventV2(w http.ResponseWriter, r *http.Request) {
+	id, ok := eventIDHelper(w, r)
+	if !ok {
+		return
+	}
+	var patch eventPatch
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&patch); err != nil {
+		writeProblemHelper(w, http.StatusBadRequest, codeInvalidArgument, "invalid JSON: "+err.Error())
+		return
+	}
+	scope, recurrenceID, err := scopeParamsHelper(r)
+	if err != nil {
+		writeProblemHelper(w, http.StatusBadRequest, codeInvalidArgument, err.Error())
+		return
+	}
+
+	event, err := dh.svc.GetEvent(r.Context(), id)
+	if err != nil {
+		serviceProblemHelper(w, err)
+		return
+	}
+	version, ok := ifMatchHelper(w, r, event)
+	if !ok {
+		return
+	}
+	if scope != models.ScopeAll {
+		occurrenceTimesHelper(event, recurrenceID)
+	}
+	patch.apply(event)
+	event.Version = version
+	if err := validateEventInputHelper(event); err != nil {
+		writeProblemHelper(w, http.StatusBadRequest, codeInvalidArgument, err.Error())
+		return
+	}
+
+	if scope == models.ScopeAll {
+		err = dh.svc.UpdateEvent(r.Context(), event)
+	} else {
+		err = dh.svc.UpdateOccurrences(r.Context(), event, recurrenceID, scope)
+	}
+	if err != nil {
+		serviceProblemHelper(w, err)
+		return
+	}
+
+	updated, err := dh.svc.GetEvent(r.Context(), id)
+	if err != nil {
+		serviceProblemHelper(w, err)
+		return
+	}
+	w.Header().Set("ETag", eventETag(updated))
+	writeJSONHelper(w, http.StatusOK, updated)
+}
+
+// DeleteEventV2 обрабатывает DELETE /v2/events/{id} и отвечает 204.
+// If-Match, scope и recurrence_id работают так же, как в PatchEventV2.
+func (dh *DefaultHandler) DeleteEventV2(w http.ResponseWriter, r *http.Request) {
+	id, ok := eventIDHelper(w, r)
+	if !ok {
+		return
+	}
+	scope, recurrenceID, err := scopeParamsHelper(r)
+	if err != nil {
+		writeProblemHelper(w, http.StatusBadRequest, codeInvalidArgument, err.Error())
+		return
+	}
+
+	version := 0
+	if r.Header.Get("If-Match") != "" {
+		event, err := dh.svc.GetEvent(r.Context(), id)
+		if err != nil {
+			serviceProblemHelper(w, err)
+			return
+		}
+		if version, ok = ifMatchHelper(w, r, event); !ok {
+			return
+		}
+	}
+
+	if scope == models.ScopeAll {
+		err = dh.svc.DeleteEvent(r.Context(), id, version)
+	} else {
+		err = dh.svc.DeleteOccurrences(r.Context(), id, recurrenceID, scope)
+	}
+	if err != nil {
+		serviceProblemHelper(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// eventIDHelper читает ID события из пути, при ошибке отвечает 400.
+func eventIDHelper(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		writeProblemHelper(w, http.StatusBadRequest, codeInvalidArgument, "event id must be a positive integer")
+		return 0, false
+	}
+	return id, true
+}
+
+// occurrenceTimesHelper переносит начало и конец серии на дату вхождения recurrenceID,
+// чтобы PATCH вхождения без start не сдвигал его на дату начала серии.
+func occurrenceTimesHelper(event *models.Event, recurrenceID time.Time) {
+	loc := event.Location()
+	start := event.Start.In(loc)
+	y, m, d := recurrenceID.Date()
+	shifted := time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), 0, loc)
+	if !event.End.IsZero() {
+		event.End = shifted.Add(event.End.Sub(event.Start))
+	}
+	event.Start = shifted
+}
+
+// eventETag строит ETag события из его ID и версии.
+func eventETag(event *models.Event) string {
+	return fmt.Sprintf(`"%d-%d"`, event.ID, event.Version)
+}
+
+// etagMatchesHelper проверяет, есть ли etag в списке из заголовка If-Match или If-None-Match.
+// Слабые ETag (W/"...") сравниваются как сильные, * подходит к любому.
+func etagMatchesHelper(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchHelper сверяет If-Match с текущим ETag события и возвращает версию, с которой
+// нужно атомарно сравнить событие при записи (0 - без проверки). При несовпадении отвечает 412.
+func ifMatchHelper(w http.ResponseWriter, r *http.Request, event *models.Event) (int, bool) {
+	header := r.Header.Get("If-Match")
+	if header == "" || strings.TrimSpace(header) == "*" {
+		return 0, true
+	}
+	if !etagMatchesHelper(header, eventETag(event)) {
+		writeProblemHelper(w, http.StatusPreconditionFailed, codePreconditionFailed, "event has been modified")
+		return 0, false
+	}
+	return event.Version, true
+}
+
+// writeJSONHelper отвечает телом body в формате JSON с кодом status.
+func writeJSONHelper(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeProblemHelper отвечает ошибкой API v2 {"error":{"code":"...","message":"..."}}.
+func writeProblemHelper(w http.ResponseWriter, status int, code, msg string) {
+	writeJSONHelper(w, status, apiError{Error: apiErrorBody{Code: code, Message: msg}})
+}
+
+// serviceProblemHelper отвечает ошибкой сервиса в формате API v2.
+func serviceProblemHelper(w http.ResponseWriter, err error) {
+	status, code, msg := errorStatusHelper(err)
+	writeProblemHelper(w, status, code, msg)
+}
@@ -0,0 +1,294 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"calendar/internal/auth"
+	"calendar/internal/handlers"
+	"calendar/internal/models"
+	"calendar/internal/service"
+	"calendar/internal/service/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// v2RouterHelper собирает маршруты v2 так же, как в app.NewServer
+func v2RouterHelper(handler *handlers.DefaultHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Route("/v2/events", func(r chi.Router) {
+		r.Get("/", handler.ListEventsV2)
+		r.Post("/", handler.CreateEventV2)
+		r.Get("/{id}", handler.GetEventV2)
+		r.Patch("/{id}", handler.PatchEventV2)
+		r.Delete("/{id}", handler.DeleteEventV2)
+	})
+	return r
+}
+
+func v2RequestHelper(method, target string, body []byte) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	return req.WithContext(auth.WithUser(context.Background(), 1))
+}
+
+func errorCodeHelper(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.NotEmpty(t, body.Error.Message)
+	return body.Error.Code
+}
+
+func TestListEventsV2_Pagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	mockSvc.EXPECT().
+		ListEvents(gomock.Any(), 1, from, to, "abc", 2).
+		Return(&models.EventPage{Events: []*models.Event{{ID: 1}, {ID: 2}}, NextCursor: "def"}, nil)
+
+	req := v2RequestHelper(http.MethodGet, "/v2/events?from=2025-10-01T00:00:00Z&to=2025-11-01T00:00:00Z&cursor=abc&limit=2", nil)
+	w := httptest.NewRecorder()
+	v2RouterHelper(handler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page models.EventPage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Events, 2)
+	assert.Equal(t, "def", page.NextCursor)
+}
+
+func TestListEventsV2_InvalidRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	req := v2RequestHelper(http.MethodGet, "/v2/events?from=yesterday", nil)
+	w := httptest.NewRecorder()
+	v2RouterHelper(handler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "invalid_argument", errorCodeHelper(t, w))
+}
+
+func TestGetEventV2_ETag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	mockSvc.EXPECT().GetEvent(gomock.Any(), 7).Return(&models.Event{ID: 7, UserID: 1, Version: 3}, nil).Times(2)
+
+	w := httptest.NewRecorder()
+	v2RouterHelper(handler).ServeHTTP(w, v2RequestHelper(http.MethodGet, "/v2/events/7", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"7-3"`, w.Header().Get("ETag"))
+
+	req := v2RequestHelper(http.MethodGet, "/v2/events/7", nil)
+	req.Header.Set("If-None-Match", `W/"7-3"`)
+	w = httptest.NewRecorder()
+	v2RouterHelper(handler).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
+
+func TestGetEventV2_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	mockSvc.EXPECT().GetEvent(gomock.Any(), 7).Return(nil, fmt.Errorf("событие %w", service.ErrNotFound))
+
+	w := httptest.NewRecorder()
+	v2RouterHelper(handler).ServeHTTP(w, v2RequestHelper(http.MethodGet, "/v2/events/7", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "not_found", errorCodeHelper(t, w))
+}
+
+func TestCreateEventV2_Created(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	start := time.Now().Add(24 * time.Hour)
+	mockSvc.EXPECT().
+		CreateEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, ev *models.Event) (*models.Event, error) {
+			assert.Equal(t, 1, ev.UserID, "user_id берётся из токена")
+			ev.ID, ev.Version = 12, 1
+			return ev, nil
+		})
+
+	body, _ := json.Marshal(map[string]any{"title": "Встреча", "start": start})
+	w := httptest.NewRecorder()
+	v2RouterHelper(handler).ServeHTTP(w, v2RequestHelper(http.MethodPost, "/v2/events", body))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/v2/events/12", w.Header().Get("Location"))
+	assert.Equal(t, `"12-1"`, w.Header().Get("ETag"))
+}
+
+func TestCreateEventV2_UnknownField(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	body := []byte(`{"title":"Встреча","start":"2030-01-01T10:00:00Z","colour":"red"}`)
+	w := httptest.NewRecorder()
+	v2RouterHelper(handler).ServeHTTP(w, v2RequestHelper(http.MethodPost, "/v2/events", body))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "invalid_argument", errorCodeHelper(t, w))
+}
+
+func TestPatchEventV2_OnlyGivenFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	start := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	stored := func() *models.Event {
+		return &models.Event{ID: 7, UserID: 1, Title: "Старое", Start: start, End: start.Add(time.Hour), Version: 3}
+	}
+	gomock.InOrder(
+		mockSvc.EXPECT().GetEvent(gomock.Any(), 7).Return(stored(), nil),
+		mockSvc.EXPECT().
+			UpdateEvent(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, ev *models.Event) error {
+				assert.Equal(t, "Новое", ev.Title)
+				assert.True(t, ev.Start.Equal(start), "start не передан и не меняется")
+				assert.Equal(t, 3, ev.Version, "версия из If-Match проверяется при записи")
+				return nil
+			}),
+		mockSvc.EXPECT().GetEvent(gomock.Any(), 7).DoAndReturn(func(context.Context, int) (*models.Event, error) {
+			ev := stored()
+			ev.Title, ev.Version = "Новое", 4
+			return ev, nil
+		}),
+	)
+
+	req := v2RequestHelper(http.MethodPatch, "/v2/events/7", []byte(`{"title":"Новое"}`))
+	req.Header.Set("If-Match", `"7-3"`)
+	w := httptest.NewRecorder()
+	v2RouterHelper(handler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"7-4"`, w.Header().Get("ETag"))
+}
+
+func TestPatchEventV2_PreconditionFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	mockSvc.EXPECT().GetEvent(gomock.Any(), 7).Return(&models.Event{ID: 7, UserID: 1, Title: "t", Start: time.Now(), Version: 4}, nil)
+
+	req := v2RequestHelper(http.MethodPatch, "/v2/events/7", []byte(`{"title":"Новое"}`))
+	req.Header.Set("If-Match", `"7-3"`)
+	w := httptest.NewRecorder()
+	v2RouterHelper(handler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Equal(t, "precondition_failed", errorCodeHelper(t, w))
+}
+
+func TestPatchEventV2_ConcurrentUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	// событие изменили между чтением и записью: версию проверяет хранилище
+	mockSvc.EXPECT().GetEvent(gomock.Any(), 7).Return(&models.Event{ID: 7, UserID: 1, Title: "t", Start: time.Now(), Version: 3}, nil)
+	mockSvc.EXPECT().UpdateEvent(gomock.Any(), gomock.Any()).Return(service.ErrPreconditionFailed)
+
+	req := v2RequestHelper(http.MethodPatch, "/v2/events/7", []byte(`{"title":"Новое"}`))
+	req.Header.Set("If-Match", `"7-3"`)
+	w := httptest.NewRecorder()
+	v2RouterHelper(handler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
+
+func TestDeleteEventV2(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	mockSvc.EXPECT().GetEvent(gomock.Any(), 7).Return(&models.Event{ID: 7, UserID: 1, Version: 2}, nil)
+	mockSvc.EXPECT().DeleteEvent(gomock.Any(), 7, 2).Return(nil)
+
+	req := v2RequestHelper(http.MethodDelete, "/v2/events/7", nil)
+	req.Header.Set("If-Match", `"7-2"`)
+	w := httptest.NewRecorder()
+	v2RouterHelper(handler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestDeleteEventV2_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mocks.NewMockService(ctrl)
+	handler, _ := handlers.NewDefaultHandler(mockSvc)
+
+	mockSvc.EXPECT().DeleteEvent(gomock.Any(), 7, 0).Return(fmt.Errorf("%w: событие 7", service.ErrForbidden))
+
+	w := httptest.NewRecorder()
+	v2RouterHelper(handler).ServeHTTP(w, v2RequestHelper(http.MethodDelete, "/v2/events/7", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "permission_denied", errorCodeHelper(t, w))
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	doc := handlers.OpenAPISpec()
+
+	for _, method := range []string{"get", "patch", "delete"} {
+		assert.Contains(t, doc.Paths["/v2/events/{id}"], method)
+	}
+	event := doc.Components.Schemas["Event"]
+	require.NotNil(t, event)
+	assert.Equal(t, "date-time", event.Properties["start"].Format)
+	assert.True(t, event.Properties["version"].ReadOnly)
+	assert.Contains(t, doc.Components.Schemas, "ApiError")
+
+	_, err := json.Marshal(doc)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,430 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"calendar/internal/models"
+	"calendar/internal/recurrence"
+)
+
+// ProdID идентификатор календаря в экспортируемых файлах
+const ProdID = "-//Wildberries L2.18//calendar//RU"
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+	localLayout    = "20060102T150405"
+	maxLineOctets  = 75
+)
+
+// Encode записывает события в формате iCalendar (RFC 5545). Переопределения вхождений
+// (ParentID != nil) выводятся как отдельные VEVENT с UID серии и RECURRENCE-ID
+func Encode(w io.Writer, name string, events []*models.Event) error {
+	byID := make(map[int]*models.Event, len(events))
+	for _, ev := range events {
+		byID[ev.ID] = ev
+	}
+
+	e := &encoder{w: bufio.NewWriter(w)}
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + ProdID)
+	e.line("CALSCALE:GREGORIAN")
+	if name != "" {
+		e.line("X-WR-CALNAME:" + escape(name))
+	}
+	stamp := time.Now().UTC().Format(dateTimeLayout)
+	for _, ev := range events {
+		uid, series := ev.UID, ev
+		if ev.ParentID != nil {
+			if series = byID[*ev.ParentID]; series == nil {
+				return fmt.Errorf("для вхождения %d не передана серия %d", ev.ID, *ev.ParentID)
+			}
+			uid = series.UID
+		}
+		if uid == "" {
+			return fmt.Errorf("у события %d нет UID", ev.ID)
+		}
+
+		e.line("BEGIN:VEVENT")
+		e.line("UID:" + escape(uid))
+		e.line("DTSTAMP:" + stamp)
+		if ev.AllDay {
+			e.line("DTSTART;VALUE=DATE:" + ev.Start.Format(dateLayout))
+			e.line("DTEND;VALUE=DATE:" + ev.End.Format(dateLayout))
+		} else {
+			e.line("DTSTART" + formatTime(ev.Start, ev.TimeZone))
+			e.line("DTEND" + formatTime(ev.End, ev.TimeZone))
+		}
+		e.line("SUMMARY:" + escape(ev.Title))
+		if ev.Description != "" {
+			e.line("DESCRIPTION:" + escape(ev.Description))
+		}
+		if len(ev.Tags) > 0 {
+			tags := make([]string, len(ev.Tags))
+			for i, tag := range ev.Tags {
+				tags[i] = escape(tag)
+			}
+			e.line("CATEGORIES:" + strings.Join(tags, ","))
+		}
+		if ev.RRule != "" {
+			e.line("RRULE:" + ev.RRule)
+		}
+		for _, d := range ev.ExDates {
+			e.line("EXDATE" + formatDate(ev, d))
+		}
+		if ev.RecurrenceID != nil {
+			// RECURRENCE-ID указывает исходное начало вхождения, то есть время серии
+			e.line("RECURRENCE-ID" + formatDate(series, *ev.RecurrenceID))
+		}
+		if !ev.CreatedAt.IsZero() {
+			e.line("CREATED:" + ev.CreatedAt.UTC().Format(dateTimeLayout))
+		}
+		if !ev.UpdatedAt.IsZero() {
+			e.line("LAST-MODIFIED:" + ev.UpdatedAt.UTC().Format(dateTimeLayout))
+		}
+		e.line("END:VEVENT")
+	}
+	e.line("END:VCALENDAR")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// formatTime записывает момент в UTC либо, если задан пояс, местным временем с TZID,
+// чтобы повторения переживали переход на летнее время. VTIMEZONE не выводится:
+// клиенты распознают идентификаторы IANA сами
+func formatTime(t time.Time, tz string) string {
+	if tz == "" || tz == "UTC" {
+		return ":" + t.UTC().Format(dateTimeLayout)
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return ":" + t.UTC().Format(dateTimeLayout)
+	}
+	return ";TZID=" + tz + ":" + t.In(loc).Format(localLayout)
+}
+
+// formatDate записывает дату вхождения серии ev в том же виде, что и DTSTART
+func formatDate(ev *models.Event, date time.Time) string {
+	if ev.AllDay {
+		return ";VALUE=DATE:" + date.Format(dateLayout)
+	}
+	return formatTime(recurrence.OccurrenceStart(ev, date), ev.TimeZone)
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line пишет строку контента, перенося её по 75 октетов, как требует RFC 5545
+func (e *encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	for len(s) > maxLineOctets {
+		cut := maxLineOctets
+		// не разрываем многобайтный символ UTF-8
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if _, e.err = e.w.WriteString(s[:cut] + "\r\n "); e.err != nil {
+			return
+		}
+		s = s[cut:]
+	}
+	_, e.err = e.w.WriteString(s + "\r\n")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(strings.ReplaceAll(s, "\r\n", "\n"))
+}
+
+var unescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func unescape(s string) string {
+	return unescaper.Replace(s)
+}
+
+// splitEscaped делит список значений по запятым, кроме экранированных (\,)
+func splitEscaped(s string) []string {
+	var (
+		parts []string
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// value момент времени из свойства DTSTART, DTEND, EXDATE или RECURRENCE-ID
+type value struct {
+	t      time.Time
+	isDate bool
+	tz     string
+}
+
+// vevent свойства VEVENT, которые собираются в models.Event после END:VEVENT
+type vevent struct {
+	event        models.Event
+	start, end   *value
+	duration     time.Duration
+	exdates      []value
+	recurrenceID *value
+}
+
+// Decode читает VEVENT из потока iCalendar. У переопределений вхождений заполнен
+// RecurrenceID, а связь с серией задаётся общим UID. Вложенные компоненты
+// (VALARM, VTIMEZONE) и неизвестные свойства пропускаются
+func Decode(r io.Reader) ([]*models.Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events []*models.Event
+		cur    *vevent
+		depth  int // вложенность компонентов внутри VEVENT
+	)
+	for i, raw := range lines {
+		name, params, val, err := parseLine(raw)
+		if err != nil {
+			return nil, fmt.Errorf("ical: строка %d: %w", i+1, err)
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(val, "VEVENT") && cur == nil:
+			cur = &vevent{}
+			continue
+		case cur == nil:
+			continue
+		case name == "BEGIN":
+			depth++
+			continue
+		case name == "END" && depth > 0:
+			depth--
+			continue
+		case name == "END" && strings.EqualFold(val, "VEVENT"):
+			ev, err := cur.build()
+			if err != nil {
+				return nil, fmt.Errorf("ical: %w", err)
+			}
+			events = append(events, ev)
+			cur = nil
+			continue
+		case depth > 0:
+			continue
+		}
+
+		switch name {
+		case "UID":
+			cur.event.UID = unescape(val)
+		case "SUMMARY":
+			cur.event.Title = unescape(val)
+		case "DESCRIPTION":
+			cur.event.Description = unescape(val)
+		case "CATEGORIES":
+			for _, tag := range splitEscaped(val) {
+				cur.event.Tags = append(cur.event.Tags, unescape(tag))
+			}
+		case "RRULE":
+			cur.event.RRule = val
+		case "DTSTART":
+			cur.start, err = parseValue(val, params)
+		case "DTEND":
+			cur.end, err = parseValue(val, params)
+		case "DURATION":
+			cur.duration, err = parseDuration(val)
+		case "EXDATE":
+			for _, v := range strings.Split(val, ",") {
+				d, perr := parseValue(v, params)
+				if perr != nil {
+					err = perr
+					break
+				}
+				cur.exdates = append(cur.exdates, *d)
+			}
+		case "RECURRENCE-ID":
+			cur.recurrenceID, err = parseValue(val, params)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ical: строка %d: %s: %w", i+1, name, err)
+		}
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("ical: незакрытый VEVENT")
+	}
+	return events, nil
+}
+
+// build собирает событие: DTEND вычисляется из DURATION, если не задан, а даты
+// исключений переводятся в пояс события
+func (v *vevent) build() (*models.Event, error) {
+	ev := &v.event
+	if ev.UID == "" {
+		return nil, fmt.Errorf("VEVENT без UID")
+	}
+	if v.start == nil {
+		return nil, fmt.Errorf("VEVENT %s без DTSTART", ev.UID)
+	}
+
+	ev.Start = v.start.t
+	ev.AllDay = v.start.isDate
+	if !ev.AllDay {
+		ev.TimeZone = v.start.tz
+	}
+	switch {
+	case v.end != nil:
+		ev.End = v.end.t
+	case v.duration > 0:
+		ev.End = ev.Start.Add(v.duration)
+	case ev.AllDay:
+		ev.End = ev.Start.AddDate(0, 0, 1)
+	default:
+		ev.End = ev.Start
+	}
+
+	loc := ev.Location()
+	for _, d := range v.exdates {
+		ev.ExDates = append(ev.ExDates, dateIn(d, loc))
+	}
+	if v.recurrenceID != nil {
+		date := dateIn(*v.recurrenceID, loc)
+		ev.RecurrenceID = &date
+	}
+	return ev, nil
+}
+
+// dateIn дата момента в поясе loc; значения типа DATE уже являются датами
+func dateIn(v value, loc *time.Location) time.Time {
+	if v.isDate {
+		return v.t
+	}
+	return models.DateOf(v.t.In(loc))
+}
+
+// unfold склеивает перенесённые строки (продолжение начинается с пробела или табуляции)
+func unfold(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	var lines []string
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("ical: ошибка чтения: %w", err)
+	}
+	return lines, nil
+}
+
+// parseLine разбирает строку вида NAME;PARAM=VALUE:value
+func parseLine(line string) (name string, params map[string]string, value string, err error) {
+	head, value, ok := cutUnquoted(line, ':')
+	if !ok {
+		return "", nil, "", fmt.Errorf("нет разделителя ':'")
+	}
+	parts := strings.Split(head, ";")
+	name = strings.ToUpper(parts[0])
+	params = make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return name, params, value, nil
+}
+
+// cutUnquoted как strings.Cut, но пропускает sep внутри кавычек в параметрах
+func cutUnquoted(s string, sep byte) (string, string, bool) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+// parseValue разбирает DATE или DATE-TIME. Время с Z - это UTC, время с TZID - местное время
+// этого пояса (неизвестный пояс считается UTC), время без того и другого трактуется как UTC
+func parseValue(val string, params map[string]string) (*value, error) {
+	val = strings.TrimSpace(val)
+	if params["VALUE"] == "DATE" || len(val) == len(dateLayout) {
+		t, err := time.Parse(dateLayout, val)
+		if err != nil {
+			return nil, err
+		}
+		return &value{t: t, isDate: true}, nil
+	}
+
+	if strings.HasSuffix(val, "Z") {
+		t, err := time.Parse(dateTimeLayout, val)
+		if err != nil {
+			return nil, err
+		}
+		return &value{t: t, tz: "UTC"}, nil
+	}
+
+	tz, loc := "UTC", time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil && tzid != "Local" {
+			tz, loc = tzid, l
+		}
+	}
+	t, err := time.ParseInLocation(localLayout, val, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &value{t: t, tz: tz}, nil
+}
+
+var durationRe = regexp.MustCompile(`^\+?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration разбирает DURATION вида P1D, PT1H30M или P2W
+func parseDuration(val string) (time.Duration, error) {
+	m := durationRe.FindStringSubmatch(strings.TrimSpace(val))
+	if m == nil || val == "P" || strings.HasSuffix(val, "T") {
+		return 0, fmt.Errorf("некорректная длительность %q", val)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("некорректная длительность %q", val)
+		}
+		d += time.Duration(n) * unit
+	}
+	return d, nil
+}
@@ -0,0 +1,113 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"calendar/internal/ical"
+	"calendar/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	seriesID := 1
+	moved := time.Date(2025, 10, 23, 0, 0, 0, 0, time.UTC)
+	events := []*models.Event{
+		{
+			ID: 1, UID: "standup@calendar", TimeZone: "Europe/Moscow",
+			Start: time.Date(2025, 10, 20, 10, 0, 0, 0, moscow), End: time.Date(2025, 10, 20, 10, 15, 0, 0, moscow),
+			Title: "Стендап; обсуждаем задачи, блокеры и \\ прочее", RRule: "FREQ=WEEKLY;BYDAY=MO,TH",
+			ExDates:     []time.Time{time.Date(2025, 10, 27, 0, 0, 0, 0, time.UTC)},
+			Description: "Повестка:\n1. статус, риски", Tags: []string{"работа", "a,b"},
+		},
+		{
+			ID: 2, UID: "other", TimeZone: "UTC", Title: "Перенесён", ParentID: &seriesID, RecurrenceID: &moved,
+			Start: time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC), End: time.Date(2025, 10, 24, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			ID: 3, UID: "review@calendar", AllDay: true, Title: strings.Repeat("Очень длинное название ", 10),
+			Start: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ical.Encode(&buf, "Тест", events))
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		assert.LessOrEqual(t, len(line), 76, "строка длиннее 75 октетов: %q", line)
+	}
+
+	got, err := ical.Decode(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, events[0].UID, got[0].UID)
+	assert.Equal(t, events[0].Title, got[0].Title)
+	assert.True(t, events[0].Start.Equal(got[0].Start))
+	assert.True(t, events[0].End.Equal(got[0].End))
+	assert.Equal(t, "Europe/Moscow", got[0].TimeZone)
+	assert.Contains(t, buf.String(), "DTSTART;TZID=Europe/Moscow:20251020T100000")
+	assert.Equal(t, events[0].RRule, got[0].RRule)
+	assert.Equal(t, events[0].ExDates, got[0].ExDates)
+	assert.Equal(t, events[0].Description, got[0].Description)
+	assert.Equal(t, events[0].Tags, got[0].Tags, "экранированная запятая не делит метку")
+
+	// переопределение вхождения получает UID серии
+	assert.Equal(t, "standup@calendar", got[1].UID)
+	require.NotNil(t, got[1].RecurrenceID)
+	assert.Equal(t, moved, *got[1].RecurrenceID)
+
+	assert.True(t, events[1].Start.Equal(got[1].Start))
+
+	assert.Equal(t, events[2].Title, got[2].Title)
+	assert.True(t, got[2].AllDay)
+	assert.Equal(t, events[2].End, got[2].End)
+}
+
+func TestDecode_ForeignCalendar(t *testing.T) {
+	src := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTIMEZONE\r\nTZID:Europe/Moscow\r\nEND:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:abc@example.com\r\n" +
+		"DTSTART;TZID=\"Europe/Moscow\":20251020T100000\r\n" +
+		"SUMMARY:Встреча\\, созвон\r\n" +
+		" с командой\r\n" +
+		"EXDATE;TZID=Europe/Moscow:20251027T100000,20251103T100000\r\n" +
+		"RRULE:FREQ=WEEKLY\r\n" +
+		"DURATION:PT1H30M\r\n" +
+		"BEGIN:VALARM\r\nSUMMARY:не должно попасть\r\nEND:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	got, err := ical.Decode(strings.NewReader(src))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Встреча, созвонс командой", got[0].Title)
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	assert.True(t, time.Date(2025, 10, 20, 10, 0, 0, 0, moscow).Equal(got[0].Start))
+	assert.True(t, got[0].Start.Add(90*time.Minute).Equal(got[0].End))
+	assert.Equal(t, "Europe/Moscow", got[0].TimeZone)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 10, 27, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC),
+	}, got[0].ExDates)
+	assert.Equal(t, "FREQ=WEEKLY", got[0].RRule)
+}
+
+func TestDecode_Errors(t *testing.T) {
+	for name, src := range map[string]string{
+		"без UID":      "BEGIN:VEVENT\r\nDTSTART:20251020\r\nEND:VEVENT\r\n",
+		"без DTSTART":  "BEGIN:VEVENT\r\nUID:x\r\nEND:VEVENT\r\n",
+		"незакрытый":   "BEGIN:VEVENT\r\nUID:x\r\nDTSTART:20251020\r\n",
+		"плохая дата":  "BEGIN:VEVENT\r\nUID:x\r\nDTSTART:2025-10-20\r\nEND:VEVENT\r\n",
+		"без значения": "BEGIN:VEVENT\r\nUID\r\nEND:VEVENT\r\n",
+	} {
+		_, err := ical.Decode(strings.NewReader(src))
+		assert.Error(t, err, name)
+	}
+}
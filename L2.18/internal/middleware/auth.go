@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"calendar/internal/auth"
+	"calendar/internal/handlers"
+)
+
+// TokenLookup находит владельца токена по его хэшу
+type TokenLookup interface {
+	UserByToken(ctx context.Context, tokenHash string) (int, error)
+}
+
+// AuthMiddleware пропускает только запросы с действующим токеном в заголовке
+// "Authorization: Bearer <token>" или "X-API-Key: <token>" и кладёт пользователя в контекст.
+// С allowQuery токен принимается и из параметра ?token=, это нужно для подписки
+// на календарь из клиентов, которые не умеют передавать заголовки
+func AuthMiddleware(lookup TokenLookup, allowQuery bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := tokenFromRequest(r, allowQuery)
+			if token == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="calendar"`)
+				handlers.WriteError(w, http.StatusUnauthorized, "authentication required")
+				return
+			}
+
+			userID, err := lookup.UserByToken(r.Context(), auth.HashToken(token))
+			if err != nil || userID <= 0 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="calendar", error="invalid_token"`)
+				handlers.WriteError(w, http.StatusUnauthorized, "invalid or expired token")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), userID)))
+		})
+	}
+}
+
+func tokenFromRequest(r *http.Request, allowQuery bool) string {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return strings.TrimSpace(key)
+	}
+	if allowQuery {
+		return r.URL.Query().Get("token")
+	}
+	return ""
+}
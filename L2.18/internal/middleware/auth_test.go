@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"calendar/internal/auth"
+	"calendar/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lookupFunc func(ctx context.Context, tokenHash string) (int, error)
+
+func (f lookupFunc) UserByToken(ctx context.Context, tokenHash string) (int, error) {
+	return f(ctx, tokenHash)
+}
+
+func TestAuthMiddleware_JSONErrors(t *testing.T) {
+	lookup := lookupFunc(func(ctx context.Context, tokenHash string) (int, error) {
+		if tokenHash == auth.HashToken("good") {
+			return 7, nil
+		}
+		return 0, errors.New("токен не найден")
+	})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := auth.UserID(r.Context())
+		assert.Equal(t, 7, userID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	h := middleware.AuthMiddleware(lookup, false)(next)
+
+	cases := []struct {
+		name   string
+		header string
+		status int
+		errMsg string
+	}{
+		{"без токена", "", http.StatusUnauthorized, "authentication required"},
+		{"неверный токен", "Bearer bad", http.StatusUnauthorized, "invalid or expired token"},
+		{"верный токен", "Bearer good", http.StatusNoContent, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.status, rec.Code)
+			if tc.errMsg == "" {
+				return
+			}
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			var body map[string]string
+			assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+			assert.Equal(t, tc.errMsg, body["error"])
+		})
+	}
+}
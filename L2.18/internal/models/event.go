@@ -4,10 +4,67 @@ import "time"
 
 // Event представляет собой сущность "событие" в календаре
 type Event struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"user_id"`
-	Date      time.Time `json:"date"`
-	Title     string    `json:"title"`
+	ID     int    `json:"id"`
+	UID    string `json:"uid,omitempty"`
+	UserID int    `json:"user_id"`
+	// Start и End - начало и конец события, End не входит в событие.
+	// У событий на весь день это полночи в UTC, обозначающие даты независимо от часового пояса
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	AllDay bool      `json:"all_day"`
+	// TimeZone часовой пояс IANA (Europe/Moscow), в котором разворачиваются повторения
+	TimeZone string `json:"time_zone,omitempty"`
+	Title    string `json:"title"`
+	// Description подробное описание, по нему и по названию работает полнотекстовый поиск
+	Description string `json:"description,omitempty"`
+	// Tags метки события в нижнем регистре без повторов
+	Tags []string `json:"tags,omitempty"`
+	// Version увеличивается при каждом изменении события, из неё строится ETag
+	Version   int       `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// RRule правило повторения в формате RFC 5545 (FREQ=WEEKLY;BYDAY=MO), пустое для разовых событий
+	RRule string `json:"rrule,omitempty"`
+	// ExDates даты отменённых вхождений серии в часовом поясе события
+	ExDates []time.Time `json:"exdates,omitempty"`
+	// ParentID у изменённого вхождения указывает на серию, которую оно переопределяет
+	ParentID *int `json:"parent_id,omitempty"`
+	// RecurrenceID исходная дата вхождения: у переопределений и у вхождений, развёрнутых из серии
+	RecurrenceID *time.Time `json:"recurrence_id,omitempty"`
+
+	// Reminders за сколько до начала (каждого вхождения) напомнить о событии
+	Reminders []ReminderOffset `json:"reminders,omitempty"`
+	// Attendees приглашённые пользователи, у переопределений вхождений - участники серии
+	Attendees []Attendee `json:"attendees,omitempty"`
+}
+
+// Location возвращает часовой пояс, в котором считаются даты события.
+// События на весь день не привязаны к поясу, для них это UTC
+func (e *Event) Location() *time.Location {
+	if e.AllDay || e.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
 }
+
+// DateOf возвращает дату момента t в его часовом поясе как полночь UTC.
+// В таком виде хранятся исключения, recurrence_id и границы событий на весь день
+func DateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Scope определяет, какие вхождения повторяющегося события затрагивает изменение
+type Scope string
+
+// допустимые значения Scope
+const (
+	ScopeAll       Scope = "all"
+	ScopeThis      Scope = "this"
+	ScopeFollowing Scope = "following"
+)
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Interval промежуток времени [Start, End)
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// FreeBusy занятость группы пользователей в диапазоне
+type FreeBusy struct {
+	// Busy объединённые интервалы, когда занят хотя бы один пользователь
+	Busy []Interval `json:"busy"`
+	// Free промежутки, когда свободны все, не короче запрошенной длительности
+	Free []Interval `json:"free"`
+	// Slots предлагаемые слоты запрошенной длительности
+	Slots []Interval `json:"slots"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventCursor позиция в списке событий, отсортированном по началу, ID и дате вхождения
+type EventCursor struct {
+	Start        time.Time
+	ID           int
+	RecurrenceID string
+}
+
+// CursorOf возвращает курсор, указывающий на событие ev
+func CursorOf(ev *Event) EventCursor {
+	c := EventCursor{Start: ev.Start, ID: ev.ID}
+	if ev.RecurrenceID != nil {
+		c.RecurrenceID = ev.RecurrenceID.Format("2006-01-02")
+	}
+	return c
+}
+
+// Compare сравнивает позиции курсоров: -1, 0 или 1
+func (c EventCursor) Compare(other EventCursor) int {
+	if n := c.Start.Compare(other.Start); n != 0 {
+		return n
+	}
+	if c.ID != other.ID {
+		if c.ID < other.ID {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(c.RecurrenceID, other.RecurrenceID)
+}
+
+// Encode возвращает непрозрачную для клиента строку курсора
+func (c EventCursor) Encode() string {
+	raw := strconv.FormatInt(c.Start.UnixNano(), 10) + ":" + strconv.Itoa(c.ID) + ":" + c.RecurrenceID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeEventCursor разбирает строку, полученную из EventCursor.Encode
+func DecodeEventCursor(s string) (EventCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return EventCursor{}, fmt.Errorf("некорректный курсор")
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return EventCursor{}, fmt.Errorf("некорректный курсор")
+	}
+	nanos, err1 := strconv.ParseInt(parts[0], 10, 64)
+	id, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return EventCursor{}, fmt.Errorf("некорректный курсор")
+	}
+	return EventCursor{Start: time.Unix(0, nanos), ID: id, RecurrenceID: parts[2]}, nil
+}
+
+// EventPage страница списка событий
+type EventPage struct {
+	Events []*Event `json:"events"`
+	// NextCursor передаётся в следующий запрос, пустой на последней странице
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReminderOffset за сколько до начала события отправляется напоминание.
+// В JSON записывается строкой: "15m", "1h30m", "1d", "1w"
+type ReminderOffset time.Duration
+
+// maxReminderOffset ограничение, чтобы напоминания не планировались на годы вперёд
+const maxReminderOffset = ReminderOffset(4 * 7 * 24 * time.Hour)
+
+// ParseReminderOffset разбирает смещение напоминания: длительность Go ("15m", "2h")
+// или целое число дней и недель ("1d", "2w")
+func ParseReminderOffset(s string) (ReminderOffset, error) {
+	s = strings.TrimSpace(s)
+	var d time.Duration
+	switch {
+	case strings.HasSuffix(s, "d") || strings.HasSuffix(s, "w"):
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil {
+			return 0, fmt.Errorf("некорректное смещение напоминания %q", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+		if strings.HasSuffix(s, "w") {
+			d *= 7
+		}
+	default:
+		var err error
+		d, err = time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("некорректное смещение напоминания %q", s)
+		}
+	}
+	o := ReminderOffset(d)
+	if o < 0 || o > maxReminderOffset || d%time.Minute != 0 {
+		return 0, fmt.Errorf("смещение напоминания %q должно быть целым числом минут от 0 до 4 недель", s)
+	}
+	return o, nil
+}
+
+// String возвращает смещение в том же виде, в котором его принимает ParseReminderOffset
+func (o ReminderOffset) String() string {
+	d := time.Duration(o)
+	const day = 24 * time.Hour
+	switch {
+	case d == 0:
+		return "0m"
+	case d%(7*day) == 0:
+		return strconv.Itoa(int(d/(7*day))) + "w"
+	case d%day == 0:
+		return strconv.Itoa(int(d/day)) + "d"
+	}
+	var b strings.Builder
+	if h := d / time.Hour; h > 0 {
+		b.WriteString(strconv.Itoa(int(h)) + "h")
+	}
+	if m := d % time.Hour / time.Minute; m > 0 {
+		b.WriteString(strconv.Itoa(int(m)) + "m")
+	}
+	return b.String()
+}
+
+// MarshalJSON записывает смещение строкой
+func (o ReminderOffset) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.String())
+}
+
+// UnmarshalJSON читает смещение из строки
+func (o *ReminderOffset) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("смещение напоминания должно быть строкой, например \"15m\"")
+	}
+	parsed, err := ParseReminderOffset(s)
+	if err != nil {
+		return err
+	}
+	*o = parsed
+	return nil
+}
+
+// Reminder состояние напоминания одного события с одним смещением.
+// У серии напоминание переходит от вхождения к вхождению
+type Reminder struct {
+	ID      int
+	EventID int
+	Offset  ReminderOffset
+	// FireAt когда отправить напоминание о вхождении OccurrenceAt; nil - время ещё не рассчитано
+	FireAt       *time.Time
+	OccurrenceAt *time.Time
+	// LastOccurrenceAt вхождение, о котором напоминание уже отправлено (или отправка прекращена)
+	LastOccurrenceAt *time.Time
+	// Attempts число неудачных попыток отправки для текущего вхождения
+	Attempts int
+	// Done у события больше нет вхождений, о которых нужно напомнить
+	Done bool
+	// Lease идентификатор захвата напоминания планировщиком
+	Lease string
+}
+
+// DueReminder напоминание, захваченное планировщиком, вместе с событием и данными пользователя
+type DueReminder struct {
+	Reminder
+	Event *Event
+	// UserTimeZone пояс, в котором наступают события пользователя на весь день
+	UserTimeZone string
+	UserEmail    string
+	// Overridden даты вхождений серии, у которых есть переопределения со своими напоминаниями
+	Overridden []time.Time
+}
+
+// ReminderDelivery запись об одной попытке отправить напоминание
+type ReminderDelivery struct {
+	ReminderID   int
+	OccurrenceAt time.Time
+	Attempt      int
+	Notifier     string
+	// Error пустая у успешной отправки
+	Error     string
+	CreatedAt time.Time
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// SearchSort порядок результатов поиска событий
+type SearchSort string
+
+// допустимые значения SearchSort
+const (
+	// SortRelevance сначала события, лучше всего подходящие под запрос
+	SortRelevance SearchSort = "relevance"
+	// SortStart по возрастанию начала
+	SortStart SearchSort = "start"
+	// SortStartDesc по убыванию начала, сначала самые поздние
+	SortStartDesc SearchSort = "-start"
+)
+
+// EventSearch параметры поиска событий пользователя
+type EventSearch struct {
+	UserID int
+	// Query поисковый запрос по названию и описанию: слова, "фраза", -исключение, OR
+	Query string
+	// From и To ограничивают время событий, нулевое значение - без ограничения
+	From, To time.Time
+	// Tags событие должно иметь все перечисленные метки
+	Tags   []string
+	Sort   SearchSort
+	Limit  int
+	Offset int
+}
+
+// EventSearchResult страница результатов поиска
+type EventSearchResult struct {
+	Events []*Event `json:"events"`
+	// Total сколько всего событий подходит под условия
+	Total int `json:"total"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import "fmt"
+
+// AttendeeStatus ответ участника на приглашение (PARTSTAT из RFC 5545)
+type AttendeeStatus string
+
+// допустимые значения AttendeeStatus
+const (
+	StatusNeedsAction AttendeeStatus = "needs-action"
+	StatusAccepted    AttendeeStatus = "accepted"
+	StatusDeclined    AttendeeStatus = "declined"
+	StatusTentative   AttendeeStatus = "tentative"
+)
+
+// Validate проверяет, что статус - один из допустимых
+func (s AttendeeStatus) Validate() error {
+	switch s {
+	case StatusNeedsAction, StatusAccepted, StatusDeclined, StatusTentative:
+		return nil
+	}
+	return fmt.Errorf("недопустимый статус приглашения %q", s)
+}
+
+// Attendee приглашённый на событие пользователь
+type Attendee struct {
+	UserID int            `json:"user_id"`
+	Status AttendeeStatus `json:"status"`
+}
+
+// Permission права пользователя на чужой календарь
+type Permission string
+
+// допустимые значения Permission; write включает read
+const (
+	PermissionRead  Permission = "read"
+	PermissionWrite Permission = "write"
+)
+
+// Validate проверяет, что права - одно из допустимых значений
+func (p Permission) Validate() error {
+	switch p {
+	case PermissionRead, PermissionWrite:
+		return nil
+	}
+	return fmt.Errorf("недопустимые права %q, ожидается read или write", p)
+}
+
+// Allows проверяет, достаточно ли прав p для действия, требующего need
+func (p Permission) Allows(need Permission) bool {
+	return p == PermissionWrite || (p == PermissionRead && need == PermissionRead)
+}
+
+// CalendarShare доступ пользователя UserID к календарю пользователя OwnerID
+type CalendarShare struct {
+	OwnerID    int        `json:"owner_id"`
+	UserID     int        `json:"user_id"`
+	Permission Permission `json:"permission"`
+}
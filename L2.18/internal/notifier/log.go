@@ -0,0 +1,30 @@
+package notifier
+
+import (
+	"context"
+	"log"
+)
+
+// LogNotifier пишет напоминания в лог, используется по умолчанию и при отладке
+type LogNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogNotifier создаёт LogNotifier, при logger == nil используется стандартный логгер
+func NewLogNotifier(logger *log.Logger) *LogNotifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogNotifier{logger: logger}
+}
+
+// Name возвращает имя канала
+func (l *LogNotifier) Name() string {
+	return "log"
+}
+
+// Notify записывает напоминание в лог
+func (l *LogNotifier) Notify(ctx context.Context, n Notification) error {
+	l.logger.Printf("пользователь %d, событие %d: %s", n.UserID, n.EventID, n.Text())
+	return nil
+}
@@ -0,0 +1,37 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"calendar/internal/models"
+)
+
+// Notification напоминание о вхождении события, которое нужно доставить пользователю
+type Notification struct {
+	ReminderID int       `json:"reminder_id"`
+	EventID    int       `json:"event_id"`
+	UserID     int       `json:"user_id"`
+	Email      string    `json:"-"`
+	Title      string    `json:"title"`
+	Start      time.Time `json:"start"`
+	AllDay     bool      `json:"all_day"`
+	// Before за сколько до начала отправлено напоминание
+	Before models.ReminderOffset `json:"before"`
+}
+
+// Notifier доставляет напоминания пользователям
+type Notifier interface {
+	// Name используется в журнале попыток отправки
+	Name() string
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Text возвращает текст напоминания для каналов без разметки
+func (n Notification) Text() string {
+	if n.AllDay {
+		return fmt.Sprintf("Напоминание: «%s» %s (весь день)", n.Title, n.Start.Format("02.01.2006"))
+	}
+	return fmt.Sprintf("Напоминание: «%s» начинается %s", n.Title, n.Start.Format("02.01.2006 15:04 MST"))
+}
@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"calendar/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testNotification() Notification {
+	return Notification{
+		ReminderID: 1,
+		EventID:    2,
+		UserID:     3,
+		Email:      "user@example.com",
+		Title:      "Встреча",
+		Start:      time.Date(2025, 10, 20, 10, 0, 0, 0, time.UTC),
+		Before:     models.ReminderOffset(15 * time.Minute),
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	wn, err := NewWebhookNotifier(srv.URL, nil)
+	require.NoError(t, err)
+	require.NoError(t, wn.Notify(context.Background(), testNotification()))
+	assert.Equal(t, "Встреча", got["title"])
+	assert.Equal(t, "15m", got["before"])
+	assert.NotContains(t, got, "email")
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	wn, err := NewWebhookNotifier(srv.URL, nil)
+	require.NoError(t, err)
+	assert.Error(t, wn.Notify(context.Background(), testNotification()))
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	s, err := NewSMTPNotifier("smtp.example.com", "587", "", "", "calendar@example.com")
+	require.NoError(t, err)
+
+	var to []string
+	var msg string
+	s.send = func(addr string, a smtp.Auth, from string, rcpt []string, body []byte) error {
+		to, msg = rcpt, string(body)
+		return nil
+	}
+	require.NoError(t, s.Notify(context.Background(), testNotification()))
+	assert.Equal(t, []string{"user@example.com"}, to)
+	assert.Contains(t, msg, "Subject: =?utf-8?q?")
+	assert.True(t, strings.HasSuffix(msg, "\r\n"))
+
+	n := testNotification()
+	n.Email = ""
+	assert.Error(t, s.Notify(context.Background(), n), "без email письмо не отправить")
+}
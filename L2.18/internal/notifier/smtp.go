@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPNotifier отправляет напоминание письмом на email пользователя
+type SMTPNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+	// send позволяет подменить отправку в тестах
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPNotifier создаёт SMTPNotifier для сервера host:port. Если user пустой,
+// письма отправляются без аутентификации
+func NewSMTPNotifier(host, port, user, password, from string) (*SMTPNotifier, error) {
+	if host == "" || port == "" || from == "" {
+		return nil, fmt.Errorf("для SMTP нужно задать хост, порт и адрес отправителя")
+	}
+	var auth smtp.Auth
+	if user != "" {
+		auth = smtp.PlainAuth("", user, password, host)
+	}
+	return &SMTPNotifier{
+		addr: net.JoinHostPort(host, port),
+		from: from,
+		auth: auth,
+		send: smtp.SendMail,
+	}, nil
+}
+
+// Name возвращает имя канала
+func (s *SMTPNotifier) Name() string {
+	return "smtp"
+}
+
+// Notify отправляет письмо. smtp.SendMail не принимает контекст, поэтому отменённый
+// контекст проверяется только перед отправкой
+func (s *SMTPNotifier) Notify(ctx context.Context, n Notification) error {
+	if n.Email == "" {
+		return fmt.Errorf("у пользователя %d не указан email", n.UserID)
+	}
+	if strings.ContainsAny(n.Email, "\r\n") {
+		return fmt.Errorf("некорректный email пользователя %d", n.UserID)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := s.send(s.addr, s.auth, s.from, []string{n.Email}, s.message(n)); err != nil {
+		return fmt.Errorf("не удалось отправить письмо: %w", err)
+	}
+	return nil
+}
+
+func (s *SMTPNotifier) message(n Notification) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + s.from + "\r\n")
+	b.WriteString("To: " + n.Email + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", "Напоминание: "+n.Title) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(n.Text() + "\r\n")
+	return []byte(b.String())
+}
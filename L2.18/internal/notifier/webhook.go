@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookNotifier отправляет напоминание POST-запросом с JSON на заданный адрес
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier создаёт WebhookNotifier, при client == nil используется клиент с таймаутом 10 секунд
+func NewWebhookNotifier(url string, client *http.Client) (*WebhookNotifier, error) {
+	if url == "" {
+		return nil, fmt.Errorf("не задан адрес вебхука")
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &WebhookNotifier{url: url, client: client}, nil
+}
+
+// Name возвращает имя канала
+func (wn *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// Notify отправляет напоминание, любой ответ кроме 2xx считается ошибкой
+func (wn *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("не удалось сформировать тело вебхука: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("не удалось сформировать запрос вебхука: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := wn.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("вебхук недоступен: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("вебхук ответил %s", resp.Status)
+	}
+	return nil
+}
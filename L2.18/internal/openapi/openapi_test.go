@@ -0,0 +1,51 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type duration int
+
+func (d duration) MarshalJSON() ([]byte, error) { return []byte(`"1h"`), nil }
+
+type child struct {
+	Name string `json:"name"`
+}
+
+type base struct {
+	ID int `json:"id"`
+}
+
+type parent struct {
+	base
+	When     time.Time  `json:"when"`
+	Maybe    *time.Time `json:"maybe"`
+	Skipped  string     `json:"-"`
+	Optional string     `json:"optional,omitempty"`
+	Every    duration   `json:"every"`
+	Children []child    `json:"children"`
+	Self     *parent    `json:"self,omitempty"`
+	hidden   int
+}
+
+func TestSchemaOf(t *testing.T) {
+	doc := New("test", "1")
+	ref := doc.SchemaOf(parent{})
+	assert.Equal(t, "#/components/schemas/Parent", ref.Ref)
+
+	s := doc.Components.Schemas["Parent"]
+	require.NotNil(t, s)
+	assert.Equal(t, "integer", s.Properties["id"].Type, "поля встроенной структуры поднимаются наверх")
+	assert.Equal(t, "date-time", s.Properties["when"].Format)
+	assert.True(t, s.Properties["maybe"].Nullable)
+	assert.Equal(t, "string", s.Properties["every"].Type, "тип со своим MarshalJSON описывается строкой")
+	assert.Equal(t, "#/components/schemas/Child", s.Properties["children"].Items.Ref)
+	assert.Equal(t, "#/components/schemas/Parent", s.Properties["self"].Ref, "рекурсивный тип ссылается сам на себя")
+	assert.NotContains(t, s.Properties, "Skipped")
+	assert.NotContains(t, s.Properties, "hidden")
+	assert.ElementsMatch(t, []string{"id", "when", "every", "children"}, s.Required)
+}
@@ -0,0 +1,97 @@
+package recurrence
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"calendar/internal/models"
+)
+
+// Expand разворачивает серии из events во вхождения, пересекающиеся с [from, to).
+// Разовые события и переопределения (ParentID != nil) возвращаются как есть, если пересекаются
+// с диапазоном; вхождения серии, для которых есть переопределение или исключение, пропускаются.
+// События на весь день сравниваются с датами диапазона в поясе from, а не с моментами времени.
+// Результат отсортирован по началу
+func Expand(events []*models.Event, from, to time.Time) ([]*models.Event, error) {
+	overridden := make(map[int]map[string]bool)
+	for _, ev := range events {
+		if ev.ParentID == nil || ev.RecurrenceID == nil {
+			continue
+		}
+		if overridden[*ev.ParentID] == nil {
+			overridden[*ev.ParentID] = make(map[string]bool)
+		}
+		overridden[*ev.ParentID][ev.RecurrenceID.Format(DateLayout)] = true
+	}
+
+	floatFrom, floatTo := FloatingRange(from, to)
+	var result []*models.Event
+	for _, ev := range events {
+		lo, hi := from, to
+		if ev.AllDay {
+			lo, hi = floatFrom, floatTo
+		}
+
+		if ev.RRule == "" {
+			if Overlaps(ev.Start, ev.End, lo, hi) {
+				result = append(result, ev)
+			}
+			continue
+		}
+
+		rule, err := Parse(ev.RRule)
+		if err != nil {
+			return nil, fmt.Errorf("событие %d: %w", ev.ID, err)
+		}
+		skip := make(map[string]bool)
+		for date := range overridden[ev.ID] {
+			skip[date] = true
+		}
+		for _, exdate := range ev.ExDates {
+			skip[exdate.Format(DateLayout)] = true
+		}
+
+		duration := ev.End.Sub(ev.Start)
+		for _, t := range rule.Between(ev.Start.In(ev.Location()), lo.Add(-duration), hi) {
+			end := t.Add(duration)
+			if skip[t.Format(DateLayout)] || !Overlaps(t, end, lo, hi) {
+				continue
+			}
+			date := models.DateOf(t)
+			occ := *ev
+			occ.Start, occ.End = t, end
+			occ.RecurrenceID = &date
+			result = append(result, &occ)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b *models.Event) int { return a.Start.Compare(b.Start) })
+	return result, nil
+}
+
+// Overlaps проверяет, пересекается ли событие [start, end) с диапазоном [from, to).
+// Событие нулевой длительности попадает в диапазон, если его начало внутри
+func Overlaps(start, end, from, to time.Time) bool {
+	return start.Before(to) && (end.After(from) || !start.Before(from))
+}
+
+// OccurrenceStart возвращает начало вхождения серии ev, приходящегося на дату date
+// в часовом поясе события
+func OccurrenceStart(ev *models.Event, date time.Time) time.Time {
+	start := ev.Start.In(ev.Location())
+	y, m, d := date.Date()
+	return time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+}
+
+// FloatingRange переводит диапазон [from, to) в даты пояса from в виде полуночей UTC,
+// с которыми сравниваются события на весь день. Неполный последний день включается
+func FloatingRange(from, to time.Time) (time.Time, time.Time) {
+	loc := from.Location()
+	to = to.In(loc)
+	floatTo := models.DateOf(to)
+	if y, m, d := to.Date(); !to.Equal(time.Date(y, m, d, 0, 0, 0, 0, loc)) {
+		floatTo = floatTo.AddDate(0, 0, 1)
+	}
+	return models.DateOf(from.In(loc)), floatTo
+}
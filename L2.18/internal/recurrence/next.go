@@ -0,0 +1,68 @@
+package recurrence
+
+import (
+	"time"
+
+	"calendar/internal/models"
+)
+
+// maxSearchYears на сколько лет вперёд ищется следующее вхождение серии
+const maxSearchYears = 10
+
+// NextStart возвращает начало первого вхождения события ev строго после after.
+// Вхождения на даты skip и на исключения серии пропускаются. События на весь день
+// наступают в полночь своей даты в поясе loc (обычно это пояс пользователя).
+// Второе значение false, если вхождений после after больше нет
+func NextStart(ev *models.Event, skip []time.Time, after time.Time, loc *time.Location) (time.Time, bool) {
+	actual := func(t time.Time) time.Time {
+		if !ev.AllDay {
+			return t
+		}
+		y, m, d := t.Date()
+		return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	}
+
+	if ev.RRule == "" {
+		start := actual(ev.Start)
+		return start, start.After(after)
+	}
+	rule, err := Parse(ev.RRule)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	skipped := make(map[string]bool)
+	for _, d := range append(skip, ev.ExDates...) {
+		skipped[d.Format(DateLayout)] = true
+	}
+
+	dtstart := ev.Start.In(ev.Location())
+	// событие на весь день сравнивается с after по дате и времени на часах пояса loc
+	from := after
+	if ev.AllDay {
+		wall := after.In(loc)
+		from = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), time.UTC)
+	}
+	// начало вхождения на весь день может отличаться от from на разницу поясов
+	from = from.Add(-24 * time.Hour)
+	if from.Before(dtstart) {
+		from = dtstart
+	}
+
+	for year := 0; year < maxSearchYears; year++ {
+		to := from.AddDate(1, 0, 0)
+		for _, t := range rule.Between(dtstart, from, to) {
+			if skipped[t.Format(DateLayout)] {
+				continue
+			}
+			if start := actual(t); start.After(after) {
+				return start, true
+			}
+		}
+		if !rule.Until.IsZero() && to.After(rule.Until) {
+			break
+		}
+		from = to.Add(time.Nanosecond)
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,298 @@
+package recurrence
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Freq частота повторения из RFC 5545
+type Freq string
+
+// поддерживаемые значения FREQ
+const (
+	Daily   Freq = "DAILY"
+	Weekly  Freq = "WEEKLY"
+	Monthly Freq = "MONTHLY"
+	Yearly  Freq = "YEARLY"
+)
+
+// DateLayout формат, в котором хранятся даты исключений и recurrence_id
+const DateLayout = "2006-01-02"
+
+// maxOccurrences ограничение на число вхождений за один вызов Between
+const maxOccurrences = 10_000
+
+// WeekdayNum день недели из BYDAY с необязательным порядковым номером (1MO, -1FR).
+// N == 0 означает "каждый такой день"
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule правило повторения: подмножество RRULE с FREQ, INTERVAL, BYDAY, COUNT и UNTIL
+type Rule struct {
+	Freq     Freq
+	Interval int
+	ByDay    []WeekdayNum
+	Count    int
+	Until    time.Time
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Parse разбирает строку вида "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10",
+// префикс "RRULE:" допускается
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("rrule: некорректная часть %q", part)
+		}
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Freq = Freq(strings.ToUpper(value))
+			if !slices.Contains([]Freq{Daily, Weekly, Monthly, Yearly}, rule.Freq) {
+				return nil, fmt.Errorf("rrule: неподдерживаемая частота %q", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(value)
+			if err == nil && rule.Interval < 1 {
+				err = fmt.Errorf("должен быть положительным")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(value)
+			if err == nil && rule.Count < 1 {
+				err = fmt.Errorf("должен быть положительным")
+			}
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		default:
+			return nil, fmt.Errorf("rrule: неподдерживаемый параметр %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rrule: некорректный %s: %w", name, err)
+		}
+	}
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("rrule: не указан FREQ")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, fmt.Errorf("rrule: COUNT и UNTIL нельзя указывать одновременно")
+	}
+	for _, wd := range rule.ByDay {
+		if wd.N != 0 && rule.Freq != Monthly && rule.Freq != Yearly {
+			return nil, fmt.Errorf("rrule: номер дня в BYDAY допустим только для MONTHLY и YEARLY")
+		}
+	}
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("ожидается дата вида 20060102 или 20060102T150405Z")
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToUpper(strings.TrimSpace(item))
+		if len(item) < 2 {
+			return nil, fmt.Errorf("некорректный день %q", item)
+		}
+		day, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("некорректный день %q", item)
+		}
+		wd := WeekdayNum{Day: day}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("некорректный номер дня %q", item)
+			}
+			wd.N = n
+		}
+		days = append(days, wd)
+	}
+	return days, nil
+}
+
+// String возвращает правило в формате RRULE без префикса
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			day := strings.ToUpper(wd.Day.String()[:2])
+			if wd.N != 0 {
+				day = strconv.Itoa(wd.N) + day
+			}
+			days = append(days, day)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between возвращает вхождения серии, начинающейся в dtstart, попадающие в [from, to].
+// COUNT отсчитывается от dtstart, так что результат не зависит от from.
+// Исключения (EXDATE) здесь не учитываются
+func (r *Rule) Between(dtstart, from, to time.Time) []time.Time {
+	var result []time.Time
+	count := 0
+	for period := 0; ; period++ {
+		start := r.periodStart(dtstart, period)
+		if start.After(to) || (!r.Until.IsZero() && start.After(r.Until)) {
+			return result
+		}
+		for _, t := range r.candidates(dtstart, start) {
+			if t.Before(dtstart) {
+				continue
+			}
+			if t.After(to) || (!r.Until.IsZero() && t.After(r.Until)) {
+				return result
+			}
+			count++
+			if !t.Before(from) {
+				result = append(result, t)
+			}
+			if (r.Count > 0 && count >= r.Count) || len(result) >= maxOccurrences {
+				return result
+			}
+		}
+	}
+}
+
+// Occurs проверяет, является ли t вхождением серии
+func (r *Rule) Occurs(dtstart, t time.Time) bool {
+	return len(r.Between(dtstart, t, t)) > 0
+}
+
+// CountBefore возвращает число вхождений серии строго раньше t
+func (r *Rule) CountBefore(dtstart, t time.Time) int {
+	return len(r.Between(dtstart, dtstart, t.Add(-time.Nanosecond)))
+}
+
+// periodStart начало n-го периода серии: день, неделя (с понедельника), месяц или год
+func (r *Rule) periodStart(dtstart time.Time, n int) time.Time {
+	y, m, d := dtstart.Date()
+	step := n * r.Interval
+	switch r.Freq {
+	case Daily:
+		return day(dtstart, y, m, d+step)
+	case Weekly:
+		offset := (int(dtstart.Weekday()) + 6) % 7
+		return day(dtstart, y, m, d-offset+7*step)
+	case Monthly:
+		return day(dtstart, y, m+time.Month(step), 1)
+	default:
+		return day(dtstart, y+step, time.January, 1)
+	}
+}
+
+// candidates вхождения внутри периода, начинающегося в start, в порядке возрастания
+func (r *Rule) candidates(dtstart, start time.Time) []time.Time {
+	y, m, d := start.Date()
+	switch r.Freq {
+	case Daily:
+		if len(r.ByDay) > 0 && !r.matchesDay(start.Weekday()) {
+			return nil
+		}
+		return []time.Time{start}
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			offset := (int(dtstart.Weekday()) + 6) % 7
+			return []time.Time{day(dtstart, y, m, d+offset)}
+		}
+		var out []time.Time
+		for i := range 7 {
+			t := day(dtstart, y, m, d+i)
+			if r.matchesDay(t.Weekday()) {
+				out = append(out, t)
+			}
+		}
+		return out
+	case Monthly:
+		if len(r.ByDay) == 0 {
+			return validDay(dtstart, y, m, dtstart.Day())
+		}
+		return r.byDayIn(dtstart, y, m, m)
+	default:
+		if len(r.ByDay) == 0 {
+			return validDay(dtstart, y, dtstart.Month(), dtstart.Day())
+		}
+		return r.byDayIn(dtstart, y, time.January, time.December)
+	}
+}
+
+// byDayIn отбирает дни BYDAY в месяцах [first, last] года y. Номер дня отсчитывается
+// внутри месяца для MONTHLY и внутри года для YEARLY
+func (r *Rule) byDayIn(dtstart time.Time, y int, first, last time.Month) []time.Time {
+	var all []time.Time
+	for t := day(dtstart, y, first, 1); t.Month() <= last && t.Year() == y; t = t.AddDate(0, 0, 1) {
+		all = append(all, t)
+	}
+	var out []time.Time
+	for _, wd := range r.ByDay {
+		var same []time.Time
+		for _, t := range all {
+			if t.Weekday() == wd.Day {
+				same = append(same, t)
+			}
+		}
+		switch {
+		case wd.N == 0:
+			out = append(out, same...)
+		case wd.N > 0 && wd.N <= len(same):
+			out = append(out, same[wd.N-1])
+		case wd.N < 0 && -wd.N <= len(same):
+			out = append(out, same[len(same)+wd.N])
+		}
+	}
+	slices.SortFunc(out, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(out, time.Time.Equal)
+}
+
+func (r *Rule) matchesDay(wd time.Weekday) bool {
+	return slices.ContainsFunc(r.ByDay, func(d WeekdayNum) bool { return d.Day == wd })
+}
+
+// day строит дату y-m-d со временем суток и зоной из dtstart
+func day(dtstart time.Time, y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), dtstart.Nanosecond(), dtstart.Location())
+}
+
+// validDay возвращает дату, только если она существует (31 февраля пропускается, как требует RFC 5545)
+func validDay(dtstart time.Time, y int, m time.Month, d int) []time.Time {
+	t := day(dtstart, y, m, d)
+	if t.Month() != m {
+		return nil
+	}
+	return []time.Time{t}
+}
@@ -0,0 +1,221 @@
+package recurrence_test
+
+import (
+	"testing"
+	"time"
+
+	"calendar/internal/models"
+	"calendar/internal/recurrence"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(recurrence.DateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func dates(ts []time.Time) []string {
+	out := make([]string, 0, len(ts))
+	for _, t := range ts {
+		out = append(out, t.Format(recurrence.DateLayout))
+	}
+	return out
+}
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		rrule    string
+		dtstart  string
+		from, to string
+		want     []string
+	}{
+		{
+			name:    "еженедельно по понедельникам и четвергам",
+			rrule:   "FREQ=WEEKLY;BYDAY=MO,TH",
+			dtstart: "2025-10-20", from: "2025-10-20", to: "2025-11-02",
+			want: []string{"2025-10-20", "2025-10-23", "2025-10-27", "2025-10-30"},
+		},
+		{
+			name:    "раз в два дня, три раза",
+			rrule:   "FREQ=DAILY;INTERVAL=2;COUNT=3",
+			dtstart: "2025-10-01", from: "2025-10-01", to: "2025-12-31",
+			want: []string{"2025-10-01", "2025-10-03", "2025-10-05"},
+		},
+		{
+			name:    "COUNT считается от начала серии, а не от начала диапазона",
+			rrule:   "FREQ=DAILY;COUNT=5",
+			dtstart: "2025-10-01", from: "2025-10-04", to: "2025-12-31",
+			want: []string{"2025-10-04", "2025-10-05"},
+		},
+		{
+			name:    "UNTIL включительно",
+			rrule:   "FREQ=WEEKLY;UNTIL=20251015",
+			dtstart: "2025-10-01", from: "2025-09-01", to: "2025-12-31",
+			want: []string{"2025-10-01", "2025-10-08", "2025-10-15"},
+		},
+		{
+			name:    "последняя пятница месяца",
+			rrule:   "FREQ=MONTHLY;BYDAY=-1FR",
+			dtstart: "2025-10-01", from: "2025-10-01", to: "2025-12-31",
+			want: []string{"2025-10-31", "2025-11-28", "2025-12-26"},
+		},
+		{
+			name:    "31 число пропускает короткие месяцы",
+			rrule:   "FREQ=MONTHLY",
+			dtstart: "2025-10-31", from: "2025-10-01", to: "2026-01-31",
+			want: []string{"2025-10-31", "2025-12-31", "2026-01-31"},
+		},
+		{
+			name:    "ежегодно",
+			rrule:   "FREQ=YEARLY",
+			dtstart: "2024-02-29", from: "2024-01-01", to: "2028-12-31",
+			want: []string{"2024-02-29", "2028-02-29"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := recurrence.Parse(tt.rrule)
+			require.NoError(t, err)
+			got := rule.Between(date(tt.dtstart), date(tt.from), date(tt.to))
+			assert.Equal(t, tt.want, dates(got))
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTH=1",
+	} {
+		_, err := recurrence.Parse(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	rule, err := recurrence.Parse("RRULE:freq=monthly;interval=2;byday=1MO,-1FR;until=20261231T000000Z")
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=MONTHLY;INTERVAL=2;BYDAY=1MO,-1FR;UNTIL=20261231T000000Z", rule.String())
+}
+
+func TestExpand(t *testing.T) {
+	parentID := 1
+	moved := date("2025-10-23")
+	events := []*models.Event{
+		{
+			ID: 1, Start: date("2025-10-20"), End: date("2025-10-21"), AllDay: true, Title: "Стендап",
+			RRule: "FREQ=WEEKLY;BYDAY=MO,TH", ExDates: []time.Time{date("2025-10-27")},
+		},
+		{ID: 2, Start: date("2025-10-24"), End: date("2025-10-25"), AllDay: true, Title: "Стендап (перенесён)", ParentID: &parentID, RecurrenceID: &moved},
+		{ID: 3, Start: date("2025-10-21"), End: date("2025-10-22"), AllDay: true, Title: "Разовая встреча"},
+	}
+
+	got, err := recurrence.Expand(events, date("2025-10-20"), date("2025-11-03"))
+	require.NoError(t, err)
+
+	var titles []string
+	for _, ev := range got {
+		titles = append(titles, ev.Start.Format(recurrence.DateLayout)+" "+ev.Title)
+	}
+	assert.Equal(t, []string{
+		"2025-10-20 Стендап",
+		"2025-10-21 Разовая встреча",
+		"2025-10-24 Стендап (перенесён)",
+		"2025-10-30 Стендап",
+	}, titles)
+	assert.Equal(t, 1, got[0].ID)
+	assert.Equal(t, date("2025-10-20"), *got[0].RecurrenceID)
+}
+
+func TestExpand_TimeZones(t *testing.T) {
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	vladivostok, _ := time.LoadLocation("Asia/Vladivostok")
+
+	// в Берлине 26 октября 2025 переход на зимнее время: повторение сохраняет местные 09:00
+	standup := &models.Event{
+		ID: 1, Title: "Стендап", TimeZone: "Europe/Berlin", RRule: "FREQ=DAILY",
+		Start: time.Date(2025, 10, 24, 9, 0, 0, 0, berlin), End: time.Date(2025, 10, 24, 9, 30, 0, 0, berlin),
+	}
+	got, err := recurrence.Expand([]*models.Event{standup},
+		time.Date(2025, 10, 25, 0, 0, 0, 0, berlin), time.Date(2025, 10, 27, 0, 0, 0, 0, berlin))
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, 7, got[0].Start.UTC().Hour())
+	assert.Equal(t, 8, got[1].Start.UTC().Hour())
+
+	// событие в 01:00 по Москве для пользователя из Владивостока приходится на 08:00 того же дня
+	night := &models.Event{
+		ID: 2, Title: "Ночной релиз", TimeZone: "Europe/Moscow",
+		Start: time.Date(2025, 10, 21, 1, 0, 0, 0, moscow), End: time.Date(2025, 10, 21, 2, 0, 0, 0, moscow),
+	}
+	day := time.Date(2025, 10, 21, 0, 0, 0, 0, vladivostok)
+	got, err = recurrence.Expand([]*models.Event{night}, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	// а для пользователя в UTC - на 20 октября
+	utcDay := time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC)
+	got, err = recurrence.Expand([]*models.Event{night}, utcDay, utcDay.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	// событие на весь день попадает в свою дату в любом поясе
+	holiday := &models.Event{ID: 3, Title: "Выходной", AllDay: true, Start: date("2025-11-04"), End: date("2025-11-05")}
+	for _, loc := range []*time.Location{moscow, vladivostok, time.UTC} {
+		from := time.Date(2025, 11, 4, 0, 0, 0, 0, loc)
+		got, err = recurrence.Expand([]*models.Event{holiday}, from, from.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		assert.Len(t, got, 1, loc.String())
+
+		from = time.Date(2025, 11, 3, 0, 0, 0, 0, loc)
+		got, err = recurrence.Expand([]*models.Event{holiday}, from, from.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		assert.Empty(t, got, loc.String())
+	}
+}
+
+func TestNextStart(t *testing.T) {
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	series := &models.Event{
+		Start:    time.Date(2025, 10, 20, 10, 0, 0, 0, moscow),
+		End:      time.Date(2025, 10, 20, 11, 0, 0, 0, moscow),
+		TimeZone: "Europe/Moscow",
+		RRule:    "FREQ=DAILY;COUNT=5",
+		ExDates:  []time.Time{date("2025-10-22")},
+	}
+
+	next, ok := recurrence.NextStart(series, nil, time.Date(2025, 10, 21, 10, 0, 0, 0, moscow), time.UTC)
+	require.True(t, ok)
+	// 22 октября в исключениях
+	assert.Equal(t, time.Date(2025, 10, 23, 10, 0, 0, 0, moscow), next)
+
+	next, ok = recurrence.NextStart(series, []time.Time{date("2025-10-23")}, time.Date(2025, 10, 21, 10, 0, 0, 0, moscow), time.UTC)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 10, 24, 10, 0, 0, 0, moscow), next)
+
+	_, ok = recurrence.NextStart(series, nil, time.Date(2025, 10, 24, 10, 0, 0, 0, moscow), time.UTC)
+	assert.False(t, ok, "у серии из пяти вхождений больше нет вхождений")
+
+	// событие на весь день наступает в полночь своей даты в поясе пользователя
+	allDay := &models.Event{AllDay: true, Start: date("2025-10-25"), End: date("2025-10-26"), RRule: "FREQ=WEEKLY"}
+	next, ok = recurrence.NextStart(allDay, nil, time.Date(2025, 10, 25, 0, 30, 0, 0, moscow), moscow)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 11, 1, 0, 0, 0, 0, moscow), next)
+
+	single := &models.Event{Start: time.Date(2025, 10, 20, 10, 0, 0, 0, time.UTC)}
+	_, ok = recurrence.NextStart(single, nil, single.Start, time.UTC)
+	assert.False(t, ok, "разовое событие уже началось")
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "errors"
+
+// ErrNotFound запрошенная запись не найдена
+var ErrNotFound = errors.New("не найдено")
+
+// ErrVersionConflict запись изменилась после того, как клиент её прочитал
+var ErrVersionConflict = errors.New("событие изменено другим запросом, получите его заново")
@@ -8,8 +8,10 @@ import (
 
 	"calendar/internal/config"
 	"calendar/internal/models"
+	"calendar/internal/recurrence"
+	"calendar/internal/repository"
 
-	_ "github.com/lib/pq" // драйвер
+	"github.com/lib/pq"
 )
 
 // PostgresStorage реализует хранилище в соответствии с интерфейсом storage
@@ -38,67 +40,221 @@ func NewPostgresStorage(cfg *config.DBPSQLConfig) (*PostgresStorage, error) {
 	return &PostgresStorage{db: db}, nil
 }
 
+// eventColumns столбцы, которые читает scanEvent
+const eventColumns = `id, uid, user_id, start_at, end_at, all_day, time_zone, title, description, tags, rrule, exdates, parent_id, recurrence_id, reminders, version, created_at, updated_at`
+
+// scanner общий интерфейс sql.Row и sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEvent(row scanner) (*models.Event, error) {
+	event := &models.Event{}
+	var (
+		tags         pq.StringArray
+		exdates      pq.StringArray
+		parentID     sql.NullInt64
+		recurrenceID sql.NullTime
+		reminders    pq.Int64Array
+	)
+	err := row.Scan(
+		&event.ID, &event.UID, &event.UserID, &event.Start, &event.End, &event.AllDay, &event.TimeZone,
+		&event.Title, &event.Description, &tags, &event.RRule, &exdates,
+		&parentID, &recurrenceID, &reminders, &event.Version, &event.CreatedAt, &event.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) > 0 {
+		event.Tags = []string(tags)
+	}
+	for _, s := range exdates {
+		d, err := time.Parse(recurrence.DateLayout, s)
+		if err != nil {
+			return nil, fmt.Errorf("некорректная дата исключения %q: %w", s, err)
+		}
+		event.ExDates = append(event.ExDates, d)
+	}
+	for _, seconds := range reminders {
+		event.Reminders = append(event.Reminders, models.ReminderOffset(time.Duration(seconds)*time.Second))
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		event.ParentID = &id
+	}
+	if recurrenceID.Valid {
+		date := models.DateOf(recurrenceID.Time)
+		event.RecurrenceID = &date
+	}
+	if event.AllDay {
+		event.Start, event.End = event.Start.UTC(), event.End.UTC()
+	}
+	return event, nil
+}
+
+// dateArray переводит даты в массив для столбца date[]
+func dateArray(dates []time.Time) pq.StringArray {
+	arr := pq.StringArray{}
+	for _, d := range dates {
+		arr = append(arr, d.Format(recurrence.DateLayout))
+	}
+	return arr
+}
+
+// reminderArray переводит смещения напоминаний в секунды для столбца integer[]
+func reminderArray(offsets []models.ReminderOffset) pq.Int64Array {
+	arr := pq.Int64Array{}
+	for _, o := range offsets {
+		arr = append(arr, int64(time.Duration(o)/time.Second))
+	}
+	return arr
+}
+
+// tagArray переводит метки в значение для столбца text[], nil сохраняется как пустой массив
+func tagArray(tags []string) pq.StringArray {
+	if tags == nil {
+		return pq.StringArray{}
+	}
+	return pq.StringArray(tags)
+}
+
+// nullDate переводит необязательную дату в значение для столбца date
+func nullDate(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.Format(recurrence.DateLayout), Valid: true}
+}
+
+// nullID переводит необязательный идентификатор в значение для столбца
+func nullID(id *int) sql.NullInt64 {
+	if id == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*id), Valid: true}
+}
+
 // CreateEvent функция для создания записи в календаре
 func (p *PostgresStorage) CreateEvent(ctx context.Context, event *models.Event) (*models.Event, error) {
+	return p.createEvent(ctx, p.db, event)
+}
+
+// execer общий интерфейс sql.DB и sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (p *PostgresStorage) createEvent(ctx context.Context, db execer, event *models.Event) (*models.Event, error) {
 	query := `
-		INSERT INTO events (user_id, date, title, created_at, updated_at)
-		VALUES ($1, $2, $3, NOW(), NOW())
-		RETURNING id, created_at, updated_at
+		INSERT INTO events (uid, user_id, start_at, end_at, all_day, time_zone, title, description, tags, rrule, exdates,
+			parent_id, recurrence_id, reminders, created_at, updated_at)
+		VALUES (COALESCE(NULLIF($1, ''), gen_random_uuid()::text), $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW(), NOW())
+		RETURNING id, uid, version, created_at, updated_at
 	`
 
-	row := p.db.QueryRowContext(ctx, query, event.UserID, event.Date, event.Title)
-	err := row.Scan(&event.ID, &event.CreatedAt, &event.UpdatedAt)
+	row := db.QueryRowContext(ctx, query, event.UID, event.UserID, event.Start, event.End, event.AllDay,
+		event.TimeZone, event.Title, event.Description, tagArray(event.Tags), event.RRule,
+		dateArray(event.ExDates), nullID(event.ParentID), nullDate(event.RecurrenceID), reminderArray(event.Reminders))
+	err := row.Scan(&event.ID, &event.UID, &event.Version, &event.CreatedAt, &event.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("не удалось создать событие: %w", err)
 	}
+	if err := syncReminders(ctx, db, event.ID); err != nil {
+		return nil, err
+	}
+	if err := syncAttendees(ctx, db, event); err != nil {
+		return nil, err
+	}
 
 	return event, nil
 }
 
 // GetEvent функция для получения записи в календаре
 func (p *PostgresStorage) GetEvent(ctx context.Context, eventID int) (*models.Event, error) {
-	query := `SELECT id, user_id, date, title, created_at, updated_at FROM events WHERE id=$1`
-	event := &models.Event{}
-	err := p.db.QueryRowContext(ctx, query, eventID).Scan(
-		&event.ID, &event.UserID, &event.Date, &event.Title, &event.CreatedAt, &event.UpdatedAt,
-	)
+	query := `SELECT ` + eventColumns + ` FROM events WHERE id=$1`
+	event, err := scanEvent(p.db.QueryRowContext(ctx, query, eventID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("событие не найдено")
+			return nil, fmt.Errorf("событие %w", repository.ErrNotFound)
 		}
 		return nil, fmt.Errorf("не удалось получить событие: %w", err)
 	}
+	if err := p.loadAttendees(ctx, []*models.Event{event}); err != nil {
+		return nil, err
+	}
 
 	return event, nil
 }
 
-// UpdateEvent функция для обновления записи в календаре
+// UpdateEvent функция для обновления записи в календаре. Если задана event.Version, событие
+// обновляется, только пока его версия совпадает, иначе возвращается repository.ErrVersionConflict
 func (p *PostgresStorage) UpdateEvent(ctx context.Context, event *models.Event) error {
+	return p.updateEvent(ctx, p.db, event)
+}
+
+func (p *PostgresStorage) updateEvent(ctx context.Context, db execer, event *models.Event) error {
 	query := `
 		UPDATE events
-		SET user_id=$1, date=$2, title=$3, updated_at=NOW()
-		WHERE id=$4
+		SET user_id=$1, start_at=$2, end_at=$3, all_day=$4, time_zone=$5, title=$6, description=$7, tags=$8,
+			rrule=$9, exdates=$10, reminders=$11, version=version+1, updated_at=NOW()
+		WHERE id=$12 AND ($13 = 0 OR version=$13)
+		RETURNING version, updated_at
 	`
-	res, err := p.db.ExecContext(ctx, query, event.UserID, event.Date, event.Title, event.ID)
+	row := db.QueryRowContext(ctx, query, event.UserID, event.Start, event.End, event.AllDay, event.TimeZone,
+		event.Title, event.Description, tagArray(event.Tags), event.RRule, dateArray(event.ExDates),
+		reminderArray(event.Reminders), event.ID, event.Version)
+	err := row.Scan(&event.Version, &event.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return missingEventError(ctx, db, event.ID)
+	}
 	if err != nil {
 		return fmt.Errorf("не удалось обновить событие: %w", err)
 	}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("не удалось получить количество обновлённых строк: %w", err)
-	}
-	if rowsAffected == 0 {
-		return fmt.Errorf("событие не найдено")
+	if err := syncReminders(ctx, db, event.ID); err != nil {
+		return err
 	}
+	return syncAttendees(ctx, db, event)
+}
 
-	return nil
+// UpsertByUID создаёт серию или разовое событие пользователя либо обновляет уже существующее
+// с тем же UID, поэтому повторный импорт одного и того же файла не создаёт дублей
+func (p *PostgresStorage) UpsertByUID(ctx context.Context, event *models.Event) (*models.Event, error) {
+	if event.UID == "" {
+		return nil, fmt.Errorf("у события должен быть задан uid")
+	}
+	query := `
+		INSERT INTO events (uid, user_id, start_at, end_at, all_day, time_zone, title, description, tags,
+			rrule, exdates, reminders, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())
+		ON CONFLICT (user_id, uid) WHERE parent_id IS NULL
+		DO UPDATE SET start_at=EXCLUDED.start_at, end_at=EXCLUDED.end_at, all_day=EXCLUDED.all_day,
+			time_zone=EXCLUDED.time_zone, title=EXCLUDED.title, description=EXCLUDED.description,
+			tags=EXCLUDED.tags, rrule=EXCLUDED.rrule,
+			exdates=EXCLUDED.exdates, reminders=EXCLUDED.reminders, version=events.version+1, updated_at=NOW()
+		RETURNING id, version, created_at, updated_at
+	`
+	err := p.inTx(ctx, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, query, event.UID, event.UserID, event.Start, event.End, event.AllDay,
+			event.TimeZone, event.Title, event.Description, tagArray(event.Tags), event.RRule,
+			dateArray(event.ExDates), reminderArray(event.Reminders))
+		if err := row.Scan(&event.ID, &event.Version, &event.CreatedAt, &event.UpdatedAt); err != nil {
+			return fmt.Errorf("не удалось сохранить событие %s: %w", event.UID, err)
+		}
+		return syncReminders(ctx, tx, event.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
 }
 
-// DeleteEvent функция для удаления записи из календаря
-func (p *PostgresStorage) DeleteEvent(ctx context.Context, eventID int) error {
-	query := `DELETE FROM events WHERE id=$1`
-	res, err := p.db.ExecContext(ctx, query, eventID)
+// DeleteEvent функция для удаления записи из календаря, переопределения вхождений удаляются каскадно.
+// При version != 0 событие удаляется, только если его версия совпадает
+func (p *PostgresStorage) DeleteEvent(ctx context.Context, eventID, version int) error {
+	query := `DELETE FROM events WHERE id=$1 AND ($2 = 0 OR version=$2)`
+	res, err := p.db.ExecContext(ctx, query, eventID, version)
 	if err != nil {
 		return fmt.Errorf("не удалось удалить событие: %w", err)
 	}
@@ -108,18 +264,154 @@ func (p *PostgresStorage) DeleteEvent(ctx context.Context, eventID int) error {
 		return fmt.Errorf("не удалось получить количество удалённых строк: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("событие не найдено")
+		return missingEventError(ctx, p.db, eventID)
 	}
 
 	return nil
 }
 
-func (p *PostgresStorage) eventsByRange(ctx context.Context, userID int, start, end time.Time) ([]*models.Event, error) {
-	query := `SELECT id, user_id, date, title, created_at, updated_at 
+// missingEventError объясняет, почему условное изменение не затронуло событие:
+// его нет или у него уже другая версия
+func missingEventError(ctx context.Context, db execer, eventID int) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM events WHERE id=$1)`, eventID).Scan(&exists); err != nil {
+		return fmt.Errorf("не удалось проверить событие: %w", err)
+	}
+	if exists {
+		return repository.ErrVersionConflict
+	}
+	return fmt.Errorf("событие %w", repository.ErrNotFound)
+}
+
+// SaveOverride создаёт или заменяет переопределение одного вхождения серии
+func (p *PostgresStorage) SaveOverride(ctx context.Context, event *models.Event) (*models.Event, error) {
+	if event.ParentID == nil || event.RecurrenceID == nil {
+		return nil, fmt.Errorf("у переопределения должны быть заданы parent_id и recurrence_id")
+	}
+	query := `
+		INSERT INTO events (user_id, start_at, end_at, all_day, time_zone, title, description, tags,
+			parent_id, recurrence_id, reminders, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW())
+		ON CONFLICT (parent_id, recurrence_id) WHERE parent_id IS NOT NULL
+		DO UPDATE SET start_at=EXCLUDED.start_at, end_at=EXCLUDED.end_at, all_day=EXCLUDED.all_day,
+			time_zone=EXCLUDED.time_zone, title=EXCLUDED.title, description=EXCLUDED.description,
+			tags=EXCLUDED.tags, reminders=EXCLUDED.reminders, updated_at=NOW()
+		RETURNING id, created_at, updated_at
+	`
+	err := p.inTx(ctx, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, query, event.UserID, event.Start, event.End, event.AllDay, event.TimeZone,
+			event.Title, event.Description, tagArray(event.Tags), *event.ParentID, nullDate(event.RecurrenceID),
+			reminderArray(event.Reminders))
+		if err := row.Scan(&event.ID, &event.CreatedAt, &event.UpdatedAt); err != nil {
+			return fmt.Errorf("не удалось сохранить вхождение: %w", err)
+		}
+		// напоминания серии о переопределённой дате больше не отправляются
+		if err := syncReminders(ctx, tx, *event.ParentID); err != nil {
+			return err
+		}
+		return syncReminders(ctx, tx, event.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// DeleteOccurrence отменяет одно вхождение серии: добавляет дату в исключения
+// и удаляет её переопределение, если оно было
+func (p *PostgresStorage) DeleteOccurrence(ctx context.Context, eventID int, recurrenceID time.Time) error {
+	return p.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE events
+			SET exdates = CASE WHEN $2::date = ANY(exdates) THEN exdates ELSE array_append(exdates, $2::date) END,
+				version = version + 1, updated_at = NOW()
+			WHERE id=$1 AND rrule <> ''`, eventID, recurrenceID.Format(recurrence.DateLayout))
+		if err != nil {
+			return fmt.Errorf("не удалось добавить исключение: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("повторяющееся событие %w", repository.ErrNotFound)
+		}
+		_, err = tx.ExecContext(ctx, `DELETE FROM events WHERE parent_id=$1 AND recurrence_id=$2`,
+			eventID, recurrenceID.Format(recurrence.DateLayout))
+		if err != nil {
+			return fmt.Errorf("не удалось удалить переопределение вхождения: %w", err)
+		}
+		return syncReminders(ctx, tx, eventID)
+	})
+}
+
+// SplitSeries в одной транзакции обновляет серию master, обрезанную до at, и создаёт
+// продолжение following, к которому переходят переопределения вхождений начиная с at.
+// Если following == nil, эти переопределения удаляются
+func (p *PostgresStorage) SplitSeries(ctx context.Context, master, following *models.Event, at time.Time) (*models.Event, error) {
+	atDate := at.Format(recurrence.DateLayout)
+	err := p.inTx(ctx, func(tx *sql.Tx) error {
+		if err := p.updateEvent(ctx, tx, master); err != nil {
+			return err
+		}
+		if following == nil {
+			_, err := tx.ExecContext(ctx, `DELETE FROM events WHERE parent_id=$1 AND recurrence_id >= $2`, master.ID, atDate)
+			if err != nil {
+				return fmt.Errorf("не удалось удалить переопределения вхождений: %w", err)
+			}
+			return nil
+		}
+		if _, err := p.createEvent(ctx, tx, following); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE events SET parent_id=$1, updated_at=NOW() WHERE parent_id=$2 AND recurrence_id >= $3`,
+			following.ID, master.ID, atDate)
+		if err != nil {
+			return fmt.Errorf("не удалось перенести переопределения вхождений: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return following, nil
+}
+
+func (p *PostgresStorage) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
+	}
+	return nil
+}
+
+// EventsBetween возвращает события пользователя, пересекающиеся с [from, to), с развёрнутыми сериями
+func (p *PostgresStorage) EventsBetween(ctx context.Context, userID int, from, to time.Time) ([]*models.Event, error) {
+	return p.eventsByRange(ctx, userID, from, to)
+}
+
+// eventsByRange выбирает события пользователя и события, на которые он приглашён и не отказался,
+// пересекающиеся с [from, to), серии, начавшиеся до его конца, и переопределения вхождений,
+// после чего разворачивает серии во вхождения.
+// События на весь день сравниваются с датами диапазона в поясе from
+func (p *PostgresStorage) eventsByRange(ctx context.Context, userID int, from, to time.Time) ([]*models.Event, error) {
+	floatFrom, floatTo := recurrence.FloatingRange(from, to)
+	query := `SELECT ` + eventColumns + `
 			  FROM events
-			  WHERE user_id=$1 AND date >= $2 AND date <= $3
-			  ORDER BY date`
-	rows, err := p.db.QueryContext(ctx, query, userID, start, end)
+			  WHERE (user_id=$1 OR COALESCE(parent_id, id) IN (
+				  SELECT event_id FROM attendees WHERE user_id=$1 AND status <> 'declined'
+			  )) AND (
+				  (rrule = '' AND NOT all_day AND start_at < $3 AND (end_at > $2 OR start_at >= $2))
+				  OR (rrule = '' AND all_day AND start_at < $5 AND end_at > $4)
+				  OR (rrule <> '' AND start_at < GREATEST($3, $5))
+				  OR (parent_id IS NOT NULL AND recurrence_id >= $6::date - 1 AND recurrence_id <= $7::date + 1)
+			  )
+			  ORDER BY start_at`
+	rows, err := p.db.QueryContext(ctx, query, userID, from, to, floatFrom, floatTo,
+		floatFrom.Format(recurrence.DateLayout), floatTo.Format(recurrence.DateLayout))
 	if err != nil {
 		return nil, fmt.Errorf("не удалось получить события: %w", err)
 	}
@@ -127,49 +419,95 @@ func (p *PostgresStorage) eventsByRange(ctx context.Context, userID int, start,
 
 	var events []*models.Event
 	for rows.Next() {
-		ev := &models.Event{}
-		if err := rows.Scan(&ev.ID, &ev.UserID, &ev.Date, &ev.Title, &ev.CreatedAt, &ev.UpdatedAt); err != nil {
+		ev, err := scanEvent(rows)
+		if err != nil {
 			return nil, fmt.Errorf("не удалось считать событие: %w", err)
 		}
 		events = append(events, ev)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось получить события: %w", err)
+	}
+	if err := p.loadAttendees(ctx, events); err != nil {
+		return nil, err
+	}
+
+	return recurrence.Expand(events, from, to)
+}
 
+// EventsByUser возвращает все события пользователя без разворачивания серий: серии,
+// разовые события и переопределения вхождений
+func (p *PostgresStorage) EventsByUser(ctx context.Context, userID int) ([]*models.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM events WHERE user_id=$1 ORDER BY parent_id NULLS FIRST, start_at, id`
+	rows, err := p.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить события: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.Event
+	for rows.Next() {
+		ev, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось считать событие: %w", err)
+		}
+		events = append(events, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось получить события: %w", err)
+	}
+	if err := p.loadAttendees(ctx, events); err != nil {
+		return nil, err
+	}
 	return events, nil
 }
 
-// EventsForDay функция для получения всех событий за ближайший день
+// EventsForDay функция для получения всех событий за день, границы дня считаются в часовом поясе пользователя
 func (p *PostgresStorage) EventsForDay(ctx context.Context, userID int, dateStr string) ([]*models.Event, error) {
-	date, err := time.Parse("2006-01-02", dateStr)
+	date, err := p.userDate(ctx, userID, dateStr)
 	if err != nil {
-		return nil, fmt.Errorf("некорректный формат даты")
+		return nil, err
 	}
-	start := date
-	end := date
-	return p.eventsByRange(ctx, userID, start, end)
+	return p.eventsByRange(ctx, userID, date, date.AddDate(0, 0, 1))
 }
 
-// EventsForWeek функция для получения всех событий за ближайшую неделю
+// EventsForWeek функция для получения всех событий за неделю (с понедельника), содержащую дату
 func (p *PostgresStorage) EventsForWeek(ctx context.Context, userID int, dateStr string) ([]*models.Event, error) {
-	date, err := time.Parse("2006-01-02", dateStr)
+	date, err := p.userDate(ctx, userID, dateStr)
 	if err != nil {
-		return nil, fmt.Errorf("некорректный формат даты")
+		return nil, err
 	}
 	weekday := int(date.Weekday())
 	if weekday == 0 {
 		weekday = 7
 	}
 	start := date.AddDate(0, 0, -weekday+1)
-	end := start.AddDate(0, 0, 6)
-	return p.eventsByRange(ctx, userID, start, end)
+	return p.eventsByRange(ctx, userID, start, start.AddDate(0, 0, 7))
 }
 
-// EventsForMonth функция для получения всех событий за ближайший месяц
+// EventsForMonth функция для получения всех событий за месяц, содержащий дату
 func (p *PostgresStorage) EventsForMonth(ctx context.Context, userID int, dateStr string) ([]*models.Event, error) {
-	date, err := time.Parse("2006-01-02", dateStr)
+	date, err := p.userDate(ctx, userID, dateStr)
 	if err != nil {
-		return nil, fmt.Errorf("некорректный формат даты")
+		return nil, err
 	}
 	start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
-	end := start.AddDate(0, 1, -1)
-	return p.eventsByRange(ctx, userID, start, end)
+	return p.eventsByRange(ctx, userID, start, start.AddDate(0, 1, 0))
+}
+
+// userDate разбирает дату как полночь в часовом поясе пользователя
+func (p *PostgresStorage) userDate(ctx context.Context, userID int, dateStr string) (time.Time, error) {
+	tz, err := p.UserTimeZone(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("некорректный часовой пояс пользователя %q: %w", tz, err)
+	}
+	date, err := time.ParseInLocation("2006-01-02", dateStr, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("некорректный формат даты")
+	}
+	return date, nil
 }
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"calendar/internal/models"
+	"calendar/internal/recurrence"
+
+	"github.com/lib/pq"
+)
+
+// syncReminders приводит строки напоминаний события к его списку смещений.
+// Время отправки оставшихся напоминаний сбрасывается, планировщик рассчитает его заново
+func syncReminders(ctx context.Context, db execer, eventID int) error {
+	_, err := db.ExecContext(ctx, `
+		DELETE FROM reminders r USING events e
+		WHERE r.event_id=e.id AND e.id=$1 AND NOT (r.offset_seconds = ANY(e.reminders))`, eventID)
+	if err != nil {
+		return fmt.Errorf("не удалось удалить напоминания события: %w", err)
+	}
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO reminders (event_id, offset_seconds)
+		SELECT id, unnest(reminders) FROM events WHERE id=$1
+		ON CONFLICT (event_id, offset_seconds)
+		DO UPDATE SET fire_at=NULL, occurrence_at=NULL, attempts=0, done=false, lease=NULL, locked_until=NULL`, eventID)
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить напоминания события: %w", err)
+	}
+	return nil
+}
+
+// prefixedScanner дописывает перед столбцами события дополнительные столбцы строки
+type prefixedScanner struct {
+	row   scanner
+	extra []any
+}
+
+func (s prefixedScanner) Scan(dest ...any) error {
+	return s.row.Scan(append(s.extra, dest...)...)
+}
+
+// ClaimReminders захватывает до limit напоминаний, которые пора отправить или для которых
+// ещё не рассчитано время отправки. Строки выбираются с FOR UPDATE SKIP LOCKED и помечаются
+// арендой на lease, поэтому несколько экземпляров сервиса не возьмут одно напоминание дважды,
+// а напоминание упавшего экземпляра вернётся в работу после истечения аренды
+func (p *PostgresStorage) ClaimReminders(ctx context.Context, limit int, lease time.Duration) ([]*models.DueReminder, error) {
+	query := `
+		WITH due AS (
+			SELECT id FROM reminders
+			WHERE NOT done AND (fire_at IS NULL OR fire_at <= NOW()) AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY fire_at NULLS FIRST
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE reminders r
+		SET lease=gen_random_uuid()::text, locked_until=NOW() + make_interval(secs => $2)
+		FROM due, events e
+		LEFT JOIN users u ON u.id=e.user_id
+		WHERE r.id=due.id AND e.id=r.event_id
+		RETURNING r.id, r.offset_seconds, r.fire_at, r.occurrence_at, r.last_occurrence_at, r.attempts, r.lease,
+			COALESCE(u.time_zone, '` + DefaultTimeZone + `'), COALESCE(u.email, ''),
+			ARRAY(SELECT o.recurrence_id FROM events o WHERE o.parent_id=e.id),
+			e.` + strings.ReplaceAll(eventColumns, ", ", ", e.")
+
+	rows, err := p.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("не удалось захватить напоминания: %w", err)
+	}
+	defer rows.Close()
+
+	var due []*models.DueReminder
+	for rows.Next() {
+		var (
+			d          models.DueReminder
+			offset     int64
+			fireAt     sql.NullTime
+			occurrence sql.NullTime
+			last       sql.NullTime
+			overridden pq.StringArray
+		)
+		d.Event, err = scanEvent(prefixedScanner{row: rows, extra: []any{
+			&d.ID, &offset, &fireAt, &occurrence, &last, &d.Attempts, &d.Lease,
+			&d.UserTimeZone, &d.UserEmail, &overridden,
+		}})
+		if err != nil {
+			return nil, fmt.Errorf("не удалось считать напоминание: %w", err)
+		}
+		d.EventID = d.Event.ID
+		d.Offset = models.ReminderOffset(time.Duration(offset) * time.Second)
+		d.FireAt = nullTimePtr(fireAt)
+		d.OccurrenceAt = nullTimePtr(occurrence)
+		d.LastOccurrenceAt = nullTimePtr(last)
+		for _, s := range overridden {
+			date, err := time.Parse(recurrence.DateLayout, s[:len(recurrence.DateLayout)])
+			if err != nil {
+				return nil, fmt.Errorf("некорректная дата переопределения %q: %w", s, err)
+			}
+			d.Overridden = append(d.Overridden, date)
+		}
+		due = append(due, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось захватить напоминания: %w", err)
+	}
+	return due, nil
+}
+
+// SaveReminder сохраняет рассчитанное планировщиком состояние напоминания и снимает аренду.
+// Если за время аренды событие изменили, строка уже сброшена и сохранять нечего
+func (p *PostgresStorage) SaveReminder(ctx context.Context, r *models.Reminder) error {
+	query := `
+		UPDATE reminders
+		SET fire_at=$1, occurrence_at=$2, last_occurrence_at=$3, attempts=$4, done=$5, lease=NULL, locked_until=NULL
+		WHERE id=$6 AND lease=$7
+	`
+	_, err := p.db.ExecContext(ctx, query, r.FireAt, r.OccurrenceAt, r.LastOccurrenceAt, r.Attempts, r.Done, r.ID, r.Lease)
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить напоминание: %w", err)
+	}
+	return nil
+}
+
+// RecordDelivery сохраняет попытку отправки напоминания
+func (p *PostgresStorage) RecordDelivery(ctx context.Context, d *models.ReminderDelivery) error {
+	query := `
+		INSERT INTO reminder_deliveries (reminder_id, occurrence_at, attempt, notifier, error)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+	err := p.db.QueryRowContext(ctx, query, d.ReminderID, d.OccurrenceAt, d.Attempt, d.Notifier, d.Error).Scan(&d.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить попытку отправки напоминания: %w", err)
+	}
+	return nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"calendar/internal/models"
+)
+
+// searchConfig конфигурация полнотекстового поиска, совпадает с той, по которой строится events.search
+const searchConfig = "russian"
+
+// searchOrder сортировки результатов поиска; значения подставляются в запрос, поэтому только из этого списка
+var searchOrder = map[models.SearchSort]string{
+	models.SortRelevance: `ts_rank(search, websearch_to_tsquery('` + searchConfig + `', $2)) DESC, start_at DESC, id`,
+	models.SortStart:     `start_at, id`,
+	models.SortStartDesc: `start_at DESC, id DESC`,
+}
+
+// searchConditions условия поиска: $1 пользователь, $2 запрос, $3 метки, $4 и $5 границы времени
+const searchConditions = `
+	FROM events
+	WHERE (user_id=$1 OR COALESCE(parent_id, id) IN (
+		SELECT event_id FROM attendees WHERE user_id=$1 AND status <> 'declined'
+	))
+	AND ($2 = '' OR search @@ websearch_to_tsquery('` + searchConfig + `', $2))
+	AND tags @> $3
+	AND ($4::timestamptz IS NULL OR end_at > $4 OR rrule <> '')
+	AND ($5::timestamptz IS NULL OR start_at < $5)`
+
+// SearchEvents ищет события пользователя и события, на которые он приглашён и не отказался, по тексту
+// названия и описания, меткам и времени. Серии и переопределения вхождений не разворачиваются:
+// каждая серия попадает в выдачу один раз, если она началась до конца диапазона
+func (p *PostgresStorage) SearchEvents(ctx context.Context, search models.EventSearch) (*models.EventSearchResult, error) {
+	order, ok := searchOrder[search.Sort]
+	if !ok {
+		return nil, fmt.Errorf("неизвестная сортировка %q", search.Sort)
+	}
+	args := []any{search.UserID, search.Query, tagArray(search.Tags), nullTime(search.From), nullTime(search.To)}
+	rows, err := p.db.QueryContext(ctx, `SELECT COUNT(*) OVER(), `+eventColumns+searchConditions+`
+		ORDER BY `+order+` LIMIT $6 OFFSET $7`, append(args, search.Limit, search.Offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось найти события: %w", err)
+	}
+	defer rows.Close()
+
+	result := &models.EventSearchResult{Events: []*models.Event{}}
+	for rows.Next() {
+		ev, err := scanEvent(prefixedScanner{row: rows, extra: []any{&result.Total}})
+		if err != nil {
+			return nil, fmt.Errorf("не удалось считать событие: %w", err)
+		}
+		result.Events = append(result.Events, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось найти события: %w", err)
+	}
+	if len(result.Events) == 0 && search.Offset > 0 {
+		// за пределами выдачи строк нет, и общее количество нужно посчитать отдельно
+		if err := p.db.QueryRowContext(ctx, `SELECT COUNT(*)`+searchConditions, args...).Scan(&result.Total); err != nil {
+			return nil, fmt.Errorf("не удалось посчитать события: %w", err)
+		}
+	}
+	if err := p.loadAttendees(ctx, result.Events); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// nullTime переводит нулевой момент в NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
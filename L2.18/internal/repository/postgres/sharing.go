@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"calendar/internal/models"
+	"calendar/internal/repository"
+
+	"github.com/lib/pq"
+)
+
+// syncAttendees приводит список участников события к event.Attendees. Ответы уже приглашённых
+// сохраняются, новые участники добавляются с переданным статусом. При Attendees == nil участники не меняются
+func syncAttendees(ctx context.Context, db execer, event *models.Event) error {
+	if event.Attendees == nil {
+		return nil
+	}
+	ids, statuses := pq.Int64Array{}, pq.StringArray{}
+	for _, a := range event.Attendees {
+		ids = append(ids, int64(a.UserID))
+		statuses = append(statuses, string(a.Status))
+	}
+	_, err := db.ExecContext(ctx, `DELETE FROM attendees WHERE event_id=$1 AND NOT (user_id = ANY($2))`, event.ID, ids)
+	if err != nil {
+		return fmt.Errorf("не удалось удалить участников события: %w", err)
+	}
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO attendees (event_id, user_id, status) SELECT $1, unnest($2::integer[]), unnest($3::text[])
+		ON CONFLICT (event_id, user_id) DO NOTHING`, event.ID, ids, statuses)
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить участников события: %w", err)
+	}
+	return nil
+}
+
+// loadAttendees заполняет участников событий, переопределения вхождений получают участников серии
+func (p *PostgresStorage) loadAttendees(ctx context.Context, events []*models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	seriesID := func(ev *models.Event) int {
+		if ev.ParentID != nil {
+			return *ev.ParentID
+		}
+		return ev.ID
+	}
+	ids := pq.Int64Array{}
+	for _, ev := range events {
+		ids = append(ids, int64(seriesID(ev)))
+	}
+
+	rows, err := p.db.QueryContext(ctx, `SELECT event_id, user_id, status FROM attendees WHERE event_id = ANY($1) ORDER BY user_id`, ids)
+	if err != nil {
+		return fmt.Errorf("не удалось получить участников событий: %w", err)
+	}
+	defer rows.Close()
+
+	attendees := make(map[int][]models.Attendee)
+	for rows.Next() {
+		var eventID int
+		var a models.Attendee
+		if err := rows.Scan(&eventID, &a.UserID, &a.Status); err != nil {
+			return fmt.Errorf("не удалось считать участника события: %w", err)
+		}
+		attendees[eventID] = append(attendees[eventID], a)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("не удалось получить участников событий: %w", err)
+	}
+
+	for _, ev := range events {
+		ev.Attendees = attendees[seriesID(ev)]
+	}
+	return nil
+}
+
+// SetAttendeeStatus сохраняет ответ участника на приглашение
+func (p *PostgresStorage) SetAttendeeStatus(ctx context.Context, eventID, userID int, status models.AttendeeStatus) error {
+	res, err := p.db.ExecContext(ctx, `UPDATE attendees SET status=$1, updated_at=NOW() WHERE event_id=$2 AND user_id=$3`,
+		status, eventID, userID)
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить ответ на приглашение: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("приглашение %w", repository.ErrNotFound)
+	}
+	return nil
+}
+
+// ShareCalendar открывает или меняет доступ пользователя к календарю владельца
+func (p *PostgresStorage) ShareCalendar(ctx context.Context, share *models.CalendarShare) error {
+	query := `
+		INSERT INTO calendar_shares (owner_id, user_id, permission) VALUES ($1, $2, $3)
+		ON CONFLICT (owner_id, user_id) DO UPDATE SET permission=EXCLUDED.permission
+	`
+	if _, err := p.db.ExecContext(ctx, query, share.OwnerID, share.UserID, share.Permission); err != nil {
+		return fmt.Errorf("не удалось открыть доступ к календарю: %w", err)
+	}
+	return nil
+}
+
+// UnshareCalendar закрывает доступ пользователя к календарю владельца
+func (p *PostgresStorage) UnshareCalendar(ctx context.Context, ownerID, userID int) error {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM calendar_shares WHERE owner_id=$1 AND user_id=$2`, ownerID, userID)
+	if err != nil {
+		return fmt.Errorf("не удалось закрыть доступ к календарю: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("разрешение на доступ к календарю %w", repository.ErrNotFound)
+	}
+	return nil
+}
+
+// CalendarShares возвращает пользователей, которым открыт календарь владельца
+func (p *PostgresStorage) CalendarShares(ctx context.Context, ownerID int) ([]*models.CalendarShare, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT owner_id, user_id, permission FROM calendar_shares WHERE owner_id=$1 ORDER BY user_id`, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить доступы к календарю: %w", err)
+	}
+	defer rows.Close()
+
+	var shares []*models.CalendarShare
+	for rows.Next() {
+		share := &models.CalendarShare{}
+		if err := rows.Scan(&share.OwnerID, &share.UserID, &share.Permission); err != nil {
+			return nil, fmt.Errorf("не удалось считать доступ к календарю: %w", err)
+		}
+		shares = append(shares, share)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось получить доступы к календарю: %w", err)
+	}
+	return shares, nil
+}
+
+// CalendarPermission возвращает права пользователя на календарь владельца, пустые - если доступа нет
+func (p *PostgresStorage) CalendarPermission(ctx context.Context, ownerID, userID int) (models.Permission, error) {
+	var perm models.Permission
+	err := p.db.QueryRowContext(ctx, `SELECT permission FROM calendar_shares WHERE owner_id=$1 AND user_id=$2`, ownerID, userID).Scan(&perm)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("не удалось проверить доступ к календарю: %w", err)
+	}
+	return perm, nil
+}
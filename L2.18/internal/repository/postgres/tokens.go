@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// UserByToken возвращает владельца действующего (не отозванного и не истёкшего) токена
+func (p *PostgresStorage) UserByToken(ctx context.Context, tokenHash string) (int, error) {
+	query := `
+		UPDATE api_tokens SET last_used_at=NOW()
+		WHERE token_hash=$1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING user_id
+	`
+	var userID int
+	err := p.db.QueryRowContext(ctx, query, tokenHash).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("токен не найден")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("не удалось проверить токен: %w", err)
+	}
+	return userID, nil
+}
+
+// CreateToken сохраняет хэш нового токена пользователя, expiresAt может быть nil для бессрочного
+func (p *PostgresStorage) CreateToken(ctx context.Context, userID int, tokenHash, name string, expiresAt *time.Time) error {
+	query := `INSERT INTO api_tokens (token_hash, user_id, name, expires_at) VALUES ($1, $2, $3, $4)`
+	if _, err := p.db.ExecContext(ctx, query, tokenHash, userID, name, expiresAt); err != nil {
+		return fmt.Errorf("не удалось сохранить токен: %w", err)
+	}
+	return nil
+}
+
+// RevokeToken отзывает токен
+func (p *PostgresStorage) RevokeToken(ctx context.Context, tokenHash string) error {
+	res, err := p.db.ExecContext(ctx, `UPDATE api_tokens SET revoked_at=NOW() WHERE token_hash=$1 AND revoked_at IS NULL`, tokenHash)
+	if err != nil {
+		return fmt.Errorf("не удалось отозвать токен: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("токен не найден")
+	}
+	return nil
+}
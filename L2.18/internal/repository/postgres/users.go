@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DefaultTimeZone часовой пояс пользователей, которые его не задали
+const DefaultTimeZone = "UTC"
+
+// UserTimeZone возвращает часовой пояс пользователя, либо DefaultTimeZone, если он не задан
+func (p *PostgresStorage) UserTimeZone(ctx context.Context, userID int) (string, error) {
+	var tz string
+	err := p.db.QueryRowContext(ctx, `SELECT time_zone FROM users WHERE id=$1`, userID).Scan(&tz)
+	if err == sql.ErrNoRows {
+		return DefaultTimeZone, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("не удалось получить часовой пояс пользователя: %w", err)
+	}
+	return tz, nil
+}
+
+// SetUserTimeZone сохраняет часовой пояс пользователя
+func (p *PostgresStorage) SetUserTimeZone(ctx context.Context, userID int, tz string) error {
+	query := `
+		INSERT INTO users (id, time_zone) VALUES ($1, $2)
+		ON CONFLICT (id) DO UPDATE SET time_zone=EXCLUDED.time_zone
+	`
+	if _, err := p.db.ExecContext(ctx, query, userID, tz); err != nil {
+		return fmt.Errorf("не удалось сохранить часовой пояс пользователя: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"calendar/internal/models"
 )
@@ -11,8 +12,22 @@ type Storage interface {
 	CreateEvent(ctx context.Context, event *models.Event) (*models.Event, error)
 	GetEvent(ctx context.Context, eventID int) (*models.Event, error)
 	UpdateEvent(ctx context.Context, event *models.Event) error
-	DeleteEvent(ctx context.Context, eventID int) error
+	DeleteEvent(ctx context.Context, eventID, version int) error
 	EventsForDay(ctx context.Context, userID int, date string) ([]*models.Event, error)
 	EventsForWeek(ctx context.Context, userID int, date string) ([]*models.Event, error)
 	EventsForMonth(ctx context.Context, userID int, date string) ([]*models.Event, error)
+	EventsBetween(ctx context.Context, userID int, from, to time.Time) ([]*models.Event, error)
+	UpsertByUID(ctx context.Context, event *models.Event) (*models.Event, error)
+	EventsByUser(ctx context.Context, userID int) ([]*models.Event, error)
+	UserTimeZone(ctx context.Context, userID int) (string, error)
+	SetUserTimeZone(ctx context.Context, userID int, tz string) error
+	SaveOverride(ctx context.Context, event *models.Event) (*models.Event, error)
+	DeleteOccurrence(ctx context.Context, eventID int, recurrenceID time.Time) error
+	SplitSeries(ctx context.Context, master, following *models.Event, at time.Time) (*models.Event, error)
+	SetAttendeeStatus(ctx context.Context, eventID, userID int, status models.AttendeeStatus) error
+	ShareCalendar(ctx context.Context, share *models.CalendarShare) error
+	UnshareCalendar(ctx context.Context, ownerID, userID int) error
+	CalendarShares(ctx context.Context, ownerID int) ([]*models.CalendarShare, error)
+	CalendarPermission(ctx context.Context, ownerID, userID int) (models.Permission, error)
+	SearchEvents(ctx context.Context, search models.EventSearch) (*models.EventSearchResult, error)
 }
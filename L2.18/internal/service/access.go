@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"calendar/internal/auth"
+	"calendar/internal/models"
+)
+
+// currentUserHelper возвращает пользователя, от имени которого выполняется запрос.
+func currentUserHelper(ctx context.Context) (int, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return 0, ErrUnauthorized
+	}
+	return userID, nil
+}
+
+// checkUserHelper проверяет, что запрос выполняется от имени пользователя userID.
+func checkUserHelper(ctx context.Context, userID int) error {
+	current, err := currentUserHelper(ctx)
+	if err != nil {
+		return err
+	}
+	if current != userID {
+		return fmt.Errorf("%w: данные пользователя %d", ErrForbidden, userID)
+	}
+	return nil
+}
+
+// accessHelper проверяет, что текущий пользователь - владелец календаря ownerID
+// или ему открыт доступ к календарю с правами не ниже need.
+func (d *DefaultService) accessHelper(ctx context.Context, ownerID int, need models.Permission) error {
+	current, err := currentUserHelper(ctx)
+	if err != nil {
+		return err
+	}
+	if current == ownerID {
+		return nil
+	}
+	perm, err := d.repo.CalendarPermission(ctx, ownerID, current)
+	if err != nil {
+		return err
+	}
+	if !perm.Allows(need) {
+		return fmt.Errorf("%w: календарь пользователя %d", ErrForbidden, ownerID)
+	}
+	return nil
+}
+
+// editableEventHelper загружает событие и проверяет, что текущий пользователь может его изменять:
+// это его событие или у него есть права на запись в календарь владельца.
+func (d *DefaultService) editableEventHelper(ctx context.Context, eventID int) (*models.Event, error) {
+	if _, err := currentUserHelper(ctx); err != nil {
+		return nil, err
+	}
+	event, err := d.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.accessHelper(ctx, event.UserID, models.PermissionWrite); err != nil {
+		return nil, fmt.Errorf("%w: событие %d", ErrForbidden, eventID)
+	}
+	return event, nil
+}
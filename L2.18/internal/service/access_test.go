@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"calendar/internal/auth"
+	"calendar/internal/models"
+	"calendar/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteEvent_ForeignEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockStorage(ctrl)
+	svc, err := NewDefaultService(repo)
+	require.NoError(t, err)
+
+	repo.EXPECT().GetEvent(gomock.Any(), 7).Return(&models.Event{ID: 7, UserID: 2}, nil)
+	repo.EXPECT().CalendarPermission(gomock.Any(), 2, 1).Return(models.Permission(""), nil)
+
+	err = svc.DeleteEvent(auth.WithUser(context.Background(), 1), 7, 0)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestUpdateEvent_ChangeOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockStorage(ctrl)
+	svc, err := NewDefaultService(repo)
+	require.NoError(t, err)
+
+	repo.EXPECT().GetEvent(gomock.Any(), 7).Return(&models.Event{ID: 7, UserID: 1}, nil)
+
+	event := &models.Event{ID: 7, UserID: 2, Title: "Встреча", Start: time.Now().Add(time.Hour)}
+	err = svc.UpdateEvent(auth.WithUser(context.Background(), 1), event)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestEventsForDay_Access(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockStorage(ctrl)
+	svc, err := NewDefaultService(repo)
+	require.NoError(t, err)
+
+	_, err = svc.EventsForDay(context.Background(), 1, "2025-10-21")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	repo.EXPECT().CalendarPermission(gomock.Any(), 1, 2).Return(models.Permission(""), nil)
+	_, err = svc.EventsForDay(auth.WithUser(context.Background(), 2), 1, "2025-10-21")
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestSharedCalendar_ReadOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockStorage(ctrl)
+	svc, err := NewDefaultService(repo)
+	require.NoError(t, err)
+	ctx := auth.WithUser(context.Background(), 2)
+
+	repo.EXPECT().CalendarPermission(gomock.Any(), 1, 2).Return(models.PermissionRead, nil).Times(2)
+	repo.EXPECT().EventsForDay(gomock.Any(), 1, "2025-10-21").Return([]*models.Event{}, nil)
+	repo.EXPECT().GetEvent(gomock.Any(), 7).Return(&models.Event{ID: 7, UserID: 1}, nil)
+
+	_, err = svc.EventsForDay(ctx, 1, "2025-10-21")
+	assert.NoError(t, err, "с правами на чтение календарь виден")
+
+	err = svc.DeleteEvent(ctx, 7, 0)
+	assert.ErrorIs(t, err, ErrForbidden, "без прав на запись событие не удалить")
+}
+
+func TestRespondToEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockStorage(ctrl)
+	svc, err := NewDefaultService(repo)
+	require.NoError(t, err)
+
+	parentID := 5
+	occurrence := &models.Event{
+		ID:        9,
+		UserID:    1,
+		ParentID:  &parentID,
+		Attendees: []models.Attendee{{UserID: 2, Status: models.StatusNeedsAction}},
+	}
+	repo.EXPECT().GetEvent(gomock.Any(), 9).Return(occurrence, nil).Times(2)
+	// ответ на переопределённое вхождение сохраняется для всей серии
+	repo.EXPECT().SetAttendeeStatus(gomock.Any(), 5, 2, models.StatusAccepted).Return(nil)
+
+	err = svc.RespondToEvent(auth.WithUser(context.Background(), 2), 9, models.StatusAccepted)
+	assert.NoError(t, err)
+
+	err = svc.RespondToEvent(auth.WithUser(context.Background(), 3), 9, models.StatusAccepted)
+	assert.ErrorIs(t, err, ErrForbidden, "не приглашённый пользователь не может ответить")
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"calendar/internal/repository"
+)
+
+// Ошибки сервиса. Хендлеры различают их через errors.Is и выбирают код ответа,
+// текст ошибки показывается пользователю как есть.
+var (
+	// ErrUnauthorized в контексте запроса нет аутентифицированного пользователя
+	ErrUnauthorized = errors.New("пользователь не аутентифицирован")
+	// ErrForbidden пользователь пытается прочитать или изменить чужие данные
+	ErrForbidden = errors.New("доступ запрещён")
+	// ErrValidation некорректные входные данные
+	ErrValidation = errors.New("некорректные данные")
+	// ErrNotFound событие, приглашение или доступ не найдены
+	ErrNotFound = repository.ErrNotFound
+	// ErrConflict изменение противоречит уже сохранённым данным
+	ErrConflict = errors.New("конфликт с существующими данными")
+	// ErrPreconditionFailed событие изменилось с тех пор, как клиент его прочитал (If-Match)
+	ErrPreconditionFailed = repository.ErrVersionConflict
+)
+
+// validationError ошибка во входных данных, errors.Is(err, ErrValidation) для неё истинно
+type validationError struct {
+	msg string
+}
+
+func (e *validationError) Error() string {
+	return e.msg
+}
+
+func (e *validationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// invalidf создаёт ошибку валидации с сообщением без общего префикса.
+func invalidf(format string, args ...any) error {
+	return &validationError{msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"calendar/internal/models"
+)
+
+// maxFreeBusyRange ограничение на длину диапазона в запросе занятости
+const maxFreeBusyRange = 92 * 24 * time.Hour
+
+// FindConflicts возвращает события пользователя, пересекающиеся по времени с event.
+// События на весь день и события нулевой длительности время не занимают и конфликтов не дают.
+func (d *DefaultService) FindConflicts(ctx context.Context, event *models.Event) ([]*models.Event, error) {
+	if err := d.accessHelper(ctx, event.UserID, models.PermissionRead); err != nil {
+		return nil, err
+	}
+	userLoc, err := d.userLocationHelper(ctx, event.UserID)
+	if err != nil {
+		return nil, err
+	}
+	candidate := *event
+	if err := normalizeTimesHelper(&candidate, userLoc.String()); err != nil {
+		return nil, err
+	}
+	if !blocksTimeHelper(&candidate) {
+		return nil, nil
+	}
+
+	events, err := d.repo.EventsBetween(ctx, event.UserID, candidate.Start.In(userLoc), candidate.End.In(userLoc))
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []*models.Event
+	for _, ev := range events {
+		if ev.ID == event.ID && event.ID != 0 {
+			continue
+		}
+		if blocksTimeHelper(ev) && ev.Start.Before(candidate.End) && ev.End.After(candidate.Start) {
+			conflicts = append(conflicts, ev)
+		}
+	}
+	return conflicts, nil
+}
+
+// FreeBusy считает занятость пользователей userIDs в [from, to): объединённые интервалы занятости,
+// общие свободные промежутки не короче duration и до limit предлагаемых слотов длиной duration.
+// Занятость раскрывает только интервалы, без названий событий, поэтому доступна любому
+// аутентифицированному пользователю.
+func (d *DefaultService) FreeBusy(ctx context.Context, userIDs []int, from, to time.Time, duration time.Duration, limit int) (*models.FreeBusy, error) {
+	if _, err := currentUserHelper(ctx); err != nil {
+		return nil, err
+	}
+	if len(userIDs) == 0 {
+		return nil, invalidf("не указаны пользователи")
+	}
+	if !from.Before(to) {
+		return nil, invalidf("начало диапазона должно быть раньше конца")
+	}
+	if to.Sub(from) > maxFreeBusyRange {
+		return nil, invalidf("диапазон не должен превышать %d дней", int(maxFreeBusyRange.Hours()/24))
+	}
+	if duration <= 0 {
+		return nil, invalidf("длительность слота должна быть положительной")
+	}
+
+	var busy []models.Interval
+	for _, userID := range userIDs {
+		userLoc, err := d.userLocationHelper(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		events, err := d.repo.EventsBetween(ctx, userID, from.In(userLoc), to.In(userLoc))
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range events {
+			if !blocksTimeHelper(ev) {
+				continue
+			}
+			busy = append(busy, models.Interval{Start: maxTime(ev.Start, from), End: minTime(ev.End, to)})
+		}
+	}
+
+	busy = mergeIntervalsHelper(busy)
+	free := freeIntervalsHelper(busy, from, to, duration)
+	return &models.FreeBusy{
+		Busy:  busy,
+		Free:  free,
+		Slots: slotsHelper(free, duration, limit),
+	}, nil
+}
+
+// blocksTimeHelper проверяет, занимает ли событие время в расписании.
+func blocksTimeHelper(ev *models.Event) bool {
+	return !ev.AllDay && ev.End.After(ev.Start)
+}
+
+// mergeIntervalsHelper сортирует интервалы и объединяет пересекающиеся и смежные.
+func mergeIntervalsHelper(intervals []models.Interval) []models.Interval {
+	slices.SortFunc(intervals, func(a, b models.Interval) int { return a.Start.Compare(b.Start) })
+	merged := make([]models.Interval, 0, len(intervals))
+	for _, iv := range intervals {
+		if !iv.End.After(iv.Start) {
+			continue
+		}
+		if n := len(merged); n > 0 && !iv.Start.After(merged[n-1].End) {
+			merged[n-1].End = maxTime(merged[n-1].End, iv.End)
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	return merged
+}
+
+// freeIntervalsHelper возвращает промежутки [from, to) между отсортированными
+// непересекающимися интервалами busy, не короче minLen.
+func freeIntervalsHelper(busy []models.Interval, from, to time.Time, minLen time.Duration) []models.Interval {
+	free := []models.Interval{}
+	cursor := from
+	for _, iv := range append(busy, models.Interval{Start: to, End: to}) {
+		if iv.Start.Sub(cursor) >= minLen {
+			free = append(free, models.Interval{Start: cursor, End: iv.Start})
+		}
+		cursor = maxTime(cursor, iv.End)
+	}
+	return free
+}
+
+// slotsHelper нарезает свободные промежутки на слоты длиной duration, не больше limit штук.
+func slotsHelper(free []models.Interval, duration time.Duration, limit int) []models.Interval {
+	slots := []models.Interval{}
+	for _, iv := range free {
+		for start := iv.Start; !start.Add(duration).After(iv.End); start = start.Add(duration) {
+			if len(slots) >= limit {
+				return slots
+			}
+			slots = append(slots, models.Interval{Start: start, End: start.Add(duration)})
+		}
+	}
+	return slots
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"calendar/internal/models"
+)
+
+// maxListRange ограничение на диапазон, который можно листать одним курсором
+const maxListRange = 366 * 24 * time.Hour
+
+// MaxPageSize наибольший размер страницы списка событий
+const MaxPageSize = 500
+
+// GetEvent возвращает событие, если текущий пользователь может читать календарь его владельца
+// или приглашён на него.
+func (d *DefaultService) GetEvent(ctx context.Context, eventID int) (*models.Event, error) {
+	current, err := currentUserHelper(ctx)
+	if err != nil {
+		return nil, err
+	}
+	event, err := d.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range event.Attendees {
+		if a.UserID == current {
+			return event, nil
+		}
+	}
+	if err := d.accessHelper(ctx, event.UserID, models.PermissionRead); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// ListEvents возвращает страницу событий пользователя в [from, to) с развёрнутыми сериями,
+// начиная с позиции после cursor. Курсор задаёт позицию, а не смещение, поэтому страницы
+// не сдвигаются, если между запросами добавили или удалили события.
+func (d *DefaultService) ListEvents(ctx context.Context, userID int, from, to time.Time, cursor string, limit int) (*models.EventPage, error) {
+	if err := d.accessHelper(ctx, userID, models.PermissionRead); err != nil {
+		return nil, err
+	}
+	if !from.Before(to) {
+		return nil, invalidf("начало диапазона должно быть раньше конца")
+	}
+	if to.Sub(from) > maxListRange {
+		return nil, invalidf("диапазон не должен превышать %d дней", int(maxListRange.Hours()/24))
+	}
+	if limit <= 0 || limit > MaxPageSize {
+		return nil, invalidf("размер страницы должен быть от 1 до %d", MaxPageSize)
+	}
+	var after *models.EventCursor
+	if cursor != "" {
+		c, err := models.DecodeEventCursor(cursor)
+		if err != nil {
+			return nil, invalidf("%v", err)
+		}
+		after = &c
+	}
+
+	events, err := d.repo.EventsBetween(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(events, func(a, b *models.Event) int {
+		return models.CursorOf(a).Compare(models.CursorOf(b))
+	})
+
+	page := &models.EventPage{Events: []*models.Event{}}
+	for _, ev := range events {
+		if after != nil && models.CursorOf(ev).Compare(*after) <= 0 {
+			continue
+		}
+		if len(page.Events) == limit {
+			page.NextCursor = models.CursorOf(page.Events[limit-1]).Encode()
+			break
+		}
+		page.Events = append(page.Events, ev)
+	}
+	return page, nil
+}
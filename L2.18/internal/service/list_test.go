@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"calendar/internal/auth"
+	"calendar/internal/models"
+	"calendar/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListEvents_Cursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockStorage(ctrl)
+	svc, err := NewDefaultService(repo)
+	require.NoError(t, err)
+	ctx := auth.WithUser(context.Background(), 1)
+
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	at := func(day int) time.Time { return from.AddDate(0, 0, day) }
+	day3 := models.DateOf(at(3))
+	events := func() []*models.Event {
+		// серия 5 развёрнута во вхождения, у них одинаковый ID
+		return []*models.Event{
+			{ID: 5, Start: at(3), RecurrenceID: &day3},
+			{ID: 2, Start: at(1)},
+			{ID: 9, Start: at(3)},
+			{ID: 4, Start: at(1)},
+		}
+	}
+	repo.EXPECT().EventsBetween(gomock.Any(), 1, from, to).DoAndReturn(
+		func(context.Context, int, time.Time, time.Time) ([]*models.Event, error) { return events(), nil },
+	).Times(2)
+
+	page, err := svc.ListEvents(ctx, 1, from, to, "", 3)
+	require.NoError(t, err)
+	require.Len(t, page.Events, 3)
+	assert.Equal(t, []int{2, 4, 5}, []int{page.Events[0].ID, page.Events[1].ID, page.Events[2].ID})
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = svc.ListEvents(ctx, 1, from, to, page.NextCursor, 3)
+	require.NoError(t, err)
+	require.Len(t, page.Events, 1)
+	assert.Equal(t, 9, page.Events[0].ID)
+	assert.Empty(t, page.NextCursor, "на последней странице курсора нет")
+}
+
+func TestListEvents_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockStorage(ctrl)
+	svc, err := NewDefaultService(repo)
+	require.NoError(t, err)
+	ctx := auth.WithUser(context.Background(), 1)
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err = svc.ListEvents(ctx, 1, from, from.AddDate(2, 0, 0), "", 10)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = svc.ListEvents(ctx, 1, from, from.AddDate(0, 1, 0), "", MaxPageSize+1)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = svc.ListEvents(ctx, 1, from, from.AddDate(0, 1, 0), "не курсор", 10)
+	assert.ErrorIs(t, err, ErrValidation)
+}
@@ -8,6 +8,7 @@ import (
 	models "calendar/internal/models"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +36,21 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// CalendarShares mocks base method.
+func (m *MockService) CalendarShares(ctx context.Context, ownerID int) ([]*models.CalendarShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalendarShares", ctx, ownerID)
+	ret0, _ := ret[0].([]*models.CalendarShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalendarShares indicates an expected call of CalendarShares.
+func (mr *MockServiceMockRecorder) CalendarShares(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalendarShares", reflect.TypeOf((*MockService)(nil).CalendarShares), ctx, ownerID)
+}
+
 // CreateEvent mocks base method.
 func (m *MockService) CreateEvent(ctx context.Context, event *models.Event) (*models.Event, error) {
 	m.ctrl.T.Helper()
@@ -51,17 +67,31 @@ func (mr *MockServiceMockRecorder) CreateEvent(ctx, event interface{}) *gomock.C
 }
 
 // DeleteEvent mocks base method.
-func (m *MockService) DeleteEvent(ctx context.Context, eventID int) error {
+func (m *MockService) DeleteEvent(ctx context.Context, eventID, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEvent", ctx, eventID)
+	ret := m.ctrl.Call(m, "DeleteEvent", ctx, eventID, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteEvent indicates an expected call of DeleteEvent.
-func (mr *MockServiceMockRecorder) DeleteEvent(ctx, eventID interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) DeleteEvent(ctx, eventID, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockService)(nil).DeleteEvent), ctx, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockService)(nil).DeleteEvent), ctx, eventID, version)
+}
+
+// DeleteOccurrences mocks base method.
+func (m *MockService) DeleteOccurrences(ctx context.Context, eventID int, recurrenceID time.Time, scope models.Scope) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOccurrences", ctx, eventID, recurrenceID, scope)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOccurrences indicates an expected call of DeleteOccurrences.
+func (mr *MockServiceMockRecorder) DeleteOccurrences(ctx, eventID, recurrenceID, scope interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOccurrences", reflect.TypeOf((*MockService)(nil).DeleteOccurrences), ctx, eventID, recurrenceID, scope)
 }
 
 // EventsForDay mocks base method.
@@ -109,6 +139,167 @@ func (mr *MockServiceMockRecorder) EventsForWeek(ctx, userID, dateStr interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsForWeek", reflect.TypeOf((*MockService)(nil).EventsForWeek), ctx, userID, dateStr)
 }
 
+// ExportEvents mocks base method.
+func (m *MockService) ExportEvents(ctx context.Context, userID int) ([]*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportEvents", ctx, userID)
+	ret0, _ := ret[0].([]*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportEvents indicates an expected call of ExportEvents.
+func (mr *MockServiceMockRecorder) ExportEvents(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportEvents", reflect.TypeOf((*MockService)(nil).ExportEvents), ctx, userID)
+}
+
+// FindConflicts mocks base method.
+func (m *MockService) FindConflicts(ctx context.Context, event *models.Event) ([]*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindConflicts", ctx, event)
+	ret0, _ := ret[0].([]*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindConflicts indicates an expected call of FindConflicts.
+func (mr *MockServiceMockRecorder) FindConflicts(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindConflicts", reflect.TypeOf((*MockService)(nil).FindConflicts), ctx, event)
+}
+
+// FreeBusy mocks base method.
+func (m *MockService) FreeBusy(ctx context.Context, userIDs []int, from, to time.Time, duration time.Duration, limit int) (*models.FreeBusy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreeBusy", ctx, userIDs, from, to, duration, limit)
+	ret0, _ := ret[0].(*models.FreeBusy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreeBusy indicates an expected call of FreeBusy.
+func (mr *MockServiceMockRecorder) FreeBusy(ctx, userIDs, from, to, duration, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreeBusy", reflect.TypeOf((*MockService)(nil).FreeBusy), ctx, userIDs, from, to, duration, limit)
+}
+
+// GetEvent mocks base method.
+func (m *MockService) GetEvent(ctx context.Context, eventID int) (*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvent", ctx, eventID)
+	ret0, _ := ret[0].(*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvent indicates an expected call of GetEvent.
+func (mr *MockServiceMockRecorder) GetEvent(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockService)(nil).GetEvent), ctx, eventID)
+}
+
+// ImportEvents mocks base method.
+func (m *MockService) ImportEvents(ctx context.Context, userID int, events []*models.Event) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportEvents", ctx, userID, events)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportEvents indicates an expected call of ImportEvents.
+func (mr *MockServiceMockRecorder) ImportEvents(ctx, userID, events interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportEvents", reflect.TypeOf((*MockService)(nil).ImportEvents), ctx, userID, events)
+}
+
+// ListEvents mocks base method.
+func (m *MockService) ListEvents(ctx context.Context, userID int, from, to time.Time, cursor string, limit int) (*models.EventPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx, userID, from, to, cursor, limit)
+	ret0, _ := ret[0].(*models.EventPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockServiceMockRecorder) ListEvents(ctx, userID, from, to, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockService)(nil).ListEvents), ctx, userID, from, to, cursor, limit)
+}
+
+// RespondToEvent mocks base method.
+func (m *MockService) RespondToEvent(ctx context.Context, eventID int, status models.AttendeeStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondToEvent", ctx, eventID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RespondToEvent indicates an expected call of RespondToEvent.
+func (mr *MockServiceMockRecorder) RespondToEvent(ctx, eventID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToEvent", reflect.TypeOf((*MockService)(nil).RespondToEvent), ctx, eventID, status)
+}
+
+// SearchEvents mocks base method.
+func (m *MockService) SearchEvents(ctx context.Context, search models.EventSearch) (*models.EventSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchEvents", ctx, search)
+	ret0, _ := ret[0].(*models.EventSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchEvents indicates an expected call of SearchEvents.
+func (mr *MockServiceMockRecorder) SearchEvents(ctx, search interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEvents", reflect.TypeOf((*MockService)(nil).SearchEvents), ctx, search)
+}
+
+// SetUserTimeZone mocks base method.
+func (m *MockService) SetUserTimeZone(ctx context.Context, userID int, tz string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserTimeZone", ctx, userID, tz)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserTimeZone indicates an expected call of SetUserTimeZone.
+func (mr *MockServiceMockRecorder) SetUserTimeZone(ctx, userID, tz interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserTimeZone", reflect.TypeOf((*MockService)(nil).SetUserTimeZone), ctx, userID, tz)
+}
+
+// ShareCalendar mocks base method.
+func (m *MockService) ShareCalendar(ctx context.Context, share *models.CalendarShare) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareCalendar", ctx, share)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ShareCalendar indicates an expected call of ShareCalendar.
+func (mr *MockServiceMockRecorder) ShareCalendar(ctx, share interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareCalendar", reflect.TypeOf((*MockService)(nil).ShareCalendar), ctx, share)
+}
+
+// UnshareCalendar mocks base method.
+func (m *MockService) UnshareCalendar(ctx context.Context, ownerID, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnshareCalendar", ctx, ownerID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnshareCalendar indicates an expected call of UnshareCalendar.
+func (mr *MockServiceMockRecorder) UnshareCalendar(ctx, ownerID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnshareCalendar", reflect.TypeOf((*MockService)(nil).UnshareCalendar), ctx, ownerID, userID)
+}
+
 // UpdateEvent mocks base method.
 func (m *MockService) UpdateEvent(ctx context.Context, event *models.Event) error {
 	m.ctrl.T.Helper()
@@ -122,3 +313,17 @@ func (mr *MockServiceMockRecorder) UpdateEvent(ctx, event interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockService)(nil).UpdateEvent), ctx, event)
 }
+
+// UpdateOccurrences mocks base method.
+func (m *MockService) UpdateOccurrences(ctx context.Context, event *models.Event, recurrenceID time.Time, scope models.Scope) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOccurrences", ctx, event, recurrenceID, scope)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOccurrences indicates an expected call of UpdateOccurrences.
+func (mr *MockServiceMockRecorder) UpdateOccurrences(ctx, event, recurrenceID, scope interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOccurrences", reflect.TypeOf((*MockService)(nil).UpdateOccurrences), ctx, event, recurrenceID, scope)
+}
@@ -0,0 +1,345 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/storage.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "calendar/internal/models"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStorage is a mock of Storage interface.
+type MockStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageMockRecorder
+}
+
+// MockStorageMockRecorder is the mock recorder for MockStorage.
+type MockStorageMockRecorder struct {
+	mock *MockStorage
+}
+
+// NewMockStorage creates a new mock instance.
+func NewMockStorage(ctrl *gomock.Controller) *MockStorage {
+	mock := &MockStorage{ctrl: ctrl}
+	mock.recorder = &MockStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
+	return m.recorder
+}
+
+// CalendarPermission mocks base method.
+func (m *MockStorage) CalendarPermission(ctx context.Context, ownerID, userID int) (models.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalendarPermission", ctx, ownerID, userID)
+	ret0, _ := ret[0].(models.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalendarPermission indicates an expected call of CalendarPermission.
+func (mr *MockStorageMockRecorder) CalendarPermission(ctx, ownerID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalendarPermission", reflect.TypeOf((*MockStorage)(nil).CalendarPermission), ctx, ownerID, userID)
+}
+
+// CalendarShares mocks base method.
+func (m *MockStorage) CalendarShares(ctx context.Context, ownerID int) ([]*models.CalendarShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalendarShares", ctx, ownerID)
+	ret0, _ := ret[0].([]*models.CalendarShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalendarShares indicates an expected call of CalendarShares.
+func (mr *MockStorageMockRecorder) CalendarShares(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalendarShares", reflect.TypeOf((*MockStorage)(nil).CalendarShares), ctx, ownerID)
+}
+
+// CreateEvent mocks base method.
+func (m *MockStorage) CreateEvent(ctx context.Context, event *models.Event) (*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEvent", ctx, event)
+	ret0, _ := ret[0].(*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEvent indicates an expected call of CreateEvent.
+func (mr *MockStorageMockRecorder) CreateEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockStorage)(nil).CreateEvent), ctx, event)
+}
+
+// DeleteEvent mocks base method.
+func (m *MockStorage) DeleteEvent(ctx context.Context, eventID, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEvent", ctx, eventID, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEvent indicates an expected call of DeleteEvent.
+func (mr *MockStorageMockRecorder) DeleteEvent(ctx, eventID, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockStorage)(nil).DeleteEvent), ctx, eventID, version)
+}
+
+// DeleteOccurrence mocks base method.
+func (m *MockStorage) DeleteOccurrence(ctx context.Context, eventID int, recurrenceID time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOccurrence", ctx, eventID, recurrenceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOccurrence indicates an expected call of DeleteOccurrence.
+func (mr *MockStorageMockRecorder) DeleteOccurrence(ctx, eventID, recurrenceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOccurrence", reflect.TypeOf((*MockStorage)(nil).DeleteOccurrence), ctx, eventID, recurrenceID)
+}
+
+// EventsBetween mocks base method.
+func (m *MockStorage) EventsBetween(ctx context.Context, userID int, from, to time.Time) ([]*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventsBetween", ctx, userID, from, to)
+	ret0, _ := ret[0].([]*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EventsBetween indicates an expected call of EventsBetween.
+func (mr *MockStorageMockRecorder) EventsBetween(ctx, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsBetween", reflect.TypeOf((*MockStorage)(nil).EventsBetween), ctx, userID, from, to)
+}
+
+// EventsByUser mocks base method.
+func (m *MockStorage) EventsByUser(ctx context.Context, userID int) ([]*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventsByUser", ctx, userID)
+	ret0, _ := ret[0].([]*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EventsByUser indicates an expected call of EventsByUser.
+func (mr *MockStorageMockRecorder) EventsByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsByUser", reflect.TypeOf((*MockStorage)(nil).EventsByUser), ctx, userID)
+}
+
+// EventsForDay mocks base method.
+func (m *MockStorage) EventsForDay(ctx context.Context, userID int, date string) ([]*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventsForDay", ctx, userID, date)
+	ret0, _ := ret[0].([]*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EventsForDay indicates an expected call of EventsForDay.
+func (mr *MockStorageMockRecorder) EventsForDay(ctx, userID, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsForDay", reflect.TypeOf((*MockStorage)(nil).EventsForDay), ctx, userID, date)
+}
+
+// EventsForMonth mocks base method.
+func (m *MockStorage) EventsForMonth(ctx context.Context, userID int, date string) ([]*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventsForMonth", ctx, userID, date)
+	ret0, _ := ret[0].([]*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EventsForMonth indicates an expected call of EventsForMonth.
+func (mr *MockStorageMockRecorder) EventsForMonth(ctx, userID, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsForMonth", reflect.TypeOf((*MockStorage)(nil).EventsForMonth), ctx, userID, date)
+}
+
+// EventsForWeek mocks base method.
+func (m *MockStorage) EventsForWeek(ctx context.Context, userID int, date string) ([]*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventsForWeek", ctx, userID, date)
+	ret0, _ := ret[0].([]*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EventsForWeek indicates an expected call of EventsForWeek.
+func (mr *MockStorageMockRecorder) EventsForWeek(ctx, userID, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsForWeek", reflect.TypeOf((*MockStorage)(nil).EventsForWeek), ctx, userID, date)
+}
+
+// GetEvent mocks base method.
+func (m *MockStorage) GetEvent(ctx context.Context, eventID int) (*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvent", ctx, eventID)
+	ret0, _ := ret[0].(*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvent indicates an expected call of GetEvent.
+func (mr *MockStorageMockRecorder) GetEvent(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockStorage)(nil).GetEvent), ctx, eventID)
+}
+
+// SaveOverride mocks base method.
+func (m *MockStorage) SaveOverride(ctx context.Context, event *models.Event) (*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOverride", ctx, event)
+	ret0, _ := ret[0].(*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveOverride indicates an expected call of SaveOverride.
+func (mr *MockStorageMockRecorder) SaveOverride(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOverride", reflect.TypeOf((*MockStorage)(nil).SaveOverride), ctx, event)
+}
+
+// SearchEvents mocks base method.
+func (m *MockStorage) SearchEvents(ctx context.Context, search models.EventSearch) (*models.EventSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchEvents", ctx, search)
+	ret0, _ := ret[0].(*models.EventSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchEvents indicates an expected call of SearchEvents.
+func (mr *MockStorageMockRecorder) SearchEvents(ctx, search interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEvents", reflect.TypeOf((*MockStorage)(nil).SearchEvents), ctx, search)
+}
+
+// SetAttendeeStatus mocks base method.
+func (m *MockStorage) SetAttendeeStatus(ctx context.Context, eventID, userID int, status models.AttendeeStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAttendeeStatus", ctx, eventID, userID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAttendeeStatus indicates an expected call of SetAttendeeStatus.
+func (mr *MockStorageMockRecorder) SetAttendeeStatus(ctx, eventID, userID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAttendeeStatus", reflect.TypeOf((*MockStorage)(nil).SetAttendeeStatus), ctx, eventID, userID, status)
+}
+
+// SetUserTimeZone mocks base method.
+func (m *MockStorage) SetUserTimeZone(ctx context.Context, userID int, tz string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserTimeZone", ctx, userID, tz)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserTimeZone indicates an expected call of SetUserTimeZone.
+func (mr *MockStorageMockRecorder) SetUserTimeZone(ctx, userID, tz interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserTimeZone", reflect.TypeOf((*MockStorage)(nil).SetUserTimeZone), ctx, userID, tz)
+}
+
+// ShareCalendar mocks base method.
+func (m *MockStorage) ShareCalendar(ctx context.Context, share *models.CalendarShare) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareCalendar", ctx, share)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ShareCalendar indicates an expected call of ShareCalendar.
+func (mr *MockStorageMockRecorder) ShareCalendar(ctx, share interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareCalendar", reflect.TypeOf((*MockStorage)(nil).ShareCalendar), ctx, share)
+}
+
+// SplitSeries mocks base method.
+func (m *MockStorage) SplitSeries(ctx context.Context, master, following *models.Event, at time.Time) (*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitSeries", ctx, master, following, at)
+	ret0, _ := ret[0].(*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitSeries indicates an expected call of SplitSeries.
+func (mr *MockStorageMockRecorder) SplitSeries(ctx, master, following, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitSeries", reflect.TypeOf((*MockStorage)(nil).SplitSeries), ctx, master, following, at)
+}
+
+// UnshareCalendar mocks base method.
+func (m *MockStorage) UnshareCalendar(ctx context.Context, ownerID, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnshareCalendar", ctx, ownerID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnshareCalendar indicates an expected call of UnshareCalendar.
+func (mr *MockStorageMockRecorder) UnshareCalendar(ctx, ownerID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnshareCalendar", reflect.TypeOf((*MockStorage)(nil).UnshareCalendar), ctx, ownerID, userID)
+}
+
+// UpdateEvent mocks base method.
+func (m *MockStorage) UpdateEvent(ctx context.Context, event *models.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEvent indicates an expected call of UpdateEvent.
+func (mr *MockStorageMockRecorder) UpdateEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockStorage)(nil).UpdateEvent), ctx, event)
+}
+
+// UpsertByUID mocks base method.
+func (m *MockStorage) UpsertByUID(ctx context.Context, event *models.Event) (*models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertByUID", ctx, event)
+	ret0, _ := ret[0].(*models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertByUID indicates an expected call of UpsertByUID.
+func (mr *MockStorageMockRecorder) UpsertByUID(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertByUID", reflect.TypeOf((*MockStorage)(nil).UpsertByUID), ctx, event)
+}
+
+// UserTimeZone mocks base method.
+func (m *MockStorage) UserTimeZone(ctx context.Context, userID int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserTimeZone", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserTimeZone indicates an expected call of UserTimeZone.
+func (mr *MockStorageMockRecorder) UserTimeZone(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserTimeZone", reflect.TypeOf((*MockStorage)(nil).UserTimeZone), ctx, userID)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"calendar/internal/models"
+)
+
+// ограничения на поисковый запрос и метки событий
+const (
+	maxSearchQuery = 256
+	maxTags        = 32
+	maxTagLength   = 64
+)
+
+// SearchEvents ищет события пользователя по тексту названия и описания, меткам и времени.
+// Без явной сортировки результаты с запросом упорядочены по релевантности, без запроса -
+// от поздних к ранним, чтобы сначала шли недавние встречи.
+func (d *DefaultService) SearchEvents(ctx context.Context, search models.EventSearch) (*models.EventSearchResult, error) {
+	if err := d.accessHelper(ctx, search.UserID, models.PermissionRead); err != nil {
+		return nil, err
+	}
+	search.Query = strings.TrimSpace(search.Query)
+	if utf8.RuneCountInString(search.Query) > maxSearchQuery {
+		return nil, invalidf("поисковый запрос не должен быть длиннее %d символов", maxSearchQuery)
+	}
+	if !search.From.IsZero() && !search.To.IsZero() && !search.From.Before(search.To) {
+		return nil, invalidf("начало диапазона должно быть раньше конца")
+	}
+	tags, err := normalizeTagsHelper(search.Tags)
+	if err != nil {
+		return nil, err
+	}
+	search.Tags = tags
+
+	switch search.Sort {
+	case "":
+		search.Sort = models.SortStartDesc
+		if search.Query != "" {
+			search.Sort = models.SortRelevance
+		}
+	case models.SortRelevance, models.SortStart, models.SortStartDesc:
+	default:
+		return nil, invalidf("сортировка должна быть relevance, start или -start")
+	}
+	if search.Limit <= 0 || search.Limit > MaxPageSize {
+		return nil, invalidf("размер страницы должен быть от 1 до %d", MaxPageSize)
+	}
+	if search.Offset < 0 {
+		return nil, invalidf("смещение не может быть отрицательным")
+	}
+	return d.repo.SearchEvents(ctx, search)
+}
+
+// normalizeTagsHelper приводит метки к нижнему регистру, убирает пробелы по краям, пустые
+// метки и повторы. nil остаётся nil, чтобы при изменении события можно было не трогать метки.
+func normalizeTagsHelper(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, invalidf("метка %q длиннее %d символов", tag, maxTagLength)
+		}
+		if strings.Contains(tag, ",") {
+			return nil, invalidf("метка %q не должна содержать запятую", tag)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTags {
+		return nil, invalidf("у события может быть не больше %d меток", maxTags)
+	}
+	return normalized, nil
+}
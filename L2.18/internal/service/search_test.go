@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"calendar/internal/auth"
+	"calendar/internal/models"
+	"calendar/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchEvents_Defaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockStorage(ctrl)
+	svc, err := NewDefaultService(repo)
+	require.NoError(t, err)
+	ctx := auth.WithUser(context.Background(), 1)
+
+	repo.EXPECT().
+		SearchEvents(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, s models.EventSearch) (*models.EventSearchResult, error) {
+			assert.Equal(t, "ретро", s.Query)
+			assert.Equal(t, models.SortRelevance, s.Sort, "с запросом сортируем по релевантности")
+			assert.Equal(t, []string{"работа", "команда"}, s.Tags)
+			return &models.EventSearchResult{}, nil
+		})
+	repo.EXPECT().
+		SearchEvents(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, s models.EventSearch) (*models.EventSearchResult, error) {
+			assert.Equal(t, models.SortStartDesc, s.Sort, "без запроса сначала недавние события")
+			return &models.EventSearchResult{}, nil
+		})
+
+	_, err = svc.SearchEvents(ctx, models.EventSearch{UserID: 1, Query: "  ретро ", Tags: []string{"Работа", " команда", "работа", ""}, Limit: 10})
+	require.NoError(t, err)
+	_, err = svc.SearchEvents(ctx, models.EventSearch{UserID: 1, Limit: 10})
+	require.NoError(t, err)
+}
+
+func TestSearchEvents_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockStorage(ctrl)
+	svc, err := NewDefaultService(repo)
+	require.NoError(t, err)
+	ctx := auth.WithUser(context.Background(), 1)
+	now := time.Now()
+
+	cases := map[string]models.EventSearch{
+		"длинный запрос":          {Query: strings.Repeat("я", maxSearchQuery+1), Limit: 10},
+		"пустой диапазон":         {From: now, To: now, Limit: 10},
+		"неизвестная сортировка":  {Sort: "title", Limit: 10},
+		"нулевой размер страницы": {},
+		"отрицательное смещение":  {Limit: 10, Offset: -1},
+		"длинная метка":           {Tags: []string{strings.Repeat("x", maxTagLength+1)}, Limit: 10},
+	}
+	for name, search := range cases {
+		search.UserID = 1
+		_, err := svc.SearchEvents(ctx, search)
+		assert.ErrorIs(t, err, ErrValidation, name)
+	}
+}
+
+func TestSearchEvents_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockStorage(ctrl)
+	svc, err := NewDefaultService(repo)
+	require.NoError(t, err)
+
+	repo.EXPECT().CalendarPermission(gomock.Any(), 1, 2).Return(models.Permission(""), nil)
+
+	_, err = svc.SearchEvents(auth.WithUser(context.Background(), 2), models.EventSearch{UserID: 1, Limit: 10})
+	assert.ErrorIs(t, err, ErrForbidden, "искать можно только в доступных календарях")
+}
@@ -3,20 +3,36 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"calendar/internal/models"
+	"calendar/internal/recurrence"
 	"calendar/internal/repository"
 )
 
 // Service - методы для сервиса
 type Service interface {
 	CreateEvent(ctx context.Context, event *models.Event) (*models.Event, error)
+	GetEvent(ctx context.Context, eventID int) (*models.Event, error)
+	ListEvents(ctx context.Context, userID int, from, to time.Time, cursor string, limit int) (*models.EventPage, error)
 	UpdateEvent(ctx context.Context, event *models.Event) error
-	DeleteEvent(ctx context.Context, eventID int) error
+	DeleteEvent(ctx context.Context, eventID, version int) error
+	ExportEvents(ctx context.Context, userID int) ([]*models.Event, error)
+	ImportEvents(ctx context.Context, userID int, events []*models.Event) (int, error)
+	UpdateOccurrences(ctx context.Context, event *models.Event, recurrenceID time.Time, scope models.Scope) error
+	DeleteOccurrences(ctx context.Context, eventID int, recurrenceID time.Time, scope models.Scope) error
 	EventsForDay(ctx context.Context, userID int, dateStr string) ([]*models.Event, error)
 	EventsForWeek(ctx context.Context, userID int, dateStr string) ([]*models.Event, error)
 	EventsForMonth(ctx context.Context, userID int, dateStr string) ([]*models.Event, error)
+	SetUserTimeZone(ctx context.Context, userID int, tz string) error
+	FindConflicts(ctx context.Context, event *models.Event) ([]*models.Event, error)
+	FreeBusy(ctx context.Context, userIDs []int, from, to time.Time, duration time.Duration, limit int) (*models.FreeBusy, error)
+	RespondToEvent(ctx context.Context, eventID int, status models.AttendeeStatus) error
+	ShareCalendar(ctx context.Context, share *models.CalendarShare) error
+	UnshareCalendar(ctx context.Context, ownerID, userID int) error
+	CalendarShares(ctx context.Context, ownerID int) ([]*models.CalendarShare, error)
+	SearchEvents(ctx context.Context, search models.EventSearch) (*models.EventSearchResult, error)
 }
 
 // DefaultService стандартная реализация сервиса
@@ -35,45 +51,428 @@ func NewDefaultService(repo repository.Storage) (*DefaultService, error) {
 }
 
 // CreateEvent создаёт новое событие и проверяет, что дата не из прошлого.
+// Без явного часового пояса событие создаётся в поясе пользователя.
 func (d *DefaultService) CreateEvent(ctx context.Context, event *models.Event) (*models.Event, error) {
-	if isPastDateHelper(event.Date) {
-		return nil, fmt.Errorf("дата события не может быть в прошлом")
+	if err := d.accessHelper(ctx, event.UserID, models.PermissionWrite); err != nil {
+		return nil, err
+	}
+	userLoc, err := d.userLocationHelper(ctx, event.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := normalizeTimesHelper(event, userLoc.String()); err != nil {
+		return nil, err
+	}
+	if isPastDateHelper(event, userLoc, time.Now()) {
+		return nil, invalidf("дата события не может быть в прошлом")
+	}
+	if event.ParentID != nil || event.RecurrenceID != nil {
+		return nil, invalidf("parent_id и recurrence_id задаются только при изменении вхождения серии")
+	}
+	if err := normalizeRRuleHelper(event); err != nil {
+		return nil, err
+	}
+	event.Reminders = normalizeRemindersHelper(event.Reminders)
+	if event.Tags, err = normalizeTagsHelper(event.Tags); err != nil {
+		return nil, err
+	}
+	if err := normalizeAttendeesHelper(event, nil); err != nil {
+		return nil, err
 	}
 	return d.repo.CreateEvent(ctx, event)
 }
 
-// UpdateEvent обновляет существующее событие после проверки, что оно существует.
+// UpdateEvent обновляет существующее событие после проверки, что оно существует
+// и текущий пользователь может его изменять. Для серии изменение применяется ко всем вхождениям.
 func (d *DefaultService) UpdateEvent(ctx context.Context, event *models.Event) error {
-	tempEvent, err := d.repo.GetEvent(ctx, event.ID)
-	if tempEvent == nil || err != nil {
-		return fmt.Errorf("изменяемое событие не найдено или произошла ошибка при поиске")
+	tempEvent, err := d.editableEventHelper(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+	if event.UserID != tempEvent.UserID {
+		return fmt.Errorf("%w: событие нельзя передать другому пользователю", ErrForbidden)
+	}
+	if err := normalizeRRuleHelper(event); err != nil {
+		return err
+	}
+	if err := normalizeTimesHelper(event, tempEvent.TimeZone); err != nil {
+		return err
+	}
+	// исключения и связь с серией меняются только через операции над вхождениями
+	if event.ExDates == nil {
+		event.ExDates = tempEvent.ExDates
 	}
+	// без поля reminders напоминания не меняются, пустой список их отключает
+	if event.Reminders == nil {
+		event.Reminders = tempEvent.Reminders
+	}
+	event.Reminders = normalizeRemindersHelper(event.Reminders)
+	// без поля tags метки не меняются
+	if event.Tags == nil {
+		event.Tags = tempEvent.Tags
+	}
+	if event.Tags, err = normalizeTagsHelper(event.Tags); err != nil {
+		return err
+	}
+	// без поля attendees список участников не меняется
+	if err := normalizeAttendeesHelper(event, tempEvent.Attendees); err != nil {
+		return err
+	}
+	event.ParentID = tempEvent.ParentID
+	event.RecurrenceID = tempEvent.RecurrenceID
 	return d.repo.UpdateEvent(ctx, event)
 }
 
-// DeleteEvent удаляет событие по ID.
-func (d *DefaultService) DeleteEvent(ctx context.Context, eventID int) error {
-	return d.repo.DeleteEvent(ctx, eventID)
+// ExportEvents возвращает все события пользователя без разворачивания серий.
+func (d *DefaultService) ExportEvents(ctx context.Context, userID int) ([]*models.Event, error) {
+	if err := d.accessHelper(ctx, userID, models.PermissionRead); err != nil {
+		return nil, err
+	}
+	return d.repo.EventsByUser(ctx, userID)
+}
+
+// ImportEvents сохраняет события из внешнего календаря. События сопоставляются по UID,
+// поэтому повторный импорт обновляет уже загруженные, а не создаёт дубли.
+// Переопределения вхождений привязываются к серии с тем же UID из этого же импорта.
+func (d *DefaultService) ImportEvents(ctx context.Context, userID int, events []*models.Event) (int, error) {
+	if err := d.accessHelper(ctx, userID, models.PermissionWrite); err != nil {
+		return 0, err
+	}
+	userLoc, err := d.userLocationHelper(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	for _, ev := range events {
+		if err := normalizeTimesHelper(ev, userLoc.String()); err != nil {
+			return 0, fmt.Errorf("событие %s: %w", ev.UID, err)
+		}
+		if ev.Tags, err = normalizeTagsHelper(ev.Tags); err != nil {
+			return 0, fmt.Errorf("событие %s: %w", ev.UID, err)
+		}
+	}
+
+	series := make(map[string]int)
+	imported := 0
+	for _, ev := range events {
+		if ev.RecurrenceID != nil {
+			continue
+		}
+		ev.UserID = userID
+		ev.ParentID = nil
+		if err := normalizeRRuleHelper(ev); err != nil {
+			return imported, fmt.Errorf("событие %s: %w", ev.UID, err)
+		}
+		saved, err := d.repo.UpsertByUID(ctx, ev)
+		if err != nil {
+			return imported, err
+		}
+		series[ev.UID] = saved.ID
+		imported++
+	}
+
+	for _, ev := range events {
+		if ev.RecurrenceID == nil {
+			continue
+		}
+		parentID, ok := series[ev.UID]
+		if !ok {
+			return imported, invalidf("для вхождения %s события %s не найдена серия", ev.RecurrenceID.Format(recurrence.DateLayout), ev.UID)
+		}
+		ev.UserID = userID
+		ev.ParentID = &parentID
+		ev.RRule = ""
+		if _, err := d.repo.SaveOverride(ctx, ev); err != nil {
+			return imported, err
+		}
+		imported++
+	}
+	return imported, nil
+}
+
+// UpdateOccurrences изменяет вхождение серии event.ID с исходной датой recurrenceID:
+// только его (ScopeThis) или его и все последующие (ScopeFollowing).
+func (d *DefaultService) UpdateOccurrences(ctx context.Context, event *models.Event, recurrenceID time.Time, scope models.Scope) error {
+	master, rule, occStart, err := d.seriesHelper(ctx, event.ID, recurrenceID)
+	if err != nil {
+		return err
+	}
+	if err := normalizeTimesHelper(event, master.TimeZone); err != nil {
+		return err
+	}
+	if event.Reminders == nil {
+		event.Reminders = master.Reminders
+	}
+	event.Reminders = normalizeRemindersHelper(event.Reminders)
+	if event.Tags == nil {
+		event.Tags = master.Tags
+	}
+	if event.Tags, err = normalizeTagsHelper(event.Tags); err != nil {
+		return err
+	}
+
+	switch scope {
+	case models.ScopeThis:
+		override := &models.Event{
+			UserID:       master.UserID,
+			Start:        event.Start,
+			End:          event.End,
+			AllDay:       event.AllDay,
+			TimeZone:     event.TimeZone,
+			Title:        event.Title,
+			Description:  event.Description,
+			Tags:         event.Tags,
+			ParentID:     &master.ID,
+			RecurrenceID: &recurrenceID,
+			Reminders:    event.Reminders,
+		}
+		_, err = d.repo.SaveOverride(ctx, override)
+		return err
+	case models.ScopeFollowing:
+		// изменение с первого вхождения затрагивает всю серию
+		if occStart.Equal(master.Start) {
+			if event.RRule == "" {
+				event.RRule = master.RRule
+			}
+			return d.UpdateEvent(ctx, event)
+		}
+
+		following := &models.Event{
+			UserID:      master.UserID,
+			Start:       event.Start,
+			End:         event.End,
+			AllDay:      event.AllDay,
+			TimeZone:    event.TimeZone,
+			Title:       event.Title,
+			Description: event.Description,
+			Tags:        event.Tags,
+			RRule:       event.RRule,
+			Reminders:   event.Reminders,
+			Attendees:   event.Attendees,
+		}
+		if following.Attendees == nil {
+			following.Attendees = master.Attendees
+		}
+		if err := normalizeAttendeesHelper(following, master.Attendees); err != nil {
+			return err
+		}
+		if following.RRule == "" {
+			rest := *rule
+			if rule.Count > 0 {
+				rest.Count = rule.Count - rule.CountBefore(master.Start.In(master.Location()), occStart)
+			}
+			following.RRule = rest.String()
+		}
+		if err := normalizeRRuleHelper(following); err != nil {
+			return err
+		}
+		following.ExDates = datesFromHelper(master.ExDates, recurrenceID)
+
+		truncateRuleHelper(master, rule, occStart, recurrenceID)
+		_, err = d.repo.SplitSeries(ctx, master, following, recurrenceID)
+		return err
+	default:
+		return invalidf("недопустимая область изменения %q", scope)
+	}
+}
+
+// DeleteOccurrences удаляет вхождение серии eventID с датой recurrenceID
+// или, для ScopeFollowing, его и все последующие.
+func (d *DefaultService) DeleteOccurrences(ctx context.Context, eventID int, recurrenceID time.Time, scope models.Scope) error {
+	master, rule, occStart, err := d.seriesHelper(ctx, eventID, recurrenceID)
+	if err != nil {
+		return err
+	}
+
+	switch scope {
+	case models.ScopeThis:
+		return d.repo.DeleteOccurrence(ctx, eventID, recurrenceID)
+	case models.ScopeFollowing:
+		if occStart.Equal(master.Start) {
+			return d.repo.DeleteEvent(ctx, eventID, 0)
+		}
+		truncateRuleHelper(master, rule, occStart, recurrenceID)
+		_, err = d.repo.SplitSeries(ctx, master, nil, recurrenceID)
+		return err
+	default:
+		return invalidf("недопустимая область изменения %q", scope)
+	}
+}
+
+// DeleteEvent удаляет событие по ID, если текущий пользователь может его изменять.
+// При version != 0 событие удаляется, только если с тех пор его никто не изменил.
+func (d *DefaultService) DeleteEvent(ctx context.Context, eventID, version int) error {
+	if _, err := d.editableEventHelper(ctx, eventID); err != nil {
+		return err
+	}
+	return d.repo.DeleteEvent(ctx, eventID, version)
 }
 
 // EventsForDay возвращает список событий пользователя за указанный день.
 func (d *DefaultService) EventsForDay(ctx context.Context, userID int, dateStr string) ([]*models.Event, error) {
+	if err := d.accessHelper(ctx, userID, models.PermissionRead); err != nil {
+		return nil, err
+	}
 	return d.repo.EventsForDay(ctx, userID, dateStr)
 }
 
 // EventsForWeek возвращает список событий пользователя за указанную неделю.
 func (d *DefaultService) EventsForWeek(ctx context.Context, userID int, dateStr string) ([]*models.Event, error) {
+	if err := d.accessHelper(ctx, userID, models.PermissionRead); err != nil {
+		return nil, err
+	}
 	return d.repo.EventsForWeek(ctx, userID, dateStr)
 }
 
 // EventsForMonth возвращает список событий пользователя за указанный месяц.
 func (d *DefaultService) EventsForMonth(ctx context.Context, userID int, dateStr string) ([]*models.Event, error) {
+	if err := d.accessHelper(ctx, userID, models.PermissionRead); err != nil {
+		return nil, err
+	}
 	return d.repo.EventsForMonth(ctx, userID, dateStr)
 }
 
-// isPastDateHelper проверяет, находится ли дата в прошлом (с учётом дня без времени).
-func isPastDateHelper(t time.Time) bool {
-	now := time.Now().Truncate(24 * time.Hour)
-	date := t.Truncate(24 * time.Hour)
-	return date.Before(now)
+// SetUserTimeZone задаёт часовой пояс пользователя, в котором считаются границы дней, недель и месяцев.
+func (d *DefaultService) SetUserTimeZone(ctx context.Context, userID int, tz string) error {
+	if err := checkUserHelper(ctx, userID); err != nil {
+		return err
+	}
+	if _, err := time.LoadLocation(tz); err != nil || tz == "" || tz == "Local" {
+		return invalidf("неизвестный часовой пояс %q", tz)
+	}
+	return d.repo.SetUserTimeZone(ctx, userID, tz)
+}
+
+// userLocationHelper возвращает часовой пояс пользователя.
+func (d *DefaultService) userLocationHelper(ctx context.Context, userID int) (*time.Location, error) {
+	tz, err := d.repo.UserTimeZone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный часовой пояс пользователя %q: %w", tz, err)
+	}
+	return loc, nil
+}
+
+// isPastDateHelper проверяет, начинается ли событие раньше сегодняшнего дня. Для событий на весь день
+// "сегодня" считается в поясе пользователя, для остальных - в поясе события, а не сервера.
+func isPastDateHelper(event *models.Event, userLoc *time.Location, now time.Time) bool {
+	if event.AllDay {
+		return event.Start.Before(models.DateOf(now.In(userLoc)))
+	}
+	loc := event.Location()
+	return models.DateOf(event.Start.In(loc)).Before(models.DateOf(now.In(loc)))
+}
+
+// normalizeTimesHelper проверяет время события и приводит его к виду, в котором оно хранится:
+// у событий на весь день начало и конец - полночи UTC, конец не раньше следующего дня;
+// у остальных задаётся часовой пояс (по умолчанию defaultTZ), а пустой конец равен началу.
+func normalizeTimesHelper(event *models.Event, defaultTZ string) error {
+	if event.Start.IsZero() {
+		return invalidf("не задано начало события")
+	}
+	if !event.End.IsZero() && event.End.Before(event.Start) {
+		return invalidf("конец события раньше начала")
+	}
+
+	if event.AllDay {
+		event.TimeZone = ""
+		event.Start = models.DateOf(event.Start)
+		if event.End.IsZero() {
+			event.End = event.Start
+		} else {
+			event.End = models.DateOf(event.End)
+		}
+		if !event.End.After(event.Start) {
+			event.End = event.Start.AddDate(0, 0, 1)
+		}
+		return nil
+	}
+
+	if event.TimeZone == "" {
+		event.TimeZone = defaultTZ
+	}
+	if _, err := time.LoadLocation(event.TimeZone); err != nil || event.TimeZone == "" || event.TimeZone == "Local" {
+		return invalidf("неизвестный часовой пояс %q", event.TimeZone)
+	}
+	if event.End.IsZero() {
+		event.End = event.Start
+	}
+	return nil
+}
+
+// seriesHelper загружает серию и проверяет, что у неё есть вхождение на дату recurrenceID.
+// Возвращает также момент начала этого вхождения.
+func (d *DefaultService) seriesHelper(ctx context.Context, eventID int, recurrenceID time.Time) (*models.Event, *recurrence.Rule, time.Time, error) {
+	master, err := d.editableEventHelper(ctx, eventID)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	if master.RRule == "" {
+		return nil, nil, time.Time{}, invalidf("событие не является повторяющимся")
+	}
+	rule, err := recurrence.Parse(master.RRule)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	occStart := recurrence.OccurrenceStart(master, recurrenceID)
+	if !rule.Occurs(master.Start.In(master.Location()), occStart) {
+		return nil, nil, time.Time{}, invalidf("у серии нет вхождения %s", recurrenceID.Format(recurrence.DateLayout))
+	}
+	return master, rule, occStart, nil
+}
+
+// normalizeRRuleHelper проверяет правило повторения и приводит его к каноничному виду.
+func normalizeRRuleHelper(event *models.Event) error {
+	if event.RRule == "" {
+		return nil
+	}
+	rule, err := recurrence.Parse(event.RRule)
+	if err != nil {
+		return invalidf("%v", err)
+	}
+	event.RRule = rule.String()
+	return nil
+}
+
+// normalizeRemindersHelper сортирует смещения напоминаний и убирает повторы.
+func normalizeRemindersHelper(offsets []models.ReminderOffset) []models.ReminderOffset {
+	if offsets == nil {
+		return nil
+	}
+	result := slices.Clone(offsets)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// truncateRuleHelper обрезает серию так, чтобы она заканчивалась до вхождения, начинающегося
+// в occStart с датой date.
+func truncateRuleHelper(master *models.Event, rule *recurrence.Rule, occStart, date time.Time) {
+	truncated := *rule
+	if rule.Count > 0 {
+		truncated.Count = rule.CountBefore(master.Start.In(master.Location()), occStart)
+	} else {
+		truncated.Until = occStart.Add(-time.Second)
+	}
+	master.RRule = truncated.String()
+
+	var kept []time.Time
+	for _, d := range master.ExDates {
+		if d.Before(date) {
+			kept = append(kept, d)
+		}
+	}
+	master.ExDates = kept
+}
+
+// datesFromHelper возвращает даты не раньше at.
+func datesFromHelper(dates []time.Time, at time.Time) []time.Time {
+	var result []time.Time
+	for _, d := range dates {
+		if !d.Before(at) {
+			result = append(result, d)
+		}
+	}
+	return result
 }
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"calendar/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPastDateHelper_EastOfUTC(t *testing.T) {
+	vladivostok, _ := time.LoadLocation("Asia/Vladivostok")
+	// 21 октября 02:00 во Владивостоке - это ещё 20 октября в UTC
+	now := time.Date(2025, 10, 21, 2, 0, 0, 0, vladivostok)
+
+	today := &models.Event{AllDay: true, Start: time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC)}
+	yesterday := &models.Event{AllDay: true, Start: time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)}
+	assert.False(t, isPastDateHelper(today, vladivostok, now), "событие на сегодня не в прошлом")
+	assert.True(t, isPastDateHelper(yesterday, vladivostok, now), "вчерашнее событие в прошлом")
+
+	morning := &models.Event{
+		TimeZone: "Asia/Vladivostok",
+		Start:    time.Date(2025, 10, 21, 1, 0, 0, 0, vladivostok),
+	}
+	assert.False(t, isPastDateHelper(morning, vladivostok, now), "событие сегодня утром не в прошлом")
+}
+
+func TestNormalizeTimesHelper(t *testing.T) {
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+
+	allDay := &models.Event{AllDay: true, TimeZone: "Europe/Moscow", Start: time.Date(2025, 10, 21, 0, 0, 0, 0, moscow)}
+	require.NoError(t, normalizeTimesHelper(allDay, "UTC"))
+	assert.Equal(t, time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC), allDay.Start)
+	assert.Equal(t, time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC), allDay.End)
+	assert.Empty(t, allDay.TimeZone)
+
+	timed := &models.Event{Start: time.Date(2025, 10, 21, 10, 0, 0, 0, moscow)}
+	require.NoError(t, normalizeTimesHelper(timed, "Europe/Moscow"))
+	assert.Equal(t, "Europe/Moscow", timed.TimeZone)
+	assert.Equal(t, timed.Start, timed.End)
+
+	backwards := &models.Event{Start: time.Date(2025, 10, 21, 10, 0, 0, 0, moscow), End: time.Date(2025, 10, 21, 9, 0, 0, 0, moscow)}
+	assert.Error(t, normalizeTimesHelper(backwards, "UTC"))
+
+	unknown := &models.Event{Start: time.Now(), TimeZone: "Mars/Olympus"}
+	assert.Error(t, normalizeTimesHelper(unknown, "UTC"))
+}
+
+func TestFreeBusyHelpers(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 10, 21, h, m, 0, 0, time.UTC) }
+	busy := mergeIntervalsHelper([]models.Interval{
+		{Start: at(13, 0), End: at(14, 0)},
+		{Start: at(9, 0), End: at(10, 0)},
+		{Start: at(9, 30), End: at(10, 30)},
+		{Start: at(10, 30), End: at(11, 0)},
+	})
+	assert.Equal(t, []models.Interval{
+		{Start: at(9, 0), End: at(11, 0)},
+		{Start: at(13, 0), End: at(14, 0)},
+	}, busy)
+
+	free := freeIntervalsHelper(busy, at(9, 0), at(15, 0), time.Hour)
+	assert.Equal(t, []models.Interval{
+		{Start: at(11, 0), End: at(13, 0)},
+		{Start: at(14, 0), End: at(15, 0)},
+	}, free)
+
+	slots := slotsHelper(free, 45*time.Minute, 3)
+	assert.Equal(t, []models.Interval{
+		{Start: at(11, 0), End: at(11, 45)},
+		{Start: at(11, 45), End: at(12, 30)},
+		{Start: at(14, 0), End: at(14, 45)},
+	}, slots)
+}
+
+func TestNormalizeAttendeesHelper(t *testing.T) {
+	event := &models.Event{
+		UserID: 1,
+		Attendees: []models.Attendee{
+			{UserID: 2, Status: models.StatusAccepted},
+			{UserID: 1},
+			{UserID: 3, Status: models.StatusAccepted},
+			{UserID: 2},
+		},
+	}
+	existing := []models.Attendee{{UserID: 2, Status: models.StatusTentative}}
+
+	require.NoError(t, normalizeAttendeesHelper(event, existing))
+	// организатор и повторы убираются, статус за другого пользователя задать нельзя
+	assert.Equal(t, []models.Attendee{
+		{UserID: 2, Status: models.StatusTentative},
+		{UserID: 3, Status: models.StatusNeedsAction},
+	}, event.Attendees)
+
+	bad := &models.Event{UserID: 1, Attendees: []models.Attendee{{UserID: 0}}}
+	assert.Error(t, normalizeAttendeesHelper(bad, nil))
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"calendar/internal/models"
+)
+
+// RespondToEvent сохраняет ответ текущего пользователя на приглашение. Ответ на вхождение
+// серии относится ко всей серии.
+func (d *DefaultService) RespondToEvent(ctx context.Context, eventID int, status models.AttendeeStatus) error {
+	current, err := currentUserHelper(ctx)
+	if err != nil {
+		return err
+	}
+	if err := status.Validate(); err != nil {
+		return invalidf("%v", err)
+	}
+	event, err := d.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	invited := false
+	for _, a := range event.Attendees {
+		if a.UserID == current {
+			invited = true
+			break
+		}
+	}
+	if !invited {
+		return fmt.Errorf("%w: пользователь %d не приглашён на событие %d", ErrForbidden, current, eventID)
+	}
+
+	seriesID := event.ID
+	if event.ParentID != nil {
+		seriesID = *event.ParentID
+	}
+	return d.repo.SetAttendeeStatus(ctx, seriesID, current, status)
+}
+
+// ShareCalendar открывает пользователю доступ к календарю. Управлять доступом может только владелец.
+func (d *DefaultService) ShareCalendar(ctx context.Context, share *models.CalendarShare) error {
+	if err := checkUserHelper(ctx, share.OwnerID); err != nil {
+		return err
+	}
+	if err := share.Permission.Validate(); err != nil {
+		return invalidf("%v", err)
+	}
+	if share.UserID <= 0 || share.UserID == share.OwnerID {
+		return invalidf("доступ открывается другому пользователю")
+	}
+	return d.repo.ShareCalendar(ctx, share)
+}
+
+// UnshareCalendar закрывает доступ к календарю. Это может сделать владелец
+// или сам пользователь, которому доступ был открыт.
+func (d *DefaultService) UnshareCalendar(ctx context.Context, ownerID, userID int) error {
+	current, err := currentUserHelper(ctx)
+	if err != nil {
+		return err
+	}
+	if current != ownerID && current != userID {
+		return fmt.Errorf("%w: доступы к календарю пользователя %d", ErrForbidden, ownerID)
+	}
+	return d.repo.UnshareCalendar(ctx, ownerID, userID)
+}
+
+// CalendarShares возвращает пользователей, которым открыт календарь владельца.
+func (d *DefaultService) CalendarShares(ctx context.Context, ownerID int) ([]*models.CalendarShare, error) {
+	if err := checkUserHelper(ctx, ownerID); err != nil {
+		return nil, err
+	}
+	return d.repo.CalendarShares(ctx, ownerID)
+}
+
+// normalizeAttendeesHelper проверяет список участников и убирает повторы и организатора.
+// Статус нельзя задать за другого пользователя: у уже приглашённых из existing он сохраняется,
+// остальные получают needs-action. Attendees == nil означает, что участники не меняются.
+func normalizeAttendeesHelper(event *models.Event, existing []models.Attendee) error {
+	if event.Attendees == nil {
+		return nil
+	}
+	statuses := make(map[int]models.AttendeeStatus, len(existing))
+	for _, a := range existing {
+		statuses[a.UserID] = a.Status
+	}
+
+	seen := make(map[int]bool)
+	attendees := make([]models.Attendee, 0, len(event.Attendees))
+	for _, a := range event.Attendees {
+		if a.UserID <= 0 {
+			return invalidf("некорректный участник события %d", a.UserID)
+		}
+		if a.UserID == event.UserID || seen[a.UserID] {
+			continue
+		}
+		seen[a.UserID] = true
+		status, ok := statuses[a.UserID]
+		if !ok {
+			status = models.StatusNeedsAction
+		}
+		attendees = append(attendees, models.Attendee{UserID: a.UserID, Status: status})
+	}
+	event.Attendees = attendees
+	return nil
+}
@@ -1,10 +1,95 @@
+create table Users(
+    id integer primary key,
+    time_zone text not null default 'UTC',
+    email text not null default ''
+);
+
 create table Events(
     id serial primary key,
+    uid text not null default gen_random_uuid()::text,
     user_id integer not null,
-    date date not null,
+    start_at timestamptz not null,
+    end_at timestamptz not null,
+    all_day boolean not null default false,
+    time_zone text not null default 'UTC',
     title text not null,
+    description text not null default '',
+    tags text[] not null default '{}',
+    rrule text not null default '',
+    exdates date[] not null default '{}',
+    parent_id integer references Events(id) on delete cascade,
+    recurrence_id date,
+    reminders integer[] not null default '{}', -- за сколько секунд до начала напомнить
+    version integer not null default 1,
     created_at timestamp default now(),
-    updated_at timestamp default now()
+    updated_at timestamp default now(),
+    search tsvector generated always as (
+        setweight(to_tsvector('russian', title), 'A') || setweight(to_tsvector('russian', description), 'B')
+    ) stored,
+    check (end_at >= start_at)
+);
+
+create index start_idx on Events(user_id, start_at);
+create unique index override_idx on Events(parent_id, recurrence_id) where parent_id is not null;
+create unique index uid_idx on Events(user_id, uid) where parent_id is null;
+create index search_idx on Events using gin(search);
+create index tags_idx on Events using gin(tags);
+
+create table Api_tokens(
+    token_hash text primary key,
+    user_id integer not null,
+    name text not null default '',
+    created_at timestamptz not null default now(),
+    expires_at timestamptz,
+    last_used_at timestamptz,
+    revoked_at timestamptz
 );
 
-create index date_idx on Events(date);
\ No newline at end of file
+create index api_tokens_user_idx on Api_tokens(user_id);
+
+create table Reminders(
+    id serial primary key,
+    event_id integer not null references Events(id) on delete cascade,
+    offset_seconds integer not null,
+    fire_at timestamptz,
+    occurrence_at timestamptz,
+    last_occurrence_at timestamptz,
+    attempts integer not null default 0,
+    done boolean not null default false, -- вхождений, о которых нужно напомнить, больше нет
+    lease text,
+    locked_until timestamptz,
+    unique (event_id, offset_seconds)
+);
+
+create index reminders_fire_idx on Reminders(fire_at nulls first);
+
+create table Reminder_deliveries(
+    id serial primary key,
+    reminder_id integer not null references Reminders(id) on delete cascade,
+    occurrence_at timestamptz not null,
+    attempt integer not null,
+    notifier text not null,
+    error text not null default '',
+    created_at timestamptz not null default now()
+);
+
+create index reminder_deliveries_reminder_idx on Reminder_deliveries(reminder_id);
+
+create table Attendees(
+    event_id integer not null references Events(id) on delete cascade,
+    user_id integer not null,
+    status text not null default 'needs-action'
+        check (status in ('needs-action', 'accepted', 'declined', 'tentative')),
+    updated_at timestamptz not null default now(),
+    primary key (event_id, user_id)
+);
+
+create index attendees_user_idx on Attendees(user_id, event_id);
+
+create table Calendar_shares(
+    owner_id integer not null,
+    user_id integer not null,
+    permission text not null check (permission in ('read', 'write')),
+    created_at timestamptz not null default now(),
+    primary key (owner_id, user_id)
+);
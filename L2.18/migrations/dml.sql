@@ -1,5 +1,14 @@
-insert into Events(user_id, date,title)
+insert into Users(id, time_zone)
+values
+    (1, 'Europe/Moscow'),
+    (2, 'Asia/Novosibirsk');
+
+insert into Events(user_id, start_at, end_at, all_day, title)
 values 
-    (1, '2025-10-18', 'Go project work'),
-    (1, '2025-10-20', 'Test SQL scripts'),
-    (2, '2025-11-01', 'Deadline for assignment');
\ No newline at end of file
+    (1, '2025-10-18 00:00+00', '2025-10-19 00:00+00', true, 'Go project work'),
+    (1, '2025-10-20 00:00+00', '2025-10-21 00:00+00', true, 'Test SQL scripts'),
+    (2, '2025-11-01 00:00+00', '2025-11-02 00:00+00', true, 'Deadline for assignment');
+
+insert into Events(user_id, start_at, end_at, time_zone, title, rrule)
+values
+    (1, '2025-10-20 10:00+03', '2025-10-20 10:15+03', 'Europe/Moscow', 'Weekly standup', 'FREQ=WEEKLY;BYDAY=MO,TH');
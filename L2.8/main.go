@@ -1,15 +1,98 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"myntp/myntp"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 )
 
 func main() {
-	time, err := myntp.GetNTPTime()
+	servers := flag.String("servers", strings.Join(myntp.DefaultServers, ","), "NTP серверы через запятую")
+	threshold := flag.Duration("threshold", 0, "максимально допустимое смещение часов, 0 — не проверять")
+	timeout := flag.Duration("timeout", 5*time.Second, "таймаут запроса к одному серверу")
+	serve := flag.String("serve", "", "запустить SNTP сервер на адресе, например :123")
+	upstream := flag.Bool("upstream", false, "в режиме -serve синхронизировать часы с -servers")
+	rateLimit := flag.Float64("rate", 1, "в режиме -serve запросов в секунду от одного клиента, 0 — без ограничения")
+	flag.Parse()
+
+	cfg := myntp.QueryConfig{Timeout: *timeout}
+	for _, s := range strings.Split(*servers, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			cfg.Servers = append(cfg.Servers, s)
+		}
+	}
+
+	if *serve != "" {
+		runServer(*serve, cfg, *upstream, *rateLimit)
+		return
+	}
+
+	report, err := myntp.QueryServers(context.Background(), cfg)
+	if report != nil {
+		printReport(report)
+	}
 	if err != nil {
+		if errors.Is(err, myntp.ErrNoMajority) {
+			log.Println("Ошибка выбора времени: ", err)
+			os.Exit(1)
+		}
 		log.Fatal("Ошибка получения времени: ", err)
 	}
-	fmt.Println(time)
+
+	fmt.Println(report.Time())
+	if *threshold > 0 && (report.Offset > *threshold || report.Offset < -*threshold) {
+		fmt.Fprintf(os.Stderr, "Смещение часов %v превышает порог %v\n", report.Offset, *threshold)
+		os.Exit(2)
+	}
+}
+
+func runServer(addr string, cfg myntp.QueryConfig, upstream bool, rateLimit float64) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var clock myntp.Clock = myntp.LocalClock{}
+	if upstream {
+		disciplined := myntp.NewDisciplinedClock(cfg)
+		go disciplined.Run(ctx, time.Minute)
+		clock = disciplined
+	}
+
+	server := myntp.NewServer(myntp.ServerConfig{Addr: addr, Clock: clock, RateLimit: rateLimit, Burst: 4})
+	log.Println("SNTP сервер запущен на ", addr)
+	if err := server.ListenAndServe(ctx); err != nil {
+		log.Fatal("Ошибка SNTP сервера: ", err)
+	}
+}
+
+func printReport(r *myntp.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tSERVER\tOFFSET\tRTT\tSTRATUM\tLEAP\tSTATUS")
+	for i, res := range r.Results {
+		mark := ""
+		if i == r.Selected {
+			mark = "*"
+		}
+		if !res.Valid() {
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t-\t%v\n", mark, res.Server, res.Err)
+			continue
+		}
+		status := "ok"
+		if res.Falseticker {
+			status = "falseticker"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%v\t%v\t%d\t%d\t%s\n", mark, res.Server, res.Offset, res.RTT, res.Stratum, res.Leap, status)
+	}
+	w.Flush()
+	if r.Selected >= 0 {
+		fmt.Printf("Пересечение: [%v, %v], согласны %d серверов, смещение: %v\n", r.Low, r.High, r.Agreeing, r.Offset)
+	}
 }
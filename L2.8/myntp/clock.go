@@ -0,0 +1,155 @@
+package myntp
+
+import (
+	"context"
+	"crypto/md5"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/beevik/ntp"
+)
+
+// LocalStratum стратум, с которым сервер отдаёт несинхронизированные локальные часы
+const LocalStratum = 10
+
+// ClockStatus параметры синхронизации часов, попадающие в заголовок ответа
+type ClockStatus struct {
+	Stratum        uint8
+	ReferenceID    [4]byte
+	ReferenceTime  time.Time
+	RootDelay      time.Duration
+	RootDispersion time.Duration
+	Leap           ntp.LeapIndicator
+}
+
+// Clock источник времени для SNTP сервера
+type Clock interface {
+	Now() time.Time
+	Status() ClockStatus
+}
+
+// LocalClock отдаёт системное время без внешней синхронизации
+type LocalClock struct {
+	Stratum uint8 // если 0, используется LocalStratum
+}
+
+// Now возвращает системное время
+func (c LocalClock) Now() time.Time {
+	return time.Now()
+}
+
+// Status описывает локальные часы как эталон с идентификатором LOCL
+func (c LocalClock) Status() ClockStatus {
+	stratum := c.Stratum
+	if stratum == 0 {
+		stratum = LocalStratum
+	}
+	return ClockStatus{
+		Stratum:       stratum,
+		ReferenceID:   [4]byte{'L', 'O', 'C', 'L'},
+		ReferenceTime: time.Now(),
+	}
+}
+
+// DisciplinedClock корректирует системное время по смещению,
+// полученному от вышестоящих серверов через QueryServers
+type DisciplinedClock struct {
+	cfg QueryConfig
+
+	mu     sync.RWMutex
+	offset time.Duration
+	status ClockStatus
+	synced bool
+}
+
+// NewDisciplinedClock - конструктор для DisciplinedClock. До первой успешной
+// синхронизации часы считаются несинхронизированными
+func NewDisciplinedClock(cfg QueryConfig) *DisciplinedClock {
+	return &DisciplinedClock{
+		cfg: cfg,
+		status: ClockStatus{
+			Stratum: maxStratum,
+			Leap:    ntp.LeapNotInSync,
+		},
+	}
+}
+
+// Now возвращает системное время с поправкой на последнее смещение
+func (c *DisciplinedClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return time.Now().Add(c.offset)
+}
+
+// Status возвращает параметры последней синхронизации
+func (c *DisciplinedClock) Status() ClockStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
+}
+
+// Synced сообщает, была ли хотя бы одна успешная синхронизация
+func (c *DisciplinedClock) Synced() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.synced
+}
+
+// Sync опрашивает вышестоящие серверы и обновляет смещение
+func (c *DisciplinedClock) Sync(ctx context.Context) error {
+	report, err := QueryServers(ctx, c.cfg)
+	if err != nil {
+		return err
+	}
+	selected := report.Results[report.Selected]
+
+	// идентификатор вышестоящего сервера: первые 4 байта MD5 от его адреса,
+	// как для IPv6 в RFC 5905
+	sum := md5.Sum([]byte(selected.Server))
+	var refID [4]byte
+	copy(refID[:], sum[:4])
+
+	stratum := selected.Stratum + 1
+	if stratum >= maxStratum {
+		stratum = maxStratum - 1
+	}
+
+	// по RFC 5905 задержка и дисперсия до первичного источника накапливаются по цепочке:
+	// к значениям вышестоящего сервера добавляются задержка до него и погрешность измерения
+	rootDelay := selected.RootDelay + selected.RTT
+	rootDispersion := selected.RootDispersion + selected.Precision
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.offset = report.Offset
+	c.synced = true
+	c.status = ClockStatus{
+		Stratum:        stratum,
+		ReferenceID:    refID,
+		ReferenceTime:  time.Now().Add(report.Offset),
+		RootDelay:      rootDelay,
+		RootDispersion: rootDispersion,
+		Leap:           selected.Leap,
+	}
+	return nil
+}
+
+// Run синхронизирует часы сразу и затем с заданным интервалом, пока не отменён ctx
+func (c *DisciplinedClock) Run(ctx context.Context, interval time.Duration) {
+	if err := c.Sync(ctx); err != nil && ctx.Err() == nil {
+		log.Println("ошибка синхронизации часов: ", err)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Sync(ctx); err != nil && ctx.Err() == nil {
+				log.Println("ошибка синхронизации часов: ", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package myntp
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+const (
+	packetSize = 48
+
+	modeClient = 3
+	modeServer = 4
+
+	maxStratum = 16
+)
+
+var ntpEpoch = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var errShortPacket = errors.New("слишком короткий NTP пакет")
+
+// packet заголовок NTP пакета (RFC 5905, раздел 7.3)
+type packet struct {
+	LiVnMode       uint8
+	Stratum        uint8
+	Poll           int8
+	Precision      int8
+	RootDelay      uint32
+	RootDispersion uint32
+	ReferenceID    [4]byte
+	ReferenceTime  uint64
+	OriginTime     uint64
+	ReceiveTime    uint64
+	TransmitTime   uint64
+}
+
+func (p *packet) mode() uint8    { return p.LiVnMode & 0x07 }
+func (p *packet) version() uint8 { return (p.LiVnMode >> 3) & 0x07 }
+
+func (p *packet) setHeader(leap, version, mode uint8) {
+	p.LiVnMode = leap<<6 | (version&0x07)<<3 | mode&0x07
+}
+
+func parsePacket(b []byte) (*packet, error) {
+	if len(b) < packetSize {
+		return nil, errShortPacket
+	}
+	p := &packet{
+		LiVnMode:       b[0],
+		Stratum:        b[1],
+		Poll:           int8(b[2]),
+		Precision:      int8(b[3]),
+		RootDelay:      binary.BigEndian.Uint32(b[4:]),
+		RootDispersion: binary.BigEndian.Uint32(b[8:]),
+		ReferenceTime:  binary.BigEndian.Uint64(b[16:]),
+		OriginTime:     binary.BigEndian.Uint64(b[24:]),
+		ReceiveTime:    binary.BigEndian.Uint64(b[32:]),
+		TransmitTime:   binary.BigEndian.Uint64(b[40:]),
+	}
+	copy(p.ReferenceID[:], b[12:16])
+	return p, nil
+}
+
+func (p *packet) marshal() []byte {
+	b := make([]byte, packetSize)
+	b[0] = p.LiVnMode
+	b[1] = p.Stratum
+	b[2] = byte(p.Poll)
+	b[3] = byte(p.Precision)
+	binary.BigEndian.PutUint32(b[4:], p.RootDelay)
+	binary.BigEndian.PutUint32(b[8:], p.RootDispersion)
+	copy(b[12:16], p.ReferenceID[:])
+	binary.BigEndian.PutUint64(b[16:], p.ReferenceTime)
+	binary.BigEndian.PutUint64(b[24:], p.OriginTime)
+	binary.BigEndian.PutUint64(b[32:], p.ReceiveTime)
+	binary.BigEndian.PutUint64(b[40:], p.TransmitTime)
+	return b
+}
+
+// toNTPTime переводит время в 64-битный формат NTP (32.32 секунды от 1900 года)
+func toNTPTime(t time.Time) uint64 {
+	d := t.Sub(ntpEpoch)
+	sec := uint64(d / time.Second)
+	nsec := uint64(d % time.Second)
+	return sec<<32 | (nsec<<32)/uint64(time.Second)
+}
+
+// toNTPShort переводит длительность в 32-битный формат NTP (16.16 секунд)
+func toNTPShort(d time.Duration) uint32 {
+	if d < 0 {
+		d = 0
+	}
+	sec := uint64(d / time.Second)
+	if sec > 0xffff {
+		return 0xffffffff
+	}
+	nsec := uint64(d % time.Second)
+	return uint32(sec<<16 | (nsec<<16)/uint64(time.Second))
+}
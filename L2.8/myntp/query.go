@@ -0,0 +1,147 @@
+package myntp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/beevik/ntp"
+)
+
+// DefaultServers список серверов, опрашиваемых по умолчанию
+var DefaultServers = []string{
+	"0.beevik-ntp.pool.ntp.org",
+	"1.beevik-ntp.pool.ntp.org",
+	"2.beevik-ntp.pool.ntp.org",
+	"3.beevik-ntp.pool.ntp.org",
+}
+
+// ErrNoServers возвращается, если список серверов пуст
+var ErrNoServers = errors.New("не задан ни один NTP сервер")
+
+// ErrNoMajority возвращается, если большинство серверов не согласуется между собой
+var ErrNoMajority = errors.New("не найдено пересечение интервалов у большинства серверов")
+
+// queryServer выполняет запрос к одному серверу, подменяется в тестах
+var queryServer = ntp.QueryWithOptions
+
+// QueryConfig настройки опроса нескольких серверов
+type QueryConfig struct {
+	Servers []string      // если пуст, используется DefaultServers
+	Timeout time.Duration // таймаут на один сервер, по умолчанию 5 секунд
+}
+
+// ServerResult результат опроса одного сервера
+type ServerResult struct {
+	Server       string
+	Offset       time.Duration // смещение локальных часов относительно сервера
+	RTT          time.Duration
+	RootDistance time.Duration // полуширина интервала корректности
+	// RootDelay, RootDispersion и Precision сервера из его ответа
+	RootDelay      time.Duration
+	RootDispersion time.Duration
+	Precision      time.Duration
+	Stratum        uint8
+	Leap           ntp.LeapIndicator
+	Err            error
+	Falseticker    bool // интервал сервера не пересекается с выбранным
+}
+
+// Valid сообщает, участвует ли результат в выборе времени
+func (r ServerResult) Valid() bool {
+	return r.Err == nil
+}
+
+// Report итог опроса всех серверов
+type Report struct {
+	Results  []ServerResult // в том же порядке, что и QueryConfig.Servers
+	Selected int            // индекс выбранного сервера в Results
+	Offset   time.Duration  // смещение выбранного сервера
+	Low      time.Duration  // нижняя граница пересечения интервалов
+	High     time.Duration  // верхняя граница пересечения интервалов
+	Agreeing int            // число серверов, интервалы которых пересекаются
+}
+
+// Time возвращает локальное время, скорректированное на выбранное смещение
+func (r *Report) Time() time.Time {
+	return time.Now().Add(r.Offset)
+}
+
+// QueryServers параллельно опрашивает серверы и выбирает результат по алгоритму
+// Марзулло: ищется пересечение интервалов [offset-distance, offset+distance],
+// которое поддерживает большинство серверов, остальные помечаются как falseticker.
+// Report возвращается и вместе с ErrNoMajority, чтобы можно было вывести результаты
+func QueryServers(ctx context.Context, cfg QueryConfig) (*Report, error) {
+	servers := cfg.Servers
+	if len(servers) == 0 {
+		servers = DefaultServers
+	}
+	if len(servers) == 0 {
+		return nil, ErrNoServers
+	}
+
+	results := make([]ServerResult, len(servers))
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Add(1)
+		go func(i int, server string) {
+			defer wg.Done()
+			results[i] = queryOne(ctx, server, cfg.Timeout)
+		}(i, server)
+	}
+	wg.Wait()
+
+	report := &Report{Results: results, Selected: -1}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	if err := selectResult(report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func queryOne(ctx context.Context, server string, timeout time.Duration) ServerResult {
+	res := ServerResult{Server: server}
+
+	type answer struct {
+		resp *ntp.Response
+		err  error
+	}
+	ch := make(chan answer, 1)
+	go func() {
+		resp, err := queryServer(server, ntp.QueryOptions{Timeout: timeout})
+		ch <- answer{resp: resp, err: err}
+	}()
+
+	var a answer
+	select {
+	case <-ctx.Done():
+		res.Err = ctx.Err()
+		return res
+	case a = <-ch:
+	}
+	if a.err != nil {
+		res.Err = a.err
+		return res
+	}
+
+	resp := a.resp
+	res.Offset = resp.ClockOffset
+	res.RTT = resp.RTT
+	res.RootDelay = resp.RootDelay
+	res.RootDispersion = resp.RootDispersion
+	res.Precision = resp.Precision
+	res.RootDistance = resp.RootDistance
+	if res.RootDistance <= 0 {
+		res.RootDistance = resp.RTT / 2
+	}
+	res.Stratum = resp.Stratum
+	res.Leap = resp.Leap
+	if err := resp.Validate(); err != nil {
+		res.Err = fmt.Errorf("некорректный ответ: %w", err)
+	}
+	return res
+}
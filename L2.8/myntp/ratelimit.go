@@ -0,0 +1,75 @@
+package myntp
+
+import (
+	"sync"
+	"time"
+)
+
+// maxTrackedClients после превышения этого числа из таблицы удаляются клиенты с полным бакетом
+const maxTrackedClients = 10000
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	kissed bool // kiss-o'-death уже отправлен в текущем эпизоде превышения
+}
+
+// rateLimiter ограничивает частоту запросов от одного клиента (token bucket)
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	clients map[string]*bucket
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		clients: make(map[string]*bucket),
+	}
+}
+
+// allow возвращает true, если запрос можно обслужить. Если нельзя,
+// kiss показывает, нужно ли отправить клиенту kiss-o'-death
+func (l *rateLimiter) allow(client string, now time.Time) (ok, kiss bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, found := l.clients[client]
+	if !found {
+		if len(l.clients) >= maxTrackedClients {
+			l.prune(now)
+		}
+		b = &bucket{tokens: l.burst, last: now}
+		l.clients[client] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		b.kissed = false
+		return true, false
+	}
+	if b.kissed {
+		return false, false
+	}
+	b.kissed = true
+	return false, true
+}
+
+func (l *rateLimiter) prune(now time.Time) {
+	for client, b := range l.clients {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.clients, client)
+		}
+	}
+}
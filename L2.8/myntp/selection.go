@@ -0,0 +1,81 @@
+package myntp
+
+import (
+	"sort"
+	"time"
+)
+
+type edge struct {
+	offset time.Duration
+	kind   int // -1 начало интервала, +1 конец
+}
+
+// marzullo находит отрезок, покрытый наибольшим числом интервалов.
+// Интервалы задаются центром и полушириной
+func marzullo(centers, radii []time.Duration) (low, high time.Duration, count int) {
+	edges := make([]edge, 0, 2*len(centers))
+	for i := range centers {
+		edges = append(edges,
+			edge{offset: centers[i] - radii[i], kind: -1},
+			edge{offset: centers[i] + radii[i], kind: +1},
+		)
+	}
+	// при равных значениях начала идут раньше концов, чтобы касающиеся интервалы считались пересекающимися
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].offset != edges[j].offset {
+			return edges[i].offset < edges[j].offset
+		}
+		return edges[i].kind < edges[j].kind
+	})
+
+	current := 0
+	for i, e := range edges {
+		if e.kind == -1 {
+			current++
+			if current > count {
+				count = current
+				low = e.offset
+				high = edges[i+1].offset
+			}
+			continue
+		}
+		current--
+	}
+	return low, high, count
+}
+
+// selectResult помечает falseticker'ов и выбирает сервер с наименьшим
+// расстоянием до эталона среди согласующихся
+func selectResult(r *Report) error {
+	var idx []int
+	var centers, radii []time.Duration
+	for i, res := range r.Results {
+		if !res.Valid() {
+			continue
+		}
+		idx = append(idx, i)
+		centers = append(centers, res.Offset)
+		radii = append(radii, res.RootDistance)
+	}
+	if len(idx) == 0 {
+		return ErrNoMajority
+	}
+
+	low, high, count := marzullo(centers, radii)
+	r.Low, r.High, r.Agreeing = low, high, count
+	if count <= len(idx)/2 {
+		return ErrNoMajority
+	}
+
+	for k, i := range idx {
+		if centers[k]+radii[k] < low || centers[k]-radii[k] > high {
+			r.Results[i].Falseticker = true
+			continue
+		}
+		if r.Selected == -1 || r.Results[i].RootDistance < r.Results[r.Selected].RootDistance {
+			r.Selected = i
+		}
+	}
+	r.Offset = r.Results[r.Selected].Offset
+	return nil
+}
@@ -0,0 +1,137 @@
+package myntp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/beevik/ntp"
+)
+
+func TestMarzullo(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name      string
+		centers   []time.Duration
+		radii     []time.Duration
+		low, high time.Duration
+		count     int
+	}{
+		{
+			name:    "классический пример",
+			centers: []time.Duration{10 * ms, 11 * ms, 11 * ms},
+			radii:   []time.Duration{2 * ms, 1 * ms, 1 * ms},
+			low:     10 * ms, high: 12 * ms, count: 3,
+		},
+		{
+			name:    "один отстающий",
+			centers: []time.Duration{0, 1 * ms, 100 * ms},
+			radii:   []time.Duration{5 * ms, 5 * ms, 5 * ms},
+			low:     -4 * ms, high: 5 * ms, count: 2,
+		},
+		{
+			name:    "касающиеся интервалы",
+			centers: []time.Duration{0, 10 * ms},
+			radii:   []time.Duration{5 * ms, 5 * ms},
+			low:     5 * ms, high: 5 * ms, count: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			low, high, count := marzullo(tt.centers, tt.radii)
+			if low != tt.low || high != tt.high || count != tt.count {
+				t.Errorf("got=[%v,%v]x%d, want=[%v,%v]x%d", low, high, count, tt.low, tt.high, tt.count)
+			}
+		})
+	}
+}
+
+func fakeQuery(responses map[string]*ntp.Response) func(string, ntp.QueryOptions) (*ntp.Response, error) {
+	return func(server string, _ ntp.QueryOptions) (*ntp.Response, error) {
+		resp, ok := responses[server]
+		if !ok {
+			return nil, errors.New("timeout")
+		}
+		return resp, nil
+	}
+}
+
+func fakeResponse(offset, distance time.Duration) *ntp.Response {
+	now := time.Now()
+	return &ntp.Response{
+		Time:          now,
+		ReferenceTime: now.Add(-time.Minute),
+		ClockOffset:   offset,
+		RTT:           2 * distance,
+		RootDistance:  distance,
+		Stratum:       2,
+	}
+}
+
+func TestQueryServers_FlagsFalseticker(t *testing.T) {
+	old := queryServer
+	defer func() { queryServer = old }()
+	queryServer = fakeQuery(map[string]*ntp.Response{
+		"a": fakeResponse(10*time.Millisecond, 5*time.Millisecond),
+		"b": fakeResponse(12*time.Millisecond, 3*time.Millisecond),
+		"c": fakeResponse(2*time.Second, 5*time.Millisecond),
+	})
+
+	report, err := QueryServers(context.Background(), QueryConfig{Servers: []string{"a", "b", "c", "d"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.Results[2].Falseticker {
+		t.Errorf("сервер c должен быть falseticker")
+	}
+	if report.Results[3].Valid() {
+		t.Errorf("сервер d не ответил, результат должен быть невалидным")
+	}
+	if report.Selected != 1 || report.Offset != 12*time.Millisecond {
+		t.Errorf("ожидали выбор сервера b, получили %d (%v)", report.Selected, report.Offset)
+	}
+	if report.Agreeing != 2 {
+		t.Errorf("ожидали 2 согласных сервера, получили %d", report.Agreeing)
+	}
+}
+
+func TestQueryServers_NoMajority(t *testing.T) {
+	old := queryServer
+	defer func() { queryServer = old }()
+	queryServer = fakeQuery(map[string]*ntp.Response{
+		"a": fakeResponse(0, time.Millisecond),
+		"b": fakeResponse(time.Second, time.Millisecond),
+	})
+
+	report, err := QueryServers(context.Background(), QueryConfig{Servers: []string{"a", "b"}})
+	if !errors.Is(err, ErrNoMajority) {
+		t.Fatalf("ожидали ErrNoMajority, получили %v", err)
+	}
+	if report == nil || len(report.Results) != 2 {
+		t.Fatalf("отчёт должен возвращаться вместе с ошибкой")
+	}
+}
+
+func TestDisciplinedClock_AccumulatesRootDelay(t *testing.T) {
+	old := queryServer
+	defer func() { queryServer = old }()
+	resp := fakeResponse(0, 10*time.Millisecond)
+	resp.RootDelay = 30 * time.Millisecond
+	resp.RootDispersion = 5 * time.Millisecond
+	resp.Precision = time.Microsecond
+	queryServer = fakeQuery(map[string]*ntp.Response{"a": resp})
+
+	clock := NewDisciplinedClock(QueryConfig{Servers: []string{"a"}})
+	if err := clock.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	status := clock.Status()
+	if want := 30*time.Millisecond + resp.RTT; status.RootDelay != want {
+		t.Errorf("RootDelay = %v, ожидали %v", status.RootDelay, want)
+	}
+	if want := 5*time.Millisecond + time.Microsecond; status.RootDispersion != want {
+		t.Errorf("RootDispersion = %v, ожидали %v", status.RootDispersion, want)
+	}
+}
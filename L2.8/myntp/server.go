@@ -0,0 +1,142 @@
+package myntp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/beevik/ntp"
+)
+
+// DefaultServerAddr адрес, на котором SNTP сервер слушает по умолчанию
+const DefaultServerAddr = ":123"
+
+// serverPrecision точность часов сервера, log2 секунд (~1 мкс)
+const serverPrecision = -20
+
+// ServerConfig настройки SNTP сервера
+type ServerConfig struct {
+	Addr      string  // по умолчанию DefaultServerAddr
+	Clock     Clock   // по умолчанию LocalClock
+	RateLimit float64 // запросов в секунду от одного клиента, 0 — без ограничения
+	Burst     int     // допустимая пачка запросов сверх RateLimit
+}
+
+// Server SNTPv4 сервер (RFC 4330), отвечающий временем из Clock
+type Server struct {
+	addr    string
+	clock   Clock
+	limiter *rateLimiter
+}
+
+// NewServer - конструктор для Server
+func NewServer(cfg ServerConfig) *Server {
+	s := &Server{addr: cfg.Addr, clock: cfg.Clock}
+	if s.addr == "" {
+		s.addr = DefaultServerAddr
+	}
+	if s.clock == nil {
+		s.clock = LocalClock{}
+	}
+	if cfg.RateLimit > 0 {
+		s.limiter = newRateLimiter(cfg.RateLimit, cfg.Burst)
+	}
+	return s
+}
+
+// ListenAndServe открывает UDP сокет и обслуживает запросы до отмены ctx
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	pc, err := net.ListenPacket("udp", s.addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, pc)
+}
+
+// Serve обслуживает запросы на уже открытом сокете и закрывает его при отмене ctx
+func (s *Server) Serve(ctx context.Context, pc net.PacketConn) error {
+	defer pc.Close()
+	stop := context.AfterFunc(ctx, func() { _ = pc.Close() })
+	defer stop()
+
+	buf := make([]byte, 1024)
+	for {
+		n, addr, err := pc.ReadFrom(buf)
+		received := s.clock.Now()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		resp := s.handle(buf[:n], addr, received)
+		if resp != nil {
+			_, _ = pc.WriteTo(resp, addr)
+		}
+	}
+}
+
+// handle формирует ответ на запрос; nil означает, что запрос игнорируется
+func (s *Server) handle(data []byte, addr net.Addr, received time.Time) []byte {
+	req, err := parsePacket(data)
+	if err != nil || req.mode() != modeClient {
+		return nil
+	}
+	version := req.version()
+	if version < 1 || version > 4 {
+		return nil
+	}
+
+	if s.limiter != nil {
+		ok, kiss := s.limiter.allow(clientKey(addr), received)
+		if !ok {
+			if !kiss {
+				return nil
+			}
+			return s.kissOfDeath(req, version, "RATE", received)
+		}
+	}
+
+	status := s.clock.Status()
+	resp := &packet{
+		Stratum:        status.Stratum,
+		Poll:           req.Poll,
+		Precision:      serverPrecision,
+		RootDelay:      toNTPShort(status.RootDelay),
+		RootDispersion: toNTPShort(status.RootDispersion),
+		ReferenceID:    status.ReferenceID,
+		ReferenceTime:  toNTPTime(status.ReferenceTime),
+		OriginTime:     req.TransmitTime,
+		ReceiveTime:    toNTPTime(received),
+	}
+	resp.setHeader(uint8(status.Leap), version, modeServer)
+	resp.TransmitTime = toNTPTime(s.clock.Now())
+	return resp.marshal()
+}
+
+// kissOfDeath формирует ответ со стратумом 0 и кодом причины в ReferenceID
+func (s *Server) kissOfDeath(req *packet, version uint8, code string, received time.Time) []byte {
+	resp := &packet{
+		Poll:        req.Poll,
+		Precision:   serverPrecision,
+		OriginTime:  req.TransmitTime,
+		ReceiveTime: toNTPTime(received),
+	}
+	copy(resp.ReferenceID[:], code)
+	resp.setHeader(ntp.LeapNotInSync, version, modeServer)
+	resp.TransmitTime = toNTPTime(s.clock.Now())
+	return resp.marshal()
+}
+
+func clientKey(addr net.Addr) string {
+	if udp, ok := addr.(*net.UDPAddr); ok {
+		return udp.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
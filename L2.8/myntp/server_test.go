@@ -0,0 +1,140 @@
+package myntp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/beevik/ntp"
+)
+
+type shiftedClock struct {
+	shift time.Duration
+}
+
+func (c shiftedClock) Now() time.Time { return time.Now().Add(c.shift) }
+
+func (c shiftedClock) Status() ClockStatus {
+	return ClockStatus{Stratum: 1, ReferenceID: [4]byte{'T', 'E', 'S', 'T'}, ReferenceTime: c.Now()}
+}
+
+func startServer(t *testing.T, cfg ServerConfig) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = NewServer(cfg).Serve(ctx, pc)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return pc.LocalAddr().String()
+}
+
+func TestServer_LocalClock(t *testing.T) {
+	addr := startServer(t, ServerConfig{})
+
+	resp, err := ntp.QueryWithOptions(addr, ntp.QueryOptions{Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if err := resp.Validate(); err != nil {
+		t.Fatalf("ответ не прошёл проверку: %v", err)
+	}
+	if resp.Stratum != LocalStratum || resp.ReferenceID != 0x4c4f434c { // "LOCL"
+		t.Errorf("stratum=%d refid=%#x", resp.Stratum, resp.ReferenceID)
+	}
+	if resp.ClockOffset > 50*time.Millisecond || resp.ClockOffset < -50*time.Millisecond {
+		t.Errorf("слишком большое смещение от локального сервера: %v", resp.ClockOffset)
+	}
+}
+
+func TestServer_ShiftedClock(t *testing.T) {
+	addr := startServer(t, ServerConfig{Clock: shiftedClock{shift: time.Hour}})
+
+	resp, err := ntp.QueryWithOptions(addr, ntp.QueryOptions{Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if diff := resp.ClockOffset - time.Hour; diff > 50*time.Millisecond || diff < -50*time.Millisecond {
+		t.Errorf("ожидали смещение около 1h, получили %v", resp.ClockOffset)
+	}
+}
+
+func TestServer_RateLimitKissOfDeath(t *testing.T) {
+	addr := startServer(t, ServerConfig{RateLimit: 0.001, Burst: 1})
+
+	resp, err := ntp.QueryWithOptions(addr, ntp.QueryOptions{Timeout: 2 * time.Second})
+	if err != nil || resp.IsKissOfDeath() {
+		t.Fatalf("первый запрос должен быть обслужен: resp=%+v err=%v", resp, err)
+	}
+
+	resp, err = ntp.QueryWithOptions(addr, ntp.QueryOptions{Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if !resp.IsKissOfDeath() || resp.KissCode != "RATE" {
+		t.Errorf("ожидали kiss-o'-death RATE, получили stratum=%d code=%q", resp.Stratum, resp.KissCode)
+	}
+
+	_, err = ntp.QueryWithOptions(addr, ntp.QueryOptions{Timeout: 200 * time.Millisecond})
+	if err == nil {
+		t.Errorf("повторные запросы после kiss-o'-death должны игнорироваться")
+	}
+}
+
+func TestServer_DisciplinedClockFromUpstream(t *testing.T) {
+	upstream := startServer(t, ServerConfig{Clock: shiftedClock{shift: 2 * time.Second}})
+
+	clock := NewDisciplinedClock(QueryConfig{Servers: []string{upstream}, Timeout: 2 * time.Second})
+	if clock.Status().Leap != ntp.LeapNotInSync {
+		t.Fatalf("до синхронизации часы должны быть несинхронизированы")
+	}
+	if err := clock.Sync(context.Background()); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	addr := startServer(t, ServerConfig{Clock: clock})
+
+	resp, err := ntp.QueryWithOptions(addr, ntp.QueryOptions{Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if err := resp.Validate(); err != nil {
+		t.Fatalf("ответ не прошёл проверку: %v", err)
+	}
+	if resp.Stratum != 2 {
+		t.Errorf("ожидали stratum 2, получили %d", resp.Stratum)
+	}
+	if diff := resp.ClockOffset - 2*time.Second; diff > 50*time.Millisecond || diff < -50*time.Millisecond {
+		t.Errorf("ожидали смещение около 2s, получили %v", resp.ClockOffset)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	l := newRateLimiter(1, 2)
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.allow("a", now); !ok {
+			t.Fatalf("запрос %d должен пройти", i)
+		}
+	}
+	if ok, kiss := l.allow("a", now); ok || !kiss {
+		t.Errorf("третий запрос: ok=%v kiss=%v", ok, kiss)
+	}
+	if ok, kiss := l.allow("a", now); ok || kiss {
+		t.Errorf("kiss-o'-death отправляется один раз: ok=%v kiss=%v", ok, kiss)
+	}
+	if ok, _ := l.allow("b", now); !ok {
+		t.Errorf("лимит должен считаться для каждого клиента отдельно")
+	}
+	if ok, _ := l.allow("a", now.Add(time.Second)); !ok {
+		t.Errorf("через секунду бакет должен пополниться")
+	}
+}
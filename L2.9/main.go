@@ -1,83 +1,50 @@
 package main
 
+// echo "a4bc2d5e" | go run main.go        распаковка (по умолчанию, то же что -d)
+// echo "aaaabccddddde" | go run main.go -e упаковка
+
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"strings"
-	"unicode"
+
+	"L2.9/pkg/rle"
 )
 
-func readString() (string, error) {
-	scanner := bufio.NewScanner(os.Stdin)
-	str := ""
-	if scanner.Scan() {
-		str = scanner.Text()
-	}
-	if err := scanner.Err(); err != nil {
-		return "", err
-	}
-	return str, nil
+func unpackSrting(input string) (string, error) {
+	return rle.Unpack(input)
 }
 
-func writeSequence(sb *strings.Builder, val rune, amount rune) {
-	count := int(amount - '0')
-	for i := 0; i < count-1; i++ {
-		sb.WriteRune(val)
-	}
-}
+func main() {
+	decode := flag.Bool("d", false, "распаковать данные из stdin (режим по умолчанию)")
+	encode := flag.Bool("e", false, "упаковать данные из stdin")
+	flag.Parse()
 
-func unpackSrting(input string) (string, error) {
-	if input == "" {
-		return input, nil
+	if *decode && *encode {
+		fmt.Fprintln(os.Stderr, "флаги -d и -e нельзя использовать одновременно")
+		os.Exit(2)
 	}
-	var sb strings.Builder
-	sb.Grow(len(input))
 
-	escape := false
-	var lastChar rune
-	for _, val := range input {
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
 
-		if val == rune('\\') {
-			escape = true
-			continue
+	if *encode {
+		w := rle.NewPackWriter(out)
+		if _, err := io.Copy(w, os.Stdin); err != nil {
+			log.Panicln("ошибка при упаковке ", err)
 		}
-
-		if escape {
-			sb.WriteRune(val)
-			lastChar = val
-			escape = false
-			continue
-		}
-
-		if unicode.IsDigit(val) && !escape {
-			if lastChar == 0 {
-				return "", fmt.Errorf("неправильный формат введенной строки")
-			}
-			writeSequence(&sb, lastChar, val)
-			continue
-		}
-
-		if !unicode.IsDigit(val) {
-			sb.WriteRune(val)
-			lastChar = val
-			continue
+		if err := w.Close(); err != nil {
+			log.Panicln("ошибка при упаковке ", err)
 		}
+		return
 	}
-	return sb.String(), nil
 
-}
-
-func main() {
-	input, err := readString()
-	if err != nil {
-		log.Panicln("ошибка при считывании строки ", err)
-	}
-	unpackedString, err := unpackSrting(input)
-	if err != nil {
+	if _, err := io.Copy(out, rle.NewUnpackReader(os.Stdin)); err != nil {
+		out.Flush()
 		log.Println(err)
+		os.Exit(1)
 	}
-	fmt.Println(unpackedString)
-
 }
@@ -0,0 +1,99 @@
+package rle
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pack упаковывает строку, обратная операция к Unpack: "aaaabccddddde" -> "a4bc2d5e".
+// Цифры и '\' экранируются, поэтому Unpack(Pack(s)) == s для любой строки в UTF-8
+func Pack(input string) string {
+	var sb strings.Builder
+	w := NewPackWriter(&sb)
+	_, _ = io.WriteString(w, input)
+	_ = w.Close()
+	return sb.String()
+}
+
+// packWriter упаковывает данные по мере записи
+type packWriter struct {
+	dst io.Writer
+
+	char    rune
+	count   int
+	partial []byte // незавершённая UTF-8 последовательность с прошлой записи
+}
+
+// NewPackWriter возвращает io.WriteCloser, который упаковывает записанные данные в w.
+// Последняя серия дописывается в Close
+func NewPackWriter(w io.Writer) io.WriteCloser {
+	return &packWriter{dst: w}
+}
+
+func (p *packWriter) Write(data []byte) (int, error) {
+	total := len(data)
+	if len(p.partial) > 0 {
+		data = append(p.partial, data...)
+		p.partial = nil
+	}
+
+	var sb strings.Builder
+	for len(data) > 0 {
+		if !utf8.FullRune(data) {
+			p.partial = append([]byte(nil), data...)
+			break
+		}
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+		if p.count > 0 && r == p.char {
+			p.count++
+			continue
+		}
+		p.flushRun(&sb)
+		p.char = r
+		p.count = 1
+	}
+
+	if sb.Len() > 0 {
+		if _, err := io.WriteString(p.dst, sb.String()); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// Close дописывает последнюю серию
+func (p *packWriter) Close() error {
+	var sb strings.Builder
+	p.flushRun(&sb)
+	if len(p.partial) > 0 {
+		writeRun(&sb, utf8.RuneError, len(p.partial))
+		p.partial = nil
+	}
+	if sb.Len() == 0 {
+		return nil
+	}
+	_, err := io.WriteString(p.dst, sb.String())
+	return err
+}
+
+func (p *packWriter) flushRun(sb *strings.Builder) {
+	if p.count == 0 {
+		return
+	}
+	writeRun(sb, p.char, p.count)
+	p.count = 0
+}
+
+func writeRun(sb *strings.Builder, char rune, count int) {
+	if char == '\\' || unicode.IsDigit(char) {
+		sb.WriteRune('\\')
+	}
+	sb.WriteRune(char)
+	if count > 1 {
+		sb.WriteString(strconv.Itoa(count))
+	}
+}
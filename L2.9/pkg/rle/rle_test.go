@@ -0,0 +1,164 @@
+package rle_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"L2.9/pkg/rle"
+)
+
+func TestUnpack(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "пример из задания", input: "a4bc2d5e", want: "aaaabccddddde"},
+		{name: "нет цифр", input: "abcd", want: "abcd"},
+		{name: "только цифры", input: "45", wantErr: true},
+		{name: "пустая строка", input: "", want: ""},
+		{name: "экранированные цифры", input: "qwe\\4\\5", want: "qwe45"},
+		{name: "экранированная цифра с повторением", input: "qwe\\45", want: "qwe44444"},
+		{name: "экранированный слэш", input: "a\\\\3", want: "a\\\\\\"},
+		{name: "многозначное число", input: "a12b", want: "aaaaaaaaaaaab"},
+		{name: "кириллица", input: "я3ё2", want: "яяяёё"},
+		{name: "ноль повторений", input: "a0", wantErr: true},
+		{name: "незавершённое экранирование", input: "abc\\", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rle.Unpack(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v, wantErr=%v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("input=%q got=%q, want=%q", tt.input, got, tt.want)
+			}
+			if err != nil && !errors.Is(err, rle.ErrFormat) {
+				t.Errorf("ожидали ErrFormat, получили %v", err)
+			}
+		})
+	}
+}
+
+func TestPack(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "", want: ""},
+		{input: "aaaabccddddde", want: "a4bc2d5e"},
+		{input: "qwe45", want: "qwe\\4\\5"},
+		{input: "qwe44444", want: "qwe\\45"},
+		{input: "aaaaaaaaaaaab", want: "a12b"},
+		{input: "\\\\", want: "\\\\2"},
+		{input: "ёёё", want: "ё3"},
+	}
+
+	for _, tt := range tests {
+		if got := rle.Pack(tt.input); got != tt.want {
+			t.Errorf("input=%q got=%q, want=%q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPackUnpack_RoundTrip(t *testing.T) {
+	property := func(s string) bool {
+		got, err := rle.Unpack(rle.Pack(s))
+		return err == nil && got == s
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPackUnpack_RoundTripRuns(t *testing.T) {
+	// quick генерирует строки почти без повторов, поэтому отдельно проверяем длинные серии
+	property := func(chars []rune, counts []uint8) bool {
+		var sb strings.Builder
+		for i, r := range chars {
+			n := 1
+			if i < len(counts) {
+				n += int(counts[i])
+			}
+			sb.WriteString(strings.Repeat(string(r), n))
+		}
+		s := sb.String()
+		got, err := rle.Unpack(rle.Pack(s))
+		return err == nil && got == s
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPack_NeverLonger(t *testing.T) {
+	// упакованная строка не длиннее исходной вдвое (худший случай — одни цифры)
+	property := func(s string) bool {
+		return len(rle.Pack(s)) <= 2*len(s)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// oneByteReader отдаёт данные по одному байту, чтобы проверить разрезанные UTF-8 последовательности
+type oneByteReader struct {
+	r io.Reader
+}
+
+func (o oneByteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return o.r.Read(p[:1])
+}
+
+func TestStreaming_SplitInput(t *testing.T) {
+	input := "ааааbbb\\44\\\\ёё" + strings.Repeat("x", 1000)
+
+	var packed bytes.Buffer
+	w := rle.NewPackWriter(&packed)
+	if _, err := io.Copy(w, oneByteReader{strings.NewReader(input)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if packed.String() != rle.Pack(input) {
+		t.Errorf("потоковая упаковка: got=%q want=%q", packed.String(), rle.Pack(input))
+	}
+
+	unpacked, err := io.ReadAll(rle.NewUnpackReader(oneByteReader{&packed}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(unpacked) != input {
+		t.Errorf("потоковая распаковка: got=%q want=%q", unpacked, input)
+	}
+}
+
+func TestUnpackReader_SmallBuffer(t *testing.T) {
+	r := rle.NewUnpackReader(strings.NewReader("ж3"))
+	buf := make([]byte, 3)
+	var out []byte
+	for {
+		n, err := r.Read(buf)
+		out = append(out, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if string(out) != "жжж" {
+		t.Errorf("got=%q", out)
+	}
+}
@@ -0,0 +1,139 @@
+package rle
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrFormat возвращается, если строка не соответствует формату
+var ErrFormat = errors.New("неправильный формат введенной строки")
+
+// maxCount ограничение на число повторений одного символа
+const maxCount = 1 << 30
+
+// Unpack распаковывает строку вида "a4bc2d5e" в "aaaabccddddde".
+// Символ после '\' берётся как есть, поэтому цифры можно экранировать: "qwe\4\5" -> "qwe45".
+// Число повторений может состоять из нескольких цифр: "a12" -> двенадцать 'a'
+func Unpack(input string) (string, error) {
+	if input == "" {
+		return input, nil
+	}
+	var sb strings.Builder
+	sb.Grow(len(input))
+	if _, err := io.Copy(&sb, NewUnpackReader(strings.NewReader(input))); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// unpackReader распаковывает данные по мере чтения
+type unpackReader struct {
+	src *bufio.Reader
+
+	char      rune // текущий символ
+	remaining int  // сколько раз его ещё нужно вывести
+	encoded   [utf8.UTFMax]byte
+	err       error
+}
+
+// NewUnpackReader возвращает io.Reader, который распаковывает данные из r.
+// Длинные серии выдаются частями, поэтому не требуют памяти под всю строку
+func NewUnpackReader(r io.Reader) io.Reader {
+	return &unpackReader{src: bufio.NewReader(r)}
+}
+
+func (u *unpackReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if u.remaining == 0 {
+			if u.err != nil {
+				break
+			}
+			u.err = u.next()
+			continue
+		}
+		size := utf8.EncodeRune(u.encoded[:], u.char)
+		if len(p)-n < size {
+			break
+		}
+		for u.remaining > 0 && len(p)-n >= size {
+			copy(p[n:], u.encoded[:size])
+			n += size
+			u.remaining--
+		}
+	}
+	if n == 0 && u.remaining == 0 && u.err != nil {
+		return 0, u.err
+	}
+	if n == 0 && len(p) > 0 {
+		// буфер меньше одного символа
+		return 0, io.ErrShortBuffer
+	}
+	return n, nil
+}
+
+// next читает очередной символ и число его повторений
+func (u *unpackReader) next() error {
+	r, _, err := u.src.ReadRune()
+	if err != nil {
+		return err
+	}
+	if r == '\\' {
+		r, _, err = u.src.ReadRune()
+		if err == io.EOF {
+			return ErrFormat
+		}
+		if err != nil {
+			return err
+		}
+	} else if unicode.IsDigit(r) {
+		return ErrFormat
+	}
+
+	count, err := u.readCount()
+	if err != nil {
+		return err
+	}
+	u.char = r
+	u.remaining = count
+	return nil
+}
+
+// readCount читает число после символа; если цифр нет, возвращает 1
+func (u *unpackReader) readCount() (int, error) {
+	count := 0
+	digits := 0
+	for {
+		r, _, err := u.src.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if !isCountDigit(r) {
+			_ = u.src.UnreadRune()
+			break
+		}
+		count = count*10 + int(r-'0')
+		digits++
+		if count > maxCount {
+			return 0, ErrFormat
+		}
+	}
+	if digits == 0 {
+		return 1, nil
+	}
+	if count == 0 {
+		return 0, ErrFormat
+	}
+	return count, nil
+}
+
+func isCountDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 
+	"L3.1/internal/config"
 	"L3.1/internal/server"
 )
 
@@ -15,7 +16,12 @@ func main() {
 		log.Fatalf("Не удалось инициализировать сервисы: %v", err)
 	}
 
+	adminConfig, err := config.LoadAdminConfig("../../environment/.env")
+	if err != nil {
+		log.Fatalf("Не удалось загрузить конфиг администратора: %v", err)
+	}
+
 	// Создаем и запускаем сервер
-	srv := server.NewServer(":8080", notificationService)
+	srv := server.NewServer(":8080", notificationService, adminConfig.Token)
 	srv.Start()
 }
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+// minAdminTokenLength короткий токен легко подобрать
+const minAdminTokenLength = 16
+
+// AdminConfig доступ к административным ручкам (/admin/...)
+type AdminConfig struct {
+	// Token секрет для заголовка Authorization: Bearer <token>; пустой — административные ручки выключены
+	Token string
+}
+
+// LoadAdminConfig грузит ADMIN_TOKEN из .env файла
+func LoadAdminConfig(path string) (*AdminConfig, error) {
+	if path == "" {
+		return nil, fmt.Errorf("укажите путь до env файла")
+	}
+	err := godotenv.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при загрузке env файла %v", err)
+	}
+
+	token := os.Getenv("ADMIN_TOKEN")
+	if token != "" && len(token) < minAdminTokenLength {
+		return nil, fmt.Errorf("ADMIN_TOKEN должен быть не короче %d символов", minAdminTokenLength)
+	}
+	return &AdminConfig{Token: token}, nil
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// режимы шифрования соединения с SMTP-сервером
+const (
+	SMTPTLSNone     = "none"
+	SMTPTLSStartTLS = "starttls"
+	SMTPTLSImplicit = "tls"
+)
+
+// SMTPConfig параметры SMTP-сервера, через который уходят письма
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// TLS режим шифрования: none, starttls или tls (сразу TLS, обычно порт 465)
+	TLS string
+}
+
+// WebhookConfig адрес, на который отправляются уведомления, и секрет для подписи
+type WebhookConfig struct {
+	URL     string
+	Secret  string
+	Timeout time.Duration
+}
+
+// TelegramConfig токен бота, от имени которого отправляются сообщения
+type TelegramConfig struct {
+	Token  string
+	APIURL string
+}
+
+// NotifierConfig настройки каналов доставки; не настроенный канал равен nil
+type NotifierConfig struct {
+	DefaultChannel string
+	SMTP           *SMTPConfig
+	Webhook        *WebhookConfig
+	Telegram       *TelegramConfig
+}
+
+// LoadNotifierConfig грузит настройки каналов доставки из .env файла.
+// Канал включается, если для него заданы обязательные переменные, должен быть включён хотя бы один
+func LoadNotifierConfig(path string) (*NotifierConfig, error) {
+	if path == "" {
+		return nil, fmt.Errorf("укажите путь до env файла")
+	}
+	err := godotenv.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при загрузке env файла %v", err)
+	}
+
+	cfg := &NotifierConfig{DefaultChannel: getEnv("NOTIFY_DEFAULT_CHANNEL", "email")}
+
+	if cfg.SMTP, err = loadSMTPConfig(); err != nil {
+		return nil, err
+	}
+	if url := os.Getenv("WEBHOOK_URL"); url != "" {
+		timeout, err := time.ParseDuration(getEnv("WEBHOOK_TIMEOUT", "10s"))
+		if err != nil {
+			return nil, fmt.Errorf("некорректный WEBHOOK_TIMEOUT: %w", err)
+		}
+		cfg.Webhook = &WebhookConfig{URL: url, Secret: os.Getenv("WEBHOOK_SECRET"), Timeout: timeout}
+	}
+	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
+		cfg.Telegram = &TelegramConfig{Token: token, APIURL: getEnv("TELEGRAM_API_URL", "https://api.telegram.org")}
+	}
+
+	if cfg.SMTP == nil && cfg.Webhook == nil && cfg.Telegram == nil {
+		return nil, fmt.Errorf("не настроен ни один канал доставки")
+	}
+	return cfg, nil
+}
+
+// loadSMTPConfig читает SMTP_*; для совместимости без SMTP_HOST используются
+// GMAIL_SERVICE_FROM и GMAIL_SERVICE_PASSWORD с сервером smtp.gmail.com
+func loadSMTPConfig() (*SMTPConfig, error) {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		from, password := os.Getenv("GMAIL_SERVICE_FROM"), os.Getenv("GMAIL_SERVICE_PASSWORD")
+		if from == "" || password == "" {
+			return nil, nil
+		}
+		return &SMTPConfig{Host: "smtp.gmail.com", Port: 587, Username: from, Password: password, From: from, TLS: SMTPTLSStartTLS}, nil
+	}
+
+	port, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil {
+		return nil, fmt.Errorf("некорректный SMTP_PORT: %w", err)
+	}
+	cfg := &SMTPConfig{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     getEnv("SMTP_FROM", os.Getenv("SMTP_USERNAME")),
+		TLS:      getEnv("SMTP_TLS", SMTPTLSStartTLS),
+	}
+	switch cfg.TLS {
+	case SMTPTLSNone, SMTPTLSStartTLS, SMTPTLSImplicit:
+	default:
+		return nil, fmt.Errorf("SMTP_TLS должен быть none, starttls или tls")
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("не указан адрес отправителя SMTP_FROM")
+	}
+	return cfg, nil
+}
+
+// getEnv возвращает значение переменной окружения или def, если она не задана
+func getEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
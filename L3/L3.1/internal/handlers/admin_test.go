@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminAuth(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{"верный токен", "secret-admin-token", "Bearer secret-admin-token", http.StatusOK},
+		{"без заголовка", "secret-admin-token", "", http.StatusUnauthorized},
+		{"неверный токен", "secret-admin-token", "Bearer wrong", http.StatusUnauthorized},
+		{"без Bearer", "secret-admin-token", "secret-admin-token", http.StatusUnauthorized},
+		{"токен не настроен", "", "Bearer ", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/admin/dlq", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		AdminAuth(tt.token)(ok).ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: код %d, ожидался %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"L3.1/internal/models"
+	"L3.1/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultDeadLetterLimit = 50
+	maxDeadLetterLimit     = 500
+)
+
+// ListDeadLettersHandler — GET /admin/dlq?offset=0&limit=50, сначала новые
+func (dh *DefaultHandler) ListDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		http.Error(w, "offset должен быть неотрицательным числом", http.StatusBadRequest)
+		return
+	}
+	limit, err := queryInt(r, "limit", defaultDeadLetterLimit)
+	if err != nil || limit <= 0 || limit > maxDeadLetterLimit {
+		http.Error(w, fmt.Sprintf("limit должен быть от 1 до %d", maxDeadLetterLimit), http.StatusBadRequest)
+		return
+	}
+
+	entries, total, err := dh.svc.DeadLetters(r.Context(), offset, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if entries == nil {
+		entries = []*models.DeadLetter{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"items": entries, "total": total})
+}
+
+// GetDeadLetterHandler — GET /admin/dlq/{id}, запись DLQ вместе с историей статусов
+func (dh *DefaultHandler) GetDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	entry, err := dh.svc.DeadLetter(r.Context(), id)
+	if err != nil {
+		deadLetterError(w, err)
+		return
+	}
+	// истории может не быть, если запись статуса истекла; запись DLQ всё равно отдаём
+	status, _ := dh.svc.GetStatus(r.Context(), id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"deadLetter": entry, "status": status})
+}
+
+// ReplayDeadLetterHandler — POST /admin/dlq/{id}/replay, отправляет сообщение заново
+func (dh *DefaultHandler) ReplayDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	if err := dh.svc.ReplayDeadLetter(r.Context(), chi.URLParam(r, "id")); err != nil {
+		deadLetterError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, `{"status":"%s"}`, models.StatusReplayed)
+}
+
+func deadLetterError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrDeadLetterNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// queryInt — целый query-параметр, def если параметра нет
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
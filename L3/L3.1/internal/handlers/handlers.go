@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -14,6 +15,13 @@ type Handlers interface {
 	CreateNotificationHandler(w http.ResponseWriter, r *http.Request)
 	GetStatusHandler(w http.ResponseWriter, r *http.Request)
 	CancelNotificationHandler(w http.ResponseWriter, r *http.Request)
+	SaveTemplateHandler(w http.ResponseWriter, r *http.Request)
+	GetTemplateHandler(w http.ResponseWriter, r *http.Request)
+	DeleteTemplateHandler(w http.ResponseWriter, r *http.Request)
+	PreviewTemplateHandler(w http.ResponseWriter, r *http.Request)
+	ListDeadLettersHandler(w http.ResponseWriter, r *http.Request)
+	GetDeadLetterHandler(w http.ResponseWriter, r *http.Request)
+	ReplayDeadLetterHandler(w http.ResponseWriter, r *http.Request)
 }
 
 // DefaultHandler — основная реализация HTTP-хендлеров
@@ -26,7 +34,7 @@ func NewDefaultHandler(svc *service.NotificationService) *DefaultHandler {
 	return &DefaultHandler{svc: svc}
 }
 
-// CreateNotificationHandler — POST /notify
+// CreateNotificationHandler — POST /notify, необязательный заголовок Idempotency-Key
 func (dh *DefaultHandler) CreateNotificationHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "метод не поддерживается", http.StatusMethodNotAllowed)
@@ -39,15 +47,35 @@ func (dh *DefaultHandler) CreateNotificationHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	id, err := dh.svc.CreateNotification(r.Context(), req.To, req.Subject, req.Body, req.SendAt)
-	if err != nil {
+	req.IdempotencyKey = r.Header.Get("Idempotency-Key")
+
+	result, err := dh.svc.CreateNotification(r.Context(), req)
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, service.ErrIdempotencyConflict):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	case errors.Is(err, service.ErrIdempotencyInProgress):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
 		http.Error(w, fmt.Sprintf("ошибка при создании уведомления: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	fmt.Fprintf(w, `{"id":"%s"}`, id)
+	if result.Replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+	// дубликат ничего не создал, поэтому 200, а не 201
+	if result.Duplicate {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(result)
 }
 
 // GetStatusHandler — GET /notify/status?id=...
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"L3.1/internal/models"
+	"L3.1/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// SaveTemplateHandler — PUT /templates/{name}, тело {"locale":"ru","subject":"...","text":"...","html":"..."}
+func (dh *DefaultHandler) SaveTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	var template models.Template
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		http.Error(w, "ошибка при разборе JSON", http.StatusBadRequest)
+		return
+	}
+	template.Name = chi.URLParam(r, "name")
+
+	if err := dh.svc.SaveTemplate(r.Context(), &template); err != nil {
+		templateError(w, "ошибка при сохранении шаблона", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+// GetTemplateHandler — GET /templates/{name}, все языковые версии шаблона
+func (dh *DefaultHandler) GetTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	templates, err := dh.svc.Templates(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		templateError(w, "ошибка при получении шаблона", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+// DeleteTemplateHandler — DELETE /templates/{name}?locale=..., без locale удаляет все версии
+func (dh *DefaultHandler) DeleteTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	if err := dh.svc.DeleteTemplate(r.Context(), chi.URLParam(r, "name"), r.URL.Query().Get("locale")); err != nil {
+		templateError(w, "ошибка при удалении шаблона", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, `{"status":"deleted"}`)
+}
+
+// PreviewTemplateHandler — POST /templates/{name}/preview, тело {"locale":"en","vars":{"name":"Иван"}}.
+// Возвращает тему, текст и HTML так, как они уйдут получателю
+func (dh *DefaultHandler) PreviewTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Locale string         `json:"locale"`
+		Vars   map[string]any `json:"vars"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "ошибка при разборе JSON", http.StatusBadRequest)
+		return
+	}
+
+	rendered, err := dh.svc.RenderTemplate(r.Context(), chi.URLParam(r, "name"), req.Locale, req.Vars)
+	if err != nil {
+		templateError(w, "ошибка при подстановке шаблона", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rendered)
+}
+
+// templateError — отвечает 400 на некорректный шаблон, 404 на отсутствующий
+func templateError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrTemplateNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", prefix, err), http.StatusInternalServerError)
+	}
+}
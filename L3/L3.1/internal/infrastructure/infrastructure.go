@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"context"
+	"time"
 
 	"L3.1/internal/models"
 )
@@ -20,6 +21,36 @@ type QueueMQClient interface {
 	Publish(ctx context.Context, queueName string, message *models.RabbitMQMessage) error
 	Consume(ctx context.Context, queueName string, handler func(msg *models.RabbitMQMessage) error) error
 	ConsumeSingleMessage(ctx context.Context, queueName string) (*models.RabbitMQMessage, error)
-	RetryMessage(ctx context.Context, queueName string, message *models.RabbitMQMessage, delaySecons int) error
 	QueueLength(ctx context.Context, queueName string) (int, error)
 }
+
+// ScheduleClient интерфейс для расписания отложенных сообщений
+type ScheduleClient interface {
+	Schedule(ctx context.Context, message *models.RabbitMQMessage, at time.Time) error
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*models.RabbitMQMessage, error)
+	Ack(ctx context.Context, id string) error
+	Unschedule(ctx context.Context, id string) error
+	NextDue(ctx context.Context) (time.Time, bool, error)
+}
+
+// TemplateStore интерфейс для хранения шаблонов уведомлений
+type TemplateStore interface {
+	SaveTemplate(ctx context.Context, template *models.Template) error
+	Templates(ctx context.Context, name string) (map[string]*models.Template, error)
+	DeleteTemplate(ctx context.Context, name, locale string) (bool, error)
+}
+
+// KeyReserver интерфейс для ключей идемпотентности и дедупликации с TTL
+type KeyReserver interface {
+	Reserve(ctx context.Context, key, value string, ttl time.Duration) (string, bool, error)
+	Put(ctx context.Context, key, value string, ttl time.Duration) error
+	Release(ctx context.Context, key string) error
+}
+
+// DeadLetterStore интерфейс для DLQ — сообщений, которые не удалось отправить
+type DeadLetterStore interface {
+	AddDeadLetter(ctx context.Context, entry *models.DeadLetter) error
+	DeadLetters(ctx context.Context, offset, limit int) ([]*models.DeadLetter, int, error)
+	DeadLetter(ctx context.Context, id string) (*models.DeadLetter, error)
+	RemoveDeadLetter(ctx context.Context, id string) (bool, error)
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"time"
 
 	"github.com/rabbitmq/amqp091-go"
 
@@ -149,16 +148,6 @@ func (rc *RabbitMQClient) ConsumeSingleMessage(ctx context.Context, queueName st
 	return &m, nil
 }
 
-// RetryMessage — повторная отправка сообщения через delay.
-func (rc *RabbitMQClient) RetryMessage(ctx context.Context, queueName string, message *models.RabbitMQMessage, delaySeconds int) error {
-	if message == nil {
-		return fmt.Errorf("message is nil")
-	}
-	time.Sleep(time.Duration(delaySeconds) * time.Second)
-	message.RetryCount++
-	return rc.Publish(ctx, queueName, message)
-}
-
 // QueueLength — возвращает количество сообщений в очереди.
 func (rc *RabbitMQClient) QueueLength(ctx context.Context, queueName string) (int, error) {
 	q, err := rc.channel.QueueInspect(queueName)
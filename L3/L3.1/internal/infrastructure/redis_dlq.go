@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"L3.1/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// ключи DLQ в Redis
+const (
+	// deadLetterKey sorted set: id сообщения -> время попадания в DLQ в миллисекундах
+	deadLetterKey = "notify:dlq"
+	// deadLetterEntriesKey hash: id сообщения -> models.DeadLetter в JSON
+	deadLetterEntriesKey = "notify:dlq:entries"
+)
+
+// AddDeadLetter — кладёт сообщение в DLQ, повторное добавление с тем же ID заменяет запись
+func (rc *RedisClient) AddDeadLetter(ctx context.Context, entry *models.DeadLetter) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = rc.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, deadLetterEntriesKey, entry.Message.ID, data)
+		pipe.ZAdd(ctx, deadLetterKey, redis.Z{Score: float64(entry.FailedAt.UnixMilli()), Member: entry.Message.ID})
+		return nil
+	})
+	return err
+}
+
+// DeadLetters — возвращает страницу DLQ, сначала новые, и общее число записей
+func (rc *RedisClient) DeadLetters(ctx context.Context, offset, limit int) ([]*models.DeadLetter, int, error) {
+	total, err := rc.client.ZCard(ctx, deadLetterKey).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	ids, err := rc.client.ZRevRange(ctx, deadLetterKey, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(ids) == 0 {
+		return nil, int(total), nil
+	}
+
+	values, err := rc.client.HMGet(ctx, deadLetterEntriesKey, ids...).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	entries := make([]*models.DeadLetter, 0, len(values))
+	for _, value := range values {
+		// запись могли удалить между ZREVRANGE и HMGET
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var entry models.DeadLetter
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			return nil, 0, fmt.Errorf("не удалось распаковать запись DLQ: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, int(total), nil
+}
+
+// DeadLetter — возвращает запись DLQ, nil если её нет
+func (rc *RedisClient) DeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	data, err := rc.client.HGet(ctx, deadLetterEntriesKey, id).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry models.DeadLetter
+	if err := json.Unmarshal([]byte(data), &entry); err != nil {
+		return nil, fmt.Errorf("не удалось распаковать запись DLQ: %w", err)
+	}
+	return &entry, nil
+}
+
+// RemoveDeadLetter — удаляет запись из DLQ, false если её не было
+func (rc *RedisClient) RemoveDeadLetter(ctx context.Context, id string) (bool, error) {
+	var removed *redis.IntCmd
+	_, err := rc.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, deadLetterKey, id)
+		removed = pipe.HDel(ctx, deadLetterEntriesKey, id)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return removed.Val() > 0, nil
+}
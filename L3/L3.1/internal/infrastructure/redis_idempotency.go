@@ -0,0 +1,42 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// reserveScript записывает значение, если ключа ещё нет, иначе возвращает текущее значение
+var reserveScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current then
+	return current
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return false
+`)
+
+// Reserve — атомарно записывает value под key на ttl, если ключа ещё нет. Если ключ уже занят,
+// возвращает его значение и false
+func (rc *RedisClient) Reserve(ctx context.Context, key, value string, ttl time.Duration) (string, bool, error) {
+	current, err := reserveScript.Run(ctx, rc.client, []string{key}, value, ttl.Milliseconds()).Text()
+	if errors.Is(err, redis.Nil) {
+		return "", true, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return current, false, nil
+}
+
+// Put — перезаписывает значение ключа с новым ttl
+func (rc *RedisClient) Put(ctx context.Context, key, value string, ttl time.Duration) error {
+	return rc.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Release — освобождает ключ
+func (rc *RedisClient) Release(ctx context.Context, key string) error {
+	return rc.client.Del(ctx, key).Err()
+}
@@ -0,0 +1,125 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"L3.1/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// ключи расписания в Redis
+const (
+	// scheduleKey sorted set: id сообщения -> время отправки в миллисекундах
+	scheduleKey = "notify:schedule"
+	// inflightKey sorted set: id сообщения -> момент окончания аренды; сообщения, которые
+	// забрал планировщик, но ещё не переложил в очередь
+	inflightKey = "notify:inflight"
+	// payloadKey hash: id сообщения -> сообщение в JSON
+	payloadKey = "notify:payload"
+)
+
+// claimScript возвращает в расписание сообщения с истёкшей арендой (планировщик упал, не успев
+// их переложить), затем атомарно забирает подошедшие сообщения в inflight с новой арендой
+var claimScript = redis.NewScript(`
+local expired = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1])
+for _, id in ipairs(expired) do
+	redis.call('ZREM', KEYS[2], id)
+	redis.call('ZADD', KEYS[1], ARGV[1], id)
+end
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+local out = {}
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	local payload = redis.call('HGET', KEYS[3], id)
+	if payload then
+		redis.call('ZADD', KEYS[2], ARGV[3], id)
+		table.insert(out, id)
+		table.insert(out, payload)
+	end
+end
+return out
+`)
+
+// ackScript снимает аренду. Полезная нагрузка удаляется, только если сообщение не вернулось
+// в расписание: воркер мог успеть получить его из очереди и запланировать повтор до Ack
+var ackScript = redis.NewScript(`
+redis.call('ZREM', KEYS[2], ARGV[1])
+if not redis.call('ZSCORE', KEYS[1], ARGV[1]) then
+	redis.call('HDEL', KEYS[3], ARGV[1])
+end
+return 1
+`)
+
+// Schedule — сохраняет сообщение и ставит его на отправку в момент at.
+// Повторный вызов с тем же ID переносит сообщение
+func (rc *RedisClient) Schedule(ctx context.Context, message *models.RabbitMQMessage, at time.Time) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = rc.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, payloadKey, message.ID, data)
+		pipe.ZRem(ctx, inflightKey, message.ID)
+		pipe.ZAdd(ctx, scheduleKey, redis.Z{Score: float64(at.UnixMilli()), Member: message.ID})
+		return nil
+	})
+	return err
+}
+
+// ClaimDue — забирает до limit сообщений, время которых наступило к now. Сообщение остаётся
+// в Redis, пока его не подтвердят через Ack; без подтверждения через lease оно снова попадёт в выдачу.
+// Сообщение, которое не удаётся распаковать, удаляется из расписания, чтобы не блокировать остальные
+func (rc *RedisClient) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*models.RabbitMQMessage, error) {
+	res, err := claimScript.Run(ctx, rc.client, []string{scheduleKey, inflightKey, payloadKey},
+		now.UnixMilli(), limit, now.Add(lease).UnixMilli()).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*models.RabbitMQMessage, 0, len(res)/2)
+	for i := 0; i+1 < len(res); i += 2 {
+		id, payload := res[i], res[i+1]
+		var msg models.RabbitMQMessage
+		if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+			log.Printf("[Redis] Запланированное сообщение %s повреждено и удалено: %v; данные: %q", id, err, payload)
+			if err := rc.Unschedule(ctx, id); err != nil {
+				log.Printf("[Redis] Ошибка удаления повреждённого сообщения %s: %v", id, err)
+			}
+			continue
+		}
+		messages = append(messages, &msg)
+	}
+	return messages, nil
+}
+
+// Ack — подтверждает, что забранное сообщение передано дальше, и снимает аренду. Если сообщение
+// за это время снова поставили в расписание (повтор), оно там остаётся
+func (rc *RedisClient) Ack(ctx context.Context, id string) error {
+	return ackScript.Run(ctx, rc.client, []string{scheduleKey, inflightKey, payloadKey}, id).Err()
+}
+
+// Unschedule — убирает сообщение из расписания, если оно там есть
+func (rc *RedisClient) Unschedule(ctx context.Context, id string) error {
+	_, err := rc.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, scheduleKey, id)
+		pipe.ZRem(ctx, inflightKey, id)
+		pipe.HDel(ctx, payloadKey, id)
+		return nil
+	})
+	return err
+}
+
+// NextDue — возвращает время ближайшего запланированного сообщения; false, если расписание пусто
+func (rc *RedisClient) NextDue(ctx context.Context) (time.Time, bool, error) {
+	res, err := rc.client.ZRangeWithScores(ctx, scheduleKey, 0, 0).Result()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if len(res) == 0 {
+		return time.Time{}, false, nil
+	}
+	return time.UnixMilli(int64(res[0].Score)), true, nil
+}
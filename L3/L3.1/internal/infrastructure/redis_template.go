@@ -0,0 +1,52 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+
+	"L3.1/internal/models"
+)
+
+// templateKeyPrefix hash notify:template:<имя>: язык -> шаблон в JSON
+const templateKeyPrefix = "notify:template:"
+
+// SaveTemplate — сохраняет шаблон, заменяя его версию на том же языке
+func (rc *RedisClient) SaveTemplate(ctx context.Context, template *models.Template) error {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	return rc.client.HSet(ctx, templateKeyPrefix+template.Name, template.Locale, data).Err()
+}
+
+// Templates — возвращает все языковые версии шаблона, пустой map если шаблона нет
+func (rc *RedisClient) Templates(ctx context.Context, name string) (map[string]*models.Template, error) {
+	values, err := rc.client.HGetAll(ctx, templateKeyPrefix+name).Result()
+	if err != nil {
+		return nil, err
+	}
+	templates := make(map[string]*models.Template, len(values))
+	for locale, data := range values {
+		var template models.Template
+		if err := json.Unmarshal([]byte(data), &template); err != nil {
+			return nil, err
+		}
+		templates[locale] = &template
+	}
+	return templates, nil
+}
+
+// DeleteTemplate — удаляет версию шаблона на языке locale, при пустом locale — все версии.
+// Возвращает false, если удалять было нечего
+func (rc *RedisClient) DeleteTemplate(ctx context.Context, name, locale string) (bool, error) {
+	var (
+		removed int64
+		err     error
+	)
+	if locale == "" {
+		removed, err = rc.client.Del(ctx, templateKeyPrefix+name).Result()
+	} else {
+		removed, err = rc.client.HDel(ctx, templateKeyPrefix+name, locale).Result()
+	}
+	return removed > 0, err
+}
@@ -4,25 +4,102 @@ import "time"
 
 // RabbitMQMessage то что будет в redis о сообщении
 type RabbitMQMessage struct {
-	ID         string
-	To         string
-	Subject    string
-	Body       string
+	ID string
+	// Channel канал доставки (email, webhook, telegram), пустой - канал по умолчанию
+	Channel string
+	To      string
+	Subject string
+	Body    string
+	// HTMLBody HTML-версия письма, пустая - только текст
+	HTMLBody   string
 	SendAt     time.Time
 	RetryCount int
 }
 
+// статусы уведомления
+const (
+	StatusScheduled = "scheduled"
+	StatusSending   = "sending"
+	StatusRetry     = "retry"
+	StatusSent      = "sent"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+	// StatusReplayed уведомление вручную возвращено из DLQ на отправку
+	StatusReplayed = "replayed"
+)
+
 // RedisMessage то что будет в redis о сообщении
 type RedisMessage struct {
 	ID        string
 	Status    string
 	UpdatedAt time.Time
+	// LastError текст последней ошибки отправки
+	LastError string
+	// History все смены статуса, от старых к новым
+	History []StatusEvent
+}
+
+// StatusEvent смена статуса уведомления
+type StatusEvent struct {
+	Status string
+	// Attempt номер попытки для sending, sent и failed, номер повтора для retry
+	Attempt int
+	Error   string
+	At      time.Time
+}
+
+// DeadLetter сообщение, которое не удалось отправить за все попытки
+type DeadLetter struct {
+	Message   *RabbitMQMessage
+	LastError string
+	FailedAt  time.Time
 }
 
 // CreateNotificationRequest модель запроса на создание уведомления
 type CreateNotificationRequest struct {
+	// Channel канал доставки, по умолчанию берётся из конфига
+	Channel string `json:"channel"`
+	// To адрес получателя в формате канала: email, chat_id в Telegram, произвольный идентификатор для webhook
 	To      string    `json:"to"`
 	Subject string    `json:"subject"`
 	Body    string    `json:"body"`
 	SendAt  time.Time `json:"sendAt"`
+	// Template имя сохранённого шаблона, вместо subject и body
+	Template string `json:"template"`
+	// Locale язык шаблона (ru, en), по умолчанию ru
+	Locale string `json:"locale"`
+	// Vars значения переменных шаблона {{.var}}
+	Vars map[string]any `json:"vars"`
+	// DedupeWindowSeconds если больше нуля, такое же уведомление (получатель, тема, текст),
+	// созданное за последние DedupeWindowSeconds секунд, не создаётся повторно
+	DedupeWindowSeconds int `json:"dedupeWindowSeconds"`
+	// IdempotencyKey значение заголовка Idempotency-Key
+	IdempotencyKey string `json:"-"`
+}
+
+// CreateNotificationResult ответ на создание уведомления
+type CreateNotificationResult struct {
+	ID string `json:"id"`
+	// Duplicate уведомление не создано, ID указывает на такое же, созданное в окне дедупликации
+	Duplicate bool `json:"duplicate,omitempty"`
+	// Replayed ответ взят из сохранённого по Idempotency-Key
+	Replayed bool `json:"-"`
+}
+
+// Template шаблон уведомления на одном языке
+type Template struct {
+	Name    string `json:"name"`
+	Locale  string `json:"locale"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	// HTML необязательная HTML-версия, для email отправляется вместе с текстом
+	HTML string `json:"html,omitempty"`
+}
+
+// RenderedTemplate шаблон с подставленными переменными
+type RenderedTemplate struct {
+	Locale  string `json:"locale"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html,omitempty"`
 }
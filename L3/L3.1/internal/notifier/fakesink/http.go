@@ -0,0 +1,68 @@
+package fakesink
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Request запрос, принятый HTTPSink
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// HTTPSink HTTP-сервер, который запоминает запросы и отвечает заданным кодом и телом
+type HTTPSink struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	requests []Request
+	status   int
+	body     string
+}
+
+// NewHTTPSink запускает HTTPSink на свободном порту localhost, по умолчанию он отвечает 200
+func NewHTTPSink() *HTTPSink {
+	s := &HTTPSink{status: http.StatusOK}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// URL адрес сервера
+func (s *HTTPSink) URL() string {
+	return s.server.URL
+}
+
+// Respond задаёт код и тело следующих ответов
+func (s *HTTPSink) Respond(status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.body = status, body
+}
+
+// Requests возвращает принятые запросы
+func (s *HTTPSink) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Close останавливает сервер
+func (s *HTTPSink) Close() {
+	s.server.Close()
+}
+
+func (s *HTTPSink) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone(), Body: body})
+	status, respBody := s.status, s.body
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	io.WriteString(w, respBody)
+}
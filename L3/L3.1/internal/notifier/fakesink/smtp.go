@@ -0,0 +1,144 @@
+// Package fakesink локальные SMTP- и HTTP-серверы, которые принимают уведомления
+// и запоминают их, чтобы проверять отправителей без внешних сервисов
+package fakesink
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Mail письмо, принятое SMTPSink
+type Mail struct {
+	From string
+	To   []string
+	// Data заголовки и тело письма в том виде, в каком их передал клиент
+	Data string
+}
+
+// SMTPSink минимальный SMTP-сервер без TLS и авторизации
+type SMTPSink struct {
+	listener net.Listener
+	mu       sync.Mutex
+	mails    []Mail
+	wg       sync.WaitGroup
+}
+
+// NewSMTPSink запускает SMTPSink на свободном порту localhost
+func NewSMTPSink() (*SMTPSink, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &SMTPSink{listener: l}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr адрес host:port, на котором слушает сервер
+func (s *SMTPSink) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Mails возвращает принятые письма
+func (s *SMTPSink) Mails() []Mail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Mail(nil), s.mails...)
+}
+
+// Close останавливает сервер
+func (s *SMTPSink) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *SMTPSink) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.session(conn)
+		}()
+	}
+}
+
+// session ведёт SMTP-диалог одного соединения
+func (s *SMTPSink) session(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 fakesink ESMTP")
+	var mail Mail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 fakesink")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			mail = Mail{From: trimAddr(line[len("MAIL FROM:"):])}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			mail.To = append(mail.To, trimAddr(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := readData(r)
+			if err != nil {
+				return
+			}
+			mail.Data = data
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "RSET":
+			mail = Mail{}
+			reply("250 OK")
+		case cmd == "NOOP":
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// readData читает тело письма до строки из одной точки и убирает удвоение точек
+func readData(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		if line == ".\r\n" || line == ".\n" {
+			return b.String(), nil
+		}
+		b.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
+
+func trimAddr(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		s = s[:i]
+	}
+	return strings.Trim(s, "<>")
+}
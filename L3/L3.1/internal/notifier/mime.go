@@ -0,0 +1,103 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"L3.1/internal/models"
+)
+
+// buildMessage собирает письмо в формате MIME. Тема и адреса кодируются в UTF-8 (RFC 2047),
+// тело — quoted-printable. Если у сообщения есть HTMLBody, письмо собирается как
+// multipart/alternative с текстовой версией для клиентов без HTML
+func buildMessage(from, to string, msg *models.RabbitMQMessage, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	writeHeader(&buf, "From", formatAddress(from))
+	writeHeader(&buf, "To", formatAddress(to))
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	writeHeader(&buf, "Date", now.Format(time.RFC1123Z))
+	if msg.ID != "" {
+		writeHeader(&buf, "Message-ID", fmt.Sprintf("<%s@%s>", msg.ID, messageIDDomain(from)))
+	}
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	if msg.HTMLBody == "" {
+		writeHeader(&buf, "Content-Type", "text/plain; charset=utf-8")
+		writeHeader(&buf, "Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, msg.Body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	// по RFC 2046 последней идёт предпочтительная версия
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", msg.Body},
+		{"text/html; charset=utf-8", msg.HTMLBody},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	writeHeader(&buf, "Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": mw.Boundary()}))
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	buf.WriteString(": ")
+	buf.WriteString(value)
+	buf.WriteString("\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qw := quotedprintable.NewWriter(w)
+	if _, err := qw.Write([]byte(s)); err != nil {
+		return err
+	}
+	return qw.Close()
+}
+
+// formatAddress кодирует имя в адресе вида "Имя <addr>", голый адрес оставляет как есть
+func formatAddress(addr string) string {
+	parsed, err := mail.ParseAddress(addr)
+	if err != nil {
+		return addr
+	}
+	return parsed.String()
+}
+
+// messageIDDomain берёт домен для Message-ID из адреса отправителя
+func messageIDDomain(from string) string {
+	if parsed, err := mail.ParseAddress(from); err == nil {
+		from = parsed.Address
+	}
+	if i := strings.LastIndex(from, "@"); i >= 0 && i < len(from)-1 {
+		return from[i+1:]
+	}
+	return "localhost"
+}
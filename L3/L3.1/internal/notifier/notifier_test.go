@@ -0,0 +1,285 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+
+	"L3.1/internal/config"
+	"L3.1/internal/models"
+	"L3.1/internal/notifier"
+	"L3.1/internal/notifier/fakesink"
+)
+
+func testMessage() *models.RabbitMQMessage {
+	return &models.RabbitMQMessage{ID: "42", To: "user@example.com", Subject: "Привет", Body: "Текст уведомления"}
+}
+
+func smtpConfig(t *testing.T, sink *fakesink.SMTPSink, tlsMode string) config.SMTPConfig {
+	t.Helper()
+	host, portStr, _ := net.SplitHostPort(sink.Addr())
+	port, _ := strconv.Atoi(portStr)
+	return config.SMTPConfig{Host: host, Port: port, From: "noreply@example.com", TLS: tlsMode}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	sink, err := fakesink.NewSMTPSink()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	n, err := notifier.NewSMTPNotifier(smtpConfig(t, sink, config.SMTPTLSNone))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("отправка не удалась: %v", err)
+	}
+
+	mails := sink.Mails()
+	if len(mails) != 1 {
+		t.Fatalf("ожидалось одно письмо, получено %d", len(mails))
+	}
+	if mails[0].From != "noreply@example.com" || len(mails[0].To) != 1 || mails[0].To[0] != "user@example.com" {
+		t.Errorf("неверный конверт письма: %+v", mails[0])
+	}
+	parsed := parseMail(t, mails[0].Data)
+	if subject := decodeHeader(t, parsed.Header.Get("Subject")); subject != "Привет" {
+		t.Errorf("тема = %q", subject)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(parsed.Header.Get("Content-Type")); mediaType != "text/plain" {
+		t.Errorf("Content-Type = %q", parsed.Header.Get("Content-Type"))
+	}
+	body, _ := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	// перевод строки в конце добавляет SMTP перед завершающей точкой
+	if strings.TrimRight(string(body), "\r\n") != "Текст уведомления" {
+		t.Errorf("в письме нет текста: %q", body)
+	}
+}
+
+func TestSMTPNotifier_MultipartHTML(t *testing.T) {
+	sink, err := fakesink.NewSMTPSink()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	cfg := smtpConfig(t, sink, config.SMTPTLSNone)
+	cfg.From = "Сервис уведомлений <noreply@example.com>"
+	n, err := notifier.NewSMTPNotifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := testMessage()
+	msg.Subject = "Ваш заказ №15 собран и готов к выдаче в пункте самовывоза"
+	msg.HTMLBody = "<p>Текст <b>уведомления</b></p>"
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatalf("отправка не удалась: %v", err)
+	}
+
+	mails := sink.Mails()
+	if len(mails) != 1 {
+		t.Fatalf("ожидалось одно письмо, получено %d", len(mails))
+	}
+	if mails[0].From != "noreply@example.com" {
+		t.Errorf("в MAIL FROM должен быть только адрес, получено %q", mails[0].From)
+	}
+	for _, line := range strings.Split(mails[0].Data, "\r\n") {
+		for _, r := range line {
+			if r > 127 {
+				t.Fatalf("в письме не-ASCII строка: %q", line)
+			}
+		}
+	}
+
+	parsed := parseMail(t, mails[0].Data)
+	if subject := decodeHeader(t, parsed.Header.Get("Subject")); subject != msg.Subject {
+		t.Errorf("тема = %q", subject)
+	}
+	if from := decodeHeader(t, parsed.Header.Get("From")); from != cfg.From {
+		t.Errorf("From = %q", from)
+	}
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q", parsed.Header.Get("Content-Type"))
+	}
+
+	// multipart.Reader сам декодирует quoted-printable
+	parts := map[string]string{}
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(part)
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[partType] = string(content)
+	}
+	if parts["text/plain"] != msg.Body || parts["text/html"] != msg.HTMLBody {
+		t.Errorf("части письма: %q", parts)
+	}
+}
+
+func parseMail(t *testing.T, data string) *mail.Message {
+	t.Helper()
+	parsed, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("письмо не разбирается: %v", err)
+	}
+	return parsed
+}
+
+func decodeHeader(t *testing.T, value string) string {
+	t.Helper()
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		t.Fatalf("заголовок %q не декодируется: %v", value, err)
+	}
+	return decoded
+}
+
+func TestSMTPNotifier_StartTLSRequired(t *testing.T) {
+	sink, err := fakesink.NewSMTPSink()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	n, err := notifier.NewSMTPNotifier(smtpConfig(t, sink, config.SMTPTLSStartTLS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// сервер не предлагает STARTTLS, и письмо не должно уйти открытым текстом
+	if err := n.Send(context.Background(), testMessage()); err == nil {
+		t.Fatal("ожидалась ошибка без STARTTLS")
+	}
+	if len(sink.Mails()) != 0 {
+		t.Error("письмо не должно быть отправлено")
+	}
+}
+
+func TestWebhookNotifier_Signature(t *testing.T) {
+	sink := fakesink.NewHTTPSink()
+	defer sink.Close()
+
+	n, err := notifier.NewWebhookNotifier(config.WebhookConfig{URL: sink.URL() + "/hook", Secret: "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("отправка не удалась: %v", err)
+	}
+
+	reqs := sink.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("ожидался один запрос, получено %d", len(reqs))
+	}
+	req := reqs[0]
+	if req.Method != http.MethodPost || req.Path != "/hook" {
+		t.Errorf("неверный запрос: %s %s", req.Method, req.Path)
+	}
+	want := "sha256=" + notifier.Sign([]byte("s3cret"), req.Header.Get(notifier.SignatureTimestampHeader), req.Body)
+	if got := req.Header.Get(notifier.SignatureHeader); got != want {
+		t.Errorf("подпись %q, ожидалась %q", got, want)
+	}
+
+	var payload notifier.WebhookPayload
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.ID != "42" || payload.To != "user@example.com" || payload.Subject != "Привет" {
+		t.Errorf("неверное тело: %+v", payload)
+	}
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	sink := fakesink.NewHTTPSink()
+	defer sink.Close()
+	sink.Respond(http.StatusServiceUnavailable, "busy")
+
+	n, err := notifier.NewWebhookNotifier(config.WebhookConfig{URL: sink.URL()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Send(context.Background(), testMessage()); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("ожидалась ошибка с кодом 503, получено %v", err)
+	}
+}
+
+func TestTelegramNotifier(t *testing.T) {
+	sink := fakesink.NewHTTPSink()
+	defer sink.Close()
+	sink.Respond(http.StatusOK, `{"ok":true,"result":{}}`)
+
+	n, err := notifier.NewTelegramNotifier(config.TelegramConfig{Token: "123:abc", APIURL: sink.URL()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := testMessage()
+	msg.To = "-100500"
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatalf("отправка не удалась: %v", err)
+	}
+
+	req := sink.Requests()[0]
+	if req.Path != "/bot123:abc/sendMessage" {
+		t.Errorf("неверный путь %s", req.Path)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["chat_id"] != "-100500" || body["text"] != "Привет\n\nТекст уведомления" {
+		t.Errorf("неверное тело: %v", body)
+	}
+
+	sink.Respond(http.StatusBadRequest, `{"ok":false,"description":"Bad Request: chat not found"}`)
+	if err := n.Send(context.Background(), msg); err == nil || !strings.Contains(err.Error(), "chat not found") {
+		t.Fatalf("ожидалась ошибка Bot API, получено %v", err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	sink := fakesink.NewHTTPSink()
+	defer sink.Close()
+
+	webhook, err := notifier.NewWebhookNotifier(config.WebhookConfig{URL: sink.URL()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := notifier.NewRegistry(notifier.ChannelWebhook)
+	registry.Register(notifier.ChannelWebhook, webhook)
+
+	if ch, err := registry.Resolve(""); err != nil || ch != notifier.ChannelWebhook {
+		t.Errorf("пустой канал должен стать каналом по умолчанию, получено %q, %v", ch, err)
+	}
+	if _, err := registry.Resolve(notifier.ChannelTelegram); err == nil {
+		t.Error("ненастроенный канал должен давать ошибку")
+	}
+
+	if err := registry.Send(context.Background(), testMessage()); err != nil {
+		t.Fatal(err)
+	}
+	msg := testMessage()
+	msg.Channel = notifier.ChannelTelegram
+	if err := registry.Send(context.Background(), msg); err == nil {
+		t.Error("сообщение в ненастроенный канал не должно отправляться")
+	}
+	if n := len(sink.Requests()); n != 1 {
+		t.Errorf("ожидался один запрос к webhook, получено %d", n)
+	}
+}
@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"L3.1/internal/models"
+)
+
+// каналы доставки
+const (
+	ChannelEmail    = "email"
+	ChannelWebhook  = "webhook"
+	ChannelTelegram = "telegram"
+)
+
+// Sender отправляет сообщение по одному каналу
+type Sender interface {
+	Send(ctx context.Context, msg *models.RabbitMQMessage) error
+}
+
+// Registry выбирает отправителя по каналу сообщения
+type Registry struct {
+	senders        map[string]Sender
+	defaultChannel string
+}
+
+// NewRegistry создаёт пустой реестр; сообщения без канала уходят в defaultChannel
+func NewRegistry(defaultChannel string) *Registry {
+	return &Registry{
+		senders:        make(map[string]Sender),
+		defaultChannel: defaultChannel,
+	}
+}
+
+// Register добавляет отправителя для канала, повторная регистрация заменяет прежнего
+func (r *Registry) Register(channel string, sender Sender) {
+	r.senders[channel] = sender
+}
+
+// Resolve возвращает канал, в который уйдёт сообщение с указанным каналом, и ошибку, если он не настроен
+func (r *Registry) Resolve(channel string) (string, error) {
+	if channel == "" {
+		channel = r.defaultChannel
+	}
+	if _, ok := r.senders[channel]; !ok {
+		return "", fmt.Errorf("канал %q не настроен, доступны: %v", channel, r.Channels())
+	}
+	return channel, nil
+}
+
+// Channels возвращает настроенные каналы по алфавиту
+func (r *Registry) Channels() []string {
+	channels := make([]string, 0, len(r.senders))
+	for ch := range r.senders {
+		channels = append(channels, ch)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// Send отправляет сообщение через отправителя его канала
+func (r *Registry) Send(ctx context.Context, msg *models.RabbitMQMessage) error {
+	channel, err := r.Resolve(msg.Channel)
+	if err != nil {
+		return err
+	}
+	return r.senders[channel].Send(ctx, msg)
+}
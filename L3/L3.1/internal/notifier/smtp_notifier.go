@@ -0,0 +1,119 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"L3.1/internal/config"
+	"L3.1/internal/models"
+)
+
+// smtpTimeout ограничение на весь SMTP-диалог, если у контекста нет своего дедлайна
+const smtpTimeout = 30 * time.Second
+
+// SMTPNotifier отправляет письма через любой SMTP-сервер или релей
+type SMTPNotifier struct {
+	cfg config.SMTPConfig
+	// tlsConfig можно подменить, например чтобы доверять самоподписанному сертификату релея
+	tlsConfig *tls.Config
+}
+
+// NewSMTPNotifier создает SMTPNotifier
+func NewSMTPNotifier(cfg config.SMTPConfig) (*SMTPNotifier, error) {
+	if cfg.Host == "" || cfg.Port == 0 || cfg.From == "" {
+		return nil, fmt.Errorf("для SMTP нужны host, port и from")
+	}
+	if cfg.TLS == "" {
+		cfg.TLS = config.SMTPTLSStartTLS
+	}
+	return &SMTPNotifier{
+		cfg:       cfg,
+		tlsConfig: &tls.Config{ServerName: cfg.Host},
+	}, nil
+}
+
+// Send отправляет письмо получателю msg.To
+func (sn *SMTPNotifier) Send(ctx context.Context, msg *models.RabbitMQMessage) error {
+	client, err := sn.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("не удалось подключиться к SMTP: %w", err)
+	}
+	defer client.Close()
+
+	if sn.cfg.TLS == config.SMTPTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP-сервер не поддерживает STARTTLS")
+		}
+		if err := client.StartTLS(sn.tlsConfig); err != nil {
+			return fmt.Errorf("ошибка STARTTLS: %w", err)
+		}
+	}
+	if sn.cfg.Username != "" {
+		auth := smtp.PlainAuth("", sn.cfg.Username, sn.cfg.Password, sn.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("ошибка авторизации SMTP: %w", err)
+		}
+	}
+
+	data, err := buildMessage(sn.cfg.From, msg.To, msg, time.Now())
+	if err != nil {
+		return fmt.Errorf("ошибка при сборке письма: %w", err)
+	}
+	if err := client.Mail(envelopeAddress(sn.cfg.From)); err != nil {
+		return fmt.Errorf("ошибка MAIL FROM: %w", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("ошибка RCPT TO: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("ошибка DATA: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("ошибка при отправке письма: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("ошибка при отправке письма: %w", err)
+	}
+	return client.Quit()
+}
+
+// envelopeAddress адрес для MAIL FROM: из "Имя <addr>" остаётся только addr
+func envelopeAddress(from string) string {
+	if parsed, err := mail.ParseAddress(from); err == nil {
+		return parsed.Address
+	}
+	return from
+}
+
+// dial открывает соединение с учётом контекста: net/smtp сам контекст не поддерживает,
+// поэтому его дедлайн переносится на соединение
+func (sn *SMTPNotifier) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(sn.cfg.Host, strconv.Itoa(sn.cfg.Port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	conn.SetDeadline(deadline)
+
+	if sn.cfg.TLS == config.SMTPTLSImplicit {
+		conn = tls.Client(conn, sn.tlsConfig)
+	}
+	client, err := smtp.NewClient(conn, sn.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
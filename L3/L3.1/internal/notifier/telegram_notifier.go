@@ -0,0 +1,82 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"L3.1/internal/config"
+	"L3.1/internal/models"
+)
+
+// TelegramNotifier отправляет сообщения через Telegram Bot API, msg.To - chat_id или @username канала
+type TelegramNotifier struct {
+	apiURL string
+	token  string
+	client *http.Client
+}
+
+// NewTelegramNotifier создает TelegramNotifier
+func NewTelegramNotifier(cfg config.TelegramConfig) (*TelegramNotifier, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("не указан токен Telegram бота")
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = "https://api.telegram.org"
+	}
+	return &TelegramNotifier{
+		apiURL: strings.TrimRight(cfg.APIURL, "/"),
+		token:  cfg.Token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// telegramResponse общий вид ответа Bot API
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+// Send отправляет тему и текст одним сообщением
+func (tn *TelegramNotifier) Send(ctx context.Context, msg *models.RabbitMQMessage) error {
+	text := msg.Body
+	if msg.Subject != "" {
+		text = msg.Subject + "\n\n" + msg.Body
+	}
+	body, err := json.Marshal(map[string]string{"chat_id": msg.To, "text": text})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации сообщения: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/sendMessage", tn.apiURL, tn.token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса к Telegram: %w", redactToken(err, tn.token))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := tn.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка запроса к Telegram: %w", redactToken(err, tn.token))
+	}
+	defer resp.Body.Close()
+
+	var result telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("telegram ответил %d: некорректный ответ", resp.StatusCode)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram ответил %d: %s", resp.StatusCode, result.Description)
+	}
+	return nil
+}
+
+// redactToken убирает токен бота из текста ошибки: он входит в URL запроса,
+// а ошибки попадают в логи
+func redactToken(err error, token string) error {
+	return fmt.Errorf("%s", strings.ReplaceAll(err.Error(), token, "***"))
+}
@@ -0,0 +1,100 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"L3.1/internal/config"
+	"L3.1/internal/models"
+)
+
+// заголовки, которыми webhook подписывает запрос
+const (
+	SignatureHeader          = "X-Signature"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+)
+
+// WebhookPayload тело запроса, которое получает webhook
+type WebhookPayload struct {
+	ID      string `json:"id"`
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	// HTML HTML-версия из шаблона, если она есть
+	HTML   string    `json:"html,omitempty"`
+	SendAt time.Time `json:"send_at"`
+}
+
+// WebhookNotifier отправляет уведомления POST-запросом с JSON на заданный URL.
+// Если задан секрет, запрос подписывается HMAC-SHA256 от "<timestamp>.<тело>"
+type WebhookNotifier struct {
+	url    string
+	secret []byte
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewWebhookNotifier создает WebhookNotifier
+func NewWebhookNotifier(cfg config.WebhookConfig) (*WebhookNotifier, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("не указан url для webhook")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &WebhookNotifier{
+		url:    cfg.URL,
+		secret: []byte(cfg.Secret),
+		client: &http.Client{Timeout: cfg.Timeout},
+		now:    time.Now,
+	}, nil
+}
+
+// Send отправляет уведомление, любой ответ кроме 2xx считается ошибкой
+func (wn *WebhookNotifier) Send(ctx context.Context, msg *models.RabbitMQMessage) error {
+	body, err := json.Marshal(WebhookPayload{ID: msg.ID, To: msg.To, Subject: msg.Subject, Body: msg.Body, HTML: msg.HTMLBody, SendAt: msg.SendAt})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации webhook: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Notification-ID", msg.ID)
+	if len(wn.secret) > 0 {
+		timestamp := strconv.FormatInt(wn.now().Unix(), 10)
+		req.Header.Set(SignatureTimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, "sha256="+Sign(wn.secret, timestamp, body))
+	}
+
+	resp, err := wn.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook ответил %d: %s", resp.StatusCode, text)
+	}
+	return nil
+}
+
+// Sign считает подпись тела запроса, получатель проверяет её тем же секретом.
+// Метка времени входит в подпись, чтобы перехваченный запрос нельзя было повторить позже
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
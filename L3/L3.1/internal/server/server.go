@@ -39,20 +39,24 @@ func InitNotificationService() (*service.NotificationService, error) {
 		return nil, fmt.Errorf("ошибка подключения к RabbitMQ: %w", err)
 	}
 
-	gmailConfig, err := config.LoadGmaiLConfig("../../environment/.env")
+	notifierConfig, err := config.LoadNotifierConfig("../../environment/.env")
 	if err != nil {
-		return nil, fmt.Errorf("ошибка загрузки конфига Gmail: %w", err)
+		return nil, fmt.Errorf("ошибка загрузки конфига каналов доставки: %w", err)
 	}
 
-	gmailNotifier, err := notifier.NewGmailNotifier(gmailConfig.From, gmailConfig.Password)
+	registry, err := newNotifierRegistry(notifierConfig)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка создания Gmail нотификатора: %w", err)
+		return nil, err
 	}
 
 	notificationService, err := service.NewNotificationService(
 		redisClient,
 		rabbitMQClient,
-		gmailNotifier,
+		redisClient,
+		redisClient,
+		redisClient,
+		redisClient,
+		registry,
 		"delayed_queue",
 		"ready_queue",
 	)
@@ -63,8 +67,42 @@ func InitNotificationService() (*service.NotificationService, error) {
 	return notificationService, nil
 }
 
-// NewServer создает новый сервер
-func NewServer(addr string, notificationSvc *service.NotificationService) *Server {
+// newNotifierRegistry регистрирует отправителей для всех настроенных каналов
+func newNotifierRegistry(cfg *config.NotifierConfig) (*notifier.Registry, error) {
+	registry := notifier.NewRegistry(cfg.DefaultChannel)
+
+	if cfg.SMTP != nil {
+		smtpNotifier, err := notifier.NewSMTPNotifier(*cfg.SMTP)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка создания SMTP нотификатора: %w", err)
+		}
+		registry.Register(notifier.ChannelEmail, smtpNotifier)
+	}
+	if cfg.Webhook != nil {
+		webhookNotifier, err := notifier.NewWebhookNotifier(*cfg.Webhook)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка создания webhook нотификатора: %w", err)
+		}
+		registry.Register(notifier.ChannelWebhook, webhookNotifier)
+	}
+	if cfg.Telegram != nil {
+		telegramNotifier, err := notifier.NewTelegramNotifier(*cfg.Telegram)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка создания Telegram нотификатора: %w", err)
+		}
+		registry.Register(notifier.ChannelTelegram, telegramNotifier)
+	}
+
+	if _, err := registry.Resolve(""); err != nil {
+		return nil, fmt.Errorf("канал по умолчанию: %w", err)
+	}
+	log.Printf("Каналы доставки: %v", registry.Channels())
+	return registry, nil
+}
+
+// NewServer создает новый сервер. Административные ручки доступны только с adminToken,
+// без него они не подключаются
+func NewServer(addr string, notificationSvc *service.NotificationService, adminToken string) *Server {
 	handler := handlers.NewDefaultHandler(notificationSvc)
 
 	r := chi.NewRouter()
@@ -81,6 +119,24 @@ func NewServer(addr string, notificationSvc *service.NotificationService) *Serve
 		r.Delete("/cancel", handler.CancelNotificationHandler)
 	})
 
+	r.Route("/templates/{name}", func(r chi.Router) {
+		r.Put("/", handler.SaveTemplateHandler)
+		r.Get("/", handler.GetTemplateHandler)
+		r.Delete("/", handler.DeleteTemplateHandler)
+		r.Post("/preview", handler.PreviewTemplateHandler)
+	})
+
+	if adminToken != "" {
+		r.Route("/admin/dlq", func(r chi.Router) {
+			r.Use(handlers.AdminAuth(adminToken))
+			r.Get("/", handler.ListDeadLettersHandler)
+			r.Get("/{id}", handler.GetDeadLetterHandler)
+			r.Post("/{id}/replay", handler.ReplayDeadLetterHandler)
+		})
+	} else {
+		log.Println("ADMIN_TOKEN не задан, административные ручки /admin выключены")
+	}
+
 	srv := &http.Server{
 		Addr:    addr,
 		Handler: r,
@@ -97,14 +153,14 @@ func (s *Server) Start() {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	go func() {
-		log.Println("Запуск Delayed Worker...")
+		log.Println("Запуск планировщика...")
 		defer func() {
 			if r := recover(); r != nil {
-				log.Printf("Delayed Worker упал: %v", r)
+				log.Printf("Планировщик упал: %v", r)
 			}
 		}()
-		if err := s.notificationSvc.StartDelayedWorker(ctx); err != nil && err != context.Canceled {
-			log.Printf("Ошибка delayed воркера: %v", err)
+		if err := s.notificationSvc.StartScheduler(ctx); err != nil && err != context.Canceled {
+			log.Printf("Ошибка планировщика: %v", err)
 		}
 	}()
 
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"L3.1/internal/models"
+)
+
+// maxHistory сколько последних смен статуса хранится у уведомления
+const maxHistory = 50
+
+// recordStatus — меняет текущий статус уведомления и дописывает смену в историю
+func (ns *NotificationService) recordStatus(ctx context.Context, id string, event models.StatusEvent) error {
+	event.At = time.Now()
+
+	redisMsg, err := ns.cache.Get(ctx, id)
+	if err != nil || redisMsg == nil {
+		// записи нет или она истекла — начинаем историю заново
+		redisMsg = &models.RedisMessage{ID: id}
+	}
+	redisMsg.Status = event.Status
+	redisMsg.UpdatedAt = event.At
+	if event.Error != "" {
+		redisMsg.LastError = event.Error
+	}
+	redisMsg.History = append(redisMsg.History, event)
+	if len(redisMsg.History) > maxHistory {
+		redisMsg.History = redisMsg.History[len(redisMsg.History)-maxHistory:]
+	}
+	return ns.cache.Set(ctx, id, redisMsg)
+}
+
+// deadLetter — кладёт сообщение в DLQ и помечает его failed. Если DLQ недоступна, ошибка
+// возвращается воркеру, и RabbitMQ вернёт сообщение в очередь, чтобы оно не потерялось
+func (ns *NotificationService) deadLetter(ctx context.Context, msg *models.RabbitMQMessage, sendErr error) error {
+	entry := &models.DeadLetter{Message: msg, LastError: sendErr.Error(), FailedAt: time.Now()}
+	if err := ns.deadLetters.AddDeadLetter(ctx, entry); err != nil {
+		return fmt.Errorf("не удалось положить сообщение %s в DLQ: %w", msg.ID, err)
+	}
+	_ = ns.recordStatus(ctx, msg.ID, models.StatusEvent{Status: models.StatusFailed, Attempt: msg.RetryCount + 1, Error: sendErr.Error()})
+
+	log.Printf("Уведомление %s не отправлено за %d попыток и перенесено в DLQ: %v", msg.ID, msg.RetryCount+1, sendErr)
+	return nil
+}
+
+// DeadLetters — страница DLQ, сначала новые, и общее число записей
+func (ns *NotificationService) DeadLetters(ctx context.Context, offset, limit int) ([]*models.DeadLetter, int, error) {
+	entries, total, err := ns.deadLetters.DeadLetters(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка при чтении DLQ: %w", err)
+	}
+	return entries, total, nil
+}
+
+// DeadLetter — запись DLQ по ID сообщения
+func (ns *NotificationService) DeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	entry, err := ns.deadLetters.DeadLetter(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при чтении DLQ: %w", err)
+	}
+	if entry == nil {
+		return nil, ErrDeadLetterNotFound
+	}
+	return entry, nil
+}
+
+// ReplayDeadLetter — отправляет сообщение из DLQ заново с полным запасом попыток.
+// Запись сначала забирается из DLQ, поэтому из двух одновременных replay отправит только один;
+// если сообщение не удалось вернуть в очередь, запись возвращается в DLQ
+func (ns *NotificationService) ReplayDeadLetter(ctx context.Context, id string) error {
+	entry, err := ns.DeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+	removed, err := ns.deadLetters.RemoveDeadLetter(ctx, id)
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении сообщения из DLQ: %w", err)
+	}
+	if !removed {
+		// запись забрал другой replay
+		return ErrDeadLetterNotFound
+	}
+
+	msg := *entry.Message
+	msg.RetryCount = 0
+	msg.SendAt = time.Now()
+
+	_ = ns.recordStatus(ctx, id, models.StatusEvent{Status: models.StatusReplayed})
+	if err := ns.queue.Publish(ctx, ns.queueNameReady, &msg); err != nil {
+		err = fmt.Errorf("не удалось вернуть сообщение в очередь: %w", err)
+		if restoreErr := ns.deadLetters.AddDeadLetter(ctx, entry); restoreErr != nil {
+			log.Printf("Сообщение %s не возвращено в DLQ: %v", id, restoreErr)
+		}
+		_ = ns.recordStatus(ctx, id, models.StatusEvent{Status: models.StatusFailed, Error: err.Error()})
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"L3.1/internal/models"
+)
+
+func TestHandleMessage_DeadLetterAndReplay(t *testing.T) {
+	n := &stubNotifier{sendErr: errors.New("smtp недоступен")}
+	ns, queue, schedule := newTestService(t, n)
+	ns.maxRetries = 1
+	ctx := context.Background()
+
+	result, err := ns.CreateNotification(ctx, models.CreateNotificationRequest{To: "user@example.com", Body: "текст", SendAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := result.ID
+	_, msg, _ := schedule.at(id)
+
+	// первая попытка — повтор, вторая исчерпывает maxRetries
+	for range 2 {
+		if err := ns.handleMessage(ctx, &msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entry, err := ns.DeadLetter(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.LastError != "smtp недоступен" || entry.Message.To != "user@example.com" {
+		t.Errorf("неверная запись DLQ: %+v", entry)
+	}
+	entries, total, err := ns.DeadLetters(ctx, 0, 10)
+	if err != nil || total != 1 || len(entries) != 1 {
+		t.Errorf("в DLQ ожидалась одна запись: %d, %v", total, err)
+	}
+
+	status, err := ns.GetStatus(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.StatusEvent{
+		{Status: models.StatusScheduled},
+		{Status: models.StatusSending, Attempt: 1},
+		{Status: models.StatusRetry, Attempt: 1, Error: "smtp недоступен"},
+		{Status: models.StatusSending, Attempt: 2},
+		{Status: models.StatusFailed, Attempt: 2, Error: "smtp недоступен"},
+	}
+	assertHistory(t, status, want)
+	if status.Status != models.StatusFailed || status.LastError != "smtp недоступен" {
+		t.Errorf("текущий статус %q, последняя ошибка %q", status.Status, status.LastError)
+	}
+
+	n.sendErr = nil
+	if err := ns.ReplayDeadLetter(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ns.DeadLetter(ctx, id); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("после replay запись должна уйти из DLQ, получено %v", err)
+	}
+	replayed := <-queue.published
+	if replayed.ID != id || replayed.RetryCount != 0 {
+		t.Errorf("в очередь вернулось %+v", replayed)
+	}
+	if err := ns.handleMessage(ctx, replayed); err != nil {
+		t.Fatal(err)
+	}
+
+	status, _ = ns.GetStatus(ctx, id)
+	assertHistory(t, status, append(want,
+		models.StatusEvent{Status: models.StatusReplayed},
+		models.StatusEvent{Status: models.StatusSending, Attempt: 1},
+		models.StatusEvent{Status: models.StatusSent, Attempt: 1},
+	))
+
+	if err := ns.ReplayDeadLetter(ctx, "unknown"); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("ожидалась ErrDeadLetterNotFound, получено %v", err)
+	}
+}
+
+func assertHistory(t *testing.T, status *models.RedisMessage, want []models.StatusEvent) {
+	t.Helper()
+	if len(status.History) != len(want) {
+		t.Fatalf("история %+v, ожидалось %d событий", status.History, len(want))
+	}
+	for i, event := range status.History {
+		if event.At.IsZero() {
+			t.Errorf("событие %d без времени", i)
+		}
+		event.At = time.Time{}
+		if event != want[i] {
+			t.Errorf("событие %d: %+v, ожидалось %+v", i, event, want[i])
+		}
+	}
+}
+
+func TestReplayDeadLetter_Concurrent(t *testing.T) {
+	ns, queue, _ := newTestService(t, &stubNotifier{})
+	ctx := context.Background()
+	msg := &models.RabbitMQMessage{ID: "1", To: "user@example.com", RetryCount: 5}
+	if err := ns.deadLetters.AddDeadLetter(ctx, &models.DeadLetter{Message: msg, LastError: "ошибка", FailedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- ns.ReplayDeadLetter(ctx, msg.ID)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrDeadLetterNotFound):
+			t.Errorf("неожиданная ошибка: %v", err)
+		}
+	}
+	if succeeded != 1 || len(queue.published) != 1 {
+		t.Errorf("успешных replay %d, опубликовано %d, ожидалось по одному", succeeded, len(queue.published))
+	}
+}
+
+func TestReplayDeadLetter_PublishFailureRestoresEntry(t *testing.T) {
+	ns, queue, _ := newTestService(t, &stubNotifier{})
+	ctx := context.Background()
+	msg := &models.RabbitMQMessage{ID: "1", To: "user@example.com", RetryCount: 5}
+	if err := ns.deadLetters.AddDeadLetter(ctx, &models.DeadLetter{Message: msg, LastError: "ошибка", FailedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	queue.publishErr = errors.New("rabbitmq недоступен")
+
+	if err := ns.ReplayDeadLetter(ctx, msg.ID); err == nil {
+		t.Fatal("ожидалась ошибка публикации")
+	}
+	entry, err := ns.DeadLetter(ctx, msg.ID)
+	if err != nil {
+		t.Fatalf("запись должна вернуться в DLQ: %v", err)
+	}
+	if entry.Message.RetryCount != 5 {
+		t.Errorf("запись в DLQ изменилась: %+v", entry.Message)
+	}
+}
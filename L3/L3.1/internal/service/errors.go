@@ -0,0 +1,18 @@
+package service
+
+import "errors"
+
+// ErrValidation запрос на создание уведомления некорректен, хендлер отвечает на него 400
+var ErrValidation = errors.New("некорректный запрос")
+
+// ErrTemplateNotFound шаблона с таким именем нет ни на запрошенном языке, ни на языке по умолчанию
+var ErrTemplateNotFound = errors.New("шаблон не найден")
+
+// ErrIdempotencyConflict Idempotency-Key уже использован с другим телом запроса
+var ErrIdempotencyConflict = errors.New("ключ идемпотентности уже использован с другим запросом")
+
+// ErrIdempotencyInProgress запрос с таким Idempotency-Key ещё обрабатывается
+var ErrIdempotencyInProgress = errors.New("запрос с этим ключом идемпотентности ещё обрабатывается")
+
+// ErrDeadLetterNotFound сообщения с таким ID нет в DLQ
+var ErrDeadLetterNotFound = errors.New("сообщение не найдено в DLQ")
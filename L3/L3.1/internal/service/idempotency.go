@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"L3.1/internal/models"
+	"L3.1/internal/notifier"
+)
+
+// ключи в Redis
+const (
+	// idempotencyKeyPrefix notify:idempotency:<Idempotency-Key> -> idempotencyRecord в JSON
+	idempotencyKeyPrefix = "notify:idempotency:"
+	// dedupeKeyPrefix notify:dedupe:<хеш содержимого> -> ID уведомления
+	dedupeKeyPrefix = "notify:dedupe:"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	maxDedupeWindow         = 7 * 24 * time.Hour
+)
+
+// idempotencyRecord то, что хранится по ключу идемпотентности
+type idempotencyRecord struct {
+	// Fingerprint хеш тела запроса: с тем же ключом, но другим телом запрос отклоняется
+	Fingerprint string `json:"fingerprint"`
+	// Result пустой, пока первый запрос обрабатывается
+	Result *models.CreateNotificationResult `json:"result,omitempty"`
+}
+
+// createIdempotent — создаёт уведомление не больше одного раза на Idempotency-Key. Пока первый
+// запрос обрабатывается, ключ держится idempotencyPendingTTL, после успеха ответ хранится
+// idempotencyTTL. Неудачный запрос ключ освобождает, чтобы клиент мог его повторить
+func (ns *NotificationService) createIdempotent(ctx context.Context, req models.CreateNotificationRequest) (*models.CreateNotificationResult, error) {
+	if err := validateIdempotencyKey(req.IdempotencyKey); err != nil {
+		return nil, err
+	}
+	fingerprint, err := requestFingerprint(req)
+	if err != nil {
+		return nil, err
+	}
+	key := idempotencyKeyPrefix + req.IdempotencyKey
+
+	pending, err := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+	current, reserved, err := ns.keys.Reserve(ctx, key, string(pending), ns.idempotencyPendingTTL)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при проверке ключа идемпотентности: %w", err)
+	}
+	if !reserved {
+		var record idempotencyRecord
+		if err := json.Unmarshal([]byte(current), &record); err != nil {
+			return nil, fmt.Errorf("не удалось прочитать ключ идемпотентности: %w", err)
+		}
+		if record.Fingerprint != fingerprint {
+			return nil, ErrIdempotencyConflict
+		}
+		if record.Result == nil {
+			return nil, ErrIdempotencyInProgress
+		}
+		result := *record.Result
+		result.Replayed = true
+		return &result, nil
+	}
+
+	result, err := ns.createNotification(ctx, req)
+	if err != nil {
+		if err := ns.keys.Release(ctx, key); err != nil {
+			log.Printf("Ошибка при освобождении ключа идемпотентности: %v", err)
+		}
+		return nil, err
+	}
+
+	done, err := json.Marshal(idempotencyRecord{Fingerprint: fingerprint, Result: result})
+	if err == nil {
+		err = ns.keys.Put(ctx, key, string(done), ns.idempotencyTTL)
+	}
+	if err != nil {
+		// уведомление уже создано, поэтому запрос не проваливаем: без сохранённого ответа
+		// повтор получит 409, пока не истечёт idempotencyPendingTTL
+		log.Printf("Ошибка при сохранении ответа по ключу идемпотентности: %v", err)
+	}
+	return result, nil
+}
+
+// reserveDedupe — закрепляет содержимое за уведомлением id на window. Если такое же уведомление
+// уже создано и не отменено, возвращает его ID
+func (ns *NotificationService) reserveDedupe(ctx context.Context, key, id string, window time.Duration) (string, error) {
+	existing, reserved, err := ns.keys.Reserve(ctx, key, id, window)
+	if err != nil {
+		return "", fmt.Errorf("ошибка при проверке дубликатов: %w", err)
+	}
+	if reserved {
+		return "", nil
+	}
+
+	// отменённое уведомление не мешает создать такое же заново
+	status, err := ns.cache.Get(ctx, existing)
+	if err == nil && status.Status != models.StatusCanceled {
+		return existing, nil
+	}
+	if err := ns.keys.Put(ctx, key, id, window); err != nil {
+		return "", fmt.Errorf("ошибка при проверке дубликатов: %w", err)
+	}
+	return "", nil
+}
+
+// validateIdempotencyKey — ключ попадает в ключ Redis, поэтому ограничиваем длину и символы
+func validateIdempotencyKey(key string) error {
+	if len(key) > maxIdempotencyKeyLength {
+		return fmt.Errorf("%w: Idempotency-Key длиннее %d символов", ErrValidation, maxIdempotencyKeyLength)
+	}
+	for _, r := range key {
+		if r < '!' || r > '~' {
+			return fmt.Errorf("%w: Idempotency-Key может содержать только печатные ASCII-символы", ErrValidation)
+		}
+	}
+	return nil
+}
+
+// requestFingerprint — хеш тела запроса; map в JSON сериализуется с сортировкой ключей,
+// поэтому порядок vars на хеш не влияет
+func requestFingerprint(req models.CreateNotificationRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// contentFingerprint — хеш того, что увидит получатель: канал, адрес, тема и текст
+func contentFingerprint(msg *models.RabbitMQMessage) string {
+	to := msg.To
+	if msg.Channel == notifier.ChannelEmail {
+		to = strings.ToLower(strings.TrimSpace(to))
+	}
+	h := sha256.New()
+	for _, part := range []string{msg.Channel, to, msg.Subject, msg.Body, msg.HTMLBody} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
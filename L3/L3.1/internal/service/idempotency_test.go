@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"L3.1/internal/models"
+)
+
+func TestCreateNotification_IdempotencyKey(t *testing.T) {
+	ns, _, schedule := newTestService(t, &stubNotifier{})
+	ctx := context.Background()
+	req := models.CreateNotificationRequest{
+		To:             "user@example.com",
+		Body:           "текст",
+		SendAt:         time.Now().Add(time.Hour),
+		IdempotencyKey: "order-15",
+	}
+
+	first, err := ns.CreateNotification(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay, err := ns.CreateNotification(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.ID != first.ID || !replay.Replayed || first.Replayed {
+		t.Errorf("повтор должен вернуть исходный ответ: первый %+v, повтор %+v", first, replay)
+	}
+	if schedule.len() != 1 {
+		t.Errorf("в расписании %d сообщений, ожидалось 1", schedule.len())
+	}
+
+	changed := req
+	changed.Body = "другой текст"
+	if _, err := ns.CreateNotification(ctx, changed); !errors.Is(err, ErrIdempotencyConflict) {
+		t.Errorf("ожидалась ErrIdempotencyConflict, получено %v", err)
+	}
+}
+
+func TestCreateNotification_IdempotencyKeyReleasedOnError(t *testing.T) {
+	ns, _, _ := newTestService(t, &stubNotifier{})
+	ctx := context.Background()
+	req := models.CreateNotificationRequest{To: "не email", Body: "текст", IdempotencyKey: "k1"}
+
+	if _, err := ns.CreateNotification(ctx, req); !errors.Is(err, ErrValidation) {
+		t.Fatalf("ожидалась ошибка валидации, получено %v", err)
+	}
+	// исправленный запрос с тем же ключом проходит
+	req.To = "user@example.com"
+	if _, err := ns.CreateNotification(ctx, req); err != nil {
+		t.Errorf("после ошибки ключ должен освобождаться: %v", err)
+	}
+
+	req.IdempotencyKey = "ключ"
+	if _, err := ns.CreateNotification(ctx, req); !errors.Is(err, ErrValidation) {
+		t.Errorf("не-ASCII ключ должен быть ошибкой валидации, получено %v", err)
+	}
+}
+
+func TestCreateNotification_IdempotencyInProgress(t *testing.T) {
+	ns, _, _ := newTestService(t, &stubNotifier{})
+	ctx := context.Background()
+	req := models.CreateNotificationRequest{To: "user@example.com", Body: "текст", IdempotencyKey: "k1"}
+
+	fingerprint, err := requestFingerprint(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// первый запрос занял ключ, но ещё не сохранил ответ
+	_ = ns.keys.Put(ctx, idempotencyKeyPrefix+"k1", `{"fingerprint":"`+fingerprint+`"}`, time.Minute)
+
+	if _, err := ns.CreateNotification(ctx, req); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Errorf("ожидалась ErrIdempotencyInProgress, получено %v", err)
+	}
+}
+
+func TestCreateNotification_Dedupe(t *testing.T) {
+	ns, _, schedule := newTestService(t, &stubNotifier{})
+	ctx := context.Background()
+	req := models.CreateNotificationRequest{
+		To:                  "User@Example.com",
+		Subject:             "Привет",
+		Body:                "текст",
+		SendAt:              time.Now().Add(time.Hour),
+		DedupeWindowSeconds: 600,
+	}
+
+	first, err := ns.CreateNotification(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.To = "user@example.com"
+	second, err := ns.CreateNotification(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !second.Duplicate || second.ID != first.ID {
+		t.Errorf("ожидался дубликат %s, получено %+v", first.ID, second)
+	}
+
+	// другой текст — другое уведомление
+	other := req
+	other.Body = "другой текст"
+	if result, err := ns.CreateNotification(ctx, other); err != nil || result.Duplicate {
+		t.Errorf("другое содержимое не должно считаться дубликатом: %+v, %v", result, err)
+	}
+
+	// после отмены такое же уведомление можно создать снова
+	if err := ns.CancelNotification(ctx, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	third, err := ns.CreateNotification(ctx, req)
+	if err != nil || third.Duplicate {
+		t.Errorf("отменённое уведомление не должно считаться дубликатом: %+v, %v", third, err)
+	}
+	if schedule.len() != 2 {
+		t.Errorf("в расписании %d сообщений, ожидалось 2", schedule.len())
+	}
+
+	req.DedupeWindowSeconds = -1
+	if _, err := ns.CreateNotification(ctx, req); !errors.Is(err, ErrValidation) {
+		t.Errorf("отрицательное окно должно быть ошибкой валидации, получено %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// StartScheduler — перекладывает запланированные сообщения в ready очередь точно в их время.
+// Планировщик спит до ближайшего сообщения в расписании, но не дольше pollInterval, чтобы
+// заметить сообщения, запланированные другими экземплярами; новые сообщения этого экземпляра
+// будят его сразу. Расписание хранится в Redis, поэтому переживает перезапуск
+func (ns *NotificationService) StartScheduler(ctx context.Context) error {
+	log.Println("Планировщик запущен")
+	ns.drainDelayedQueue(ctx)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Планировщик остановлен")
+			return ctx.Err()
+		case <-timer.C:
+		case <-ns.wakeup:
+		}
+		ns.moveDue(ctx)
+		timer.Reset(ns.nextWait(ctx))
+	}
+}
+
+// moveDue — перекладывает все подошедшие сообщения пачками по claimBatch
+func (ns *NotificationService) moveDue(ctx context.Context) {
+	for {
+		messages, err := ns.schedule.ClaimDue(ctx, time.Now(), ns.claimBatch, ns.claimLease)
+		if err != nil {
+			log.Printf("Ошибка чтения расписания: %v", err)
+			return
+		}
+		for _, msg := range messages {
+			if err := ns.queue.Publish(ctx, ns.queueNameReady, msg); err != nil {
+				// без подтверждения сообщение вернётся в расписание, когда истечёт аренда
+				log.Printf("Ошибка при переносе сообщения %s в ready очередь: %v", msg.ID, err)
+				continue
+			}
+			if err := ns.schedule.Ack(ctx, msg.ID); err != nil {
+				log.Printf("Ошибка подтверждения сообщения %s: %v", msg.ID, err)
+			}
+		}
+		if len(messages) < ns.claimBatch {
+			return
+		}
+	}
+}
+
+// nextWait — сколько спать до ближайшего сообщения в расписании
+func (ns *NotificationService) nextWait(ctx context.Context) time.Duration {
+	next, ok, err := ns.schedule.NextDue(ctx)
+	if err != nil {
+		log.Printf("Ошибка чтения расписания: %v", err)
+		return ns.pollInterval
+	}
+	if !ok {
+		return ns.pollInterval
+	}
+	return min(max(time.Until(next), 0), ns.pollInterval)
+}
+
+// wake — будит планировщик, чтобы он пересчитал время до ближайшего сообщения
+func (ns *NotificationService) wake() {
+	select {
+	case ns.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// drainDelayedQueue — переносит в расписание сообщения, оставшиеся в delayed очереди RabbitMQ
+// с тех пор, как отложенные сообщения хранились в ней
+func (ns *NotificationService) drainDelayedQueue(ctx context.Context) {
+	if ns.queueNameDelayed == "" {
+		return
+	}
+	moved := 0
+	for {
+		msg, err := ns.queue.ConsumeSingleMessage(ctx, ns.queueNameDelayed)
+		if err != nil {
+			break
+		}
+		if err := ns.schedule.Schedule(ctx, msg, msg.SendAt); err != nil {
+			log.Printf("Ошибка переноса сообщения %s из delayed очереди: %v", msg.ID, err)
+			// сообщение уже забрано из очереди, возвращаем его туда, чтобы не потерять
+			if err := ns.queue.Publish(ctx, ns.queueNameDelayed, msg); err != nil {
+				log.Printf("Сообщение %s потеряно: %v", msg.ID, err)
+			}
+			break
+		}
+		moved++
+	}
+	if moved > 0 {
+		log.Printf("Из delayed очереди в расписание перенесено сообщений: %d", moved)
+	}
+}
@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"L3.1/internal/models"
+)
+
+// memCache — CacheClient в памяти
+type memCache struct {
+	mu   sync.Mutex
+	data map[string]models.RedisMessage
+}
+
+func (c *memCache) Set(_ context.Context, key string, message *models.RedisMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = *message
+	return nil
+}
+
+func (c *memCache) SetWithTTL(ctx context.Context, key string, message *models.RedisMessage, _ int) error {
+	return c.Set(ctx, key, message)
+}
+
+func (c *memCache) Get(_ context.Context, key string) (*models.RedisMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	msg, ok := c.data[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &msg, nil
+}
+
+func (c *memCache) Exists(_ context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.data[key]
+	return ok, nil
+}
+
+func (c *memCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.data, key)
+	return nil
+}
+
+// memQueue — QueueMQClient в памяти, публикации в ready очередь попадают в канал published
+type memQueue struct {
+	published chan *models.RabbitMQMessage
+	// publishErr если задана, Publish возвращает её
+	publishErr error
+	// onPublish если задан, вызывается внутри Publish — как воркер, который получил сообщение сразу
+	onPublish func(msg *models.RabbitMQMessage)
+}
+
+func (q *memQueue) Publish(_ context.Context, _ string, message *models.RabbitMQMessage) error {
+	if q.publishErr != nil {
+		return q.publishErr
+	}
+	q.published <- message
+	if q.onPublish != nil {
+		q.onPublish(message)
+	}
+	return nil
+}
+
+func (q *memQueue) Consume(ctx context.Context, _ string, _ func(msg *models.RabbitMQMessage) error) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (q *memQueue) ConsumeSingleMessage(context.Context, string) (*models.RabbitMQMessage, error) {
+	return nil, errors.New("очередь пуста")
+}
+
+func (q *memQueue) QueueLength(context.Context, string) (int, error) { return 0, nil }
+
+// memSchedule — ScheduleClient в памяти, устроен как в Redis: расписание, аренды
+// и полезная нагрузка хранятся отдельно, аренда не истекает
+type memSchedule struct {
+	mu       sync.Mutex
+	due      map[string]time.Time
+	inflight map[string]bool
+	payload  map[string]models.RabbitMQMessage
+}
+
+func newMemSchedule() *memSchedule {
+	return &memSchedule{
+		due:      map[string]time.Time{},
+		inflight: map[string]bool{},
+		payload:  map[string]models.RabbitMQMessage{},
+	}
+}
+
+func (s *memSchedule) Schedule(_ context.Context, message *models.RabbitMQMessage, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.payload[message.ID] = *message
+	delete(s.inflight, message.ID)
+	s.due[message.ID] = at
+	return nil
+}
+
+func (s *memSchedule) ClaimDue(_ context.Context, now time.Time, limit int, _ time.Duration) ([]*models.RabbitMQMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for id, at := range s.due {
+		if !at.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return s.due[ids[i]].Before(s.due[ids[j]]) })
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	result := make([]*models.RabbitMQMessage, 0, len(ids))
+	for _, id := range ids {
+		delete(s.due, id)
+		msg, ok := s.payload[id]
+		if !ok {
+			continue
+		}
+		s.inflight[id] = true
+		result = append(result, &msg)
+	}
+	return result, nil
+}
+
+func (s *memSchedule) Ack(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inflight, id)
+	if _, ok := s.due[id]; !ok {
+		delete(s.payload, id)
+	}
+	return nil
+}
+
+func (s *memSchedule) Unschedule(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.due, id)
+	delete(s.inflight, id)
+	delete(s.payload, id)
+	return nil
+}
+
+func (s *memSchedule) NextDue(context.Context) (time.Time, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	for _, at := range s.due {
+		if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	return next, !next.IsZero(), nil
+}
+
+// at — время отправки и сообщение из расписания
+func (s *memSchedule) at(id string) (time.Time, models.RabbitMQMessage, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	at, ok := s.due[id]
+	msg, hasPayload := s.payload[id]
+	return at, msg, ok && hasPayload
+}
+
+// len — число сообщений в расписании
+func (s *memSchedule) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.due)
+}
+
+// memTemplates — TemplateStore в памяти
+type memTemplates struct {
+	mu   sync.Mutex
+	data map[string]map[string]*models.Template
+}
+
+func (m *memTemplates) SaveTemplate(_ context.Context, template *models.Template) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data[template.Name] == nil {
+		m.data[template.Name] = map[string]*models.Template{}
+	}
+	saved := *template
+	m.data[template.Name][template.Locale] = &saved
+	return nil
+}
+
+func (m *memTemplates) Templates(_ context.Context, name string) (map[string]*models.Template, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	templates := map[string]*models.Template{}
+	for locale, template := range m.data[name] {
+		templates[locale] = template
+	}
+	return templates, nil
+}
+
+func (m *memTemplates) DeleteTemplate(_ context.Context, name, locale string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if locale == "" {
+		_, ok := m.data[name]
+		delete(m.data, name)
+		return ok, nil
+	}
+	_, ok := m.data[name][locale]
+	delete(m.data[name], locale)
+	return ok, nil
+}
+
+// memKeys — KeyReserver в памяти, TTL не учитывается
+type memKeys struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (m *memKeys) Reserve(_ context.Context, key, value string, _ time.Duration) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if current, ok := m.data[key]; ok {
+		return current, false, nil
+	}
+	m.data[key] = value
+	return "", true, nil
+}
+
+func (m *memKeys) Put(_ context.Context, key, value string, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = value
+	return nil
+}
+
+func (m *memKeys) Release(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+	return nil
+}
+
+// memDeadLetters — DeadLetterStore в памяти
+type memDeadLetters struct {
+	mu   sync.Mutex
+	data map[string]*models.DeadLetter
+}
+
+func (m *memDeadLetters) AddDeadLetter(_ context.Context, entry *models.DeadLetter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	saved := *entry
+	msg := *entry.Message
+	saved.Message = &msg
+	m.data[msg.ID] = &saved
+	return nil
+}
+
+func (m *memDeadLetters) DeadLetters(_ context.Context, offset, limit int) ([]*models.DeadLetter, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries := make([]*models.DeadLetter, 0, len(m.data))
+	for _, entry := range m.data {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].FailedAt.After(entries[j].FailedAt) })
+	total := len(entries)
+	entries = entries[min(offset, total):min(offset+limit, total)]
+	return entries, total, nil
+}
+
+func (m *memDeadLetters) DeadLetter(_ context.Context, id string) (*models.DeadLetter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.data[id], nil
+}
+
+func (m *memDeadLetters) RemoveDeadLetter(_ context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.data[id]
+	delete(m.data, id)
+	return ok, nil
+}
+
+// stubNotifier — Notifier, который возвращает sendErr
+type stubNotifier struct {
+	sendErr error
+}
+
+func (n *stubNotifier) Send(context.Context, *models.RabbitMQMessage) error { return n.sendErr }
+
+func (n *stubNotifier) Resolve(channel string) (string, error) {
+	if channel == "" {
+		return "email", nil
+	}
+	return channel, nil
+}
+
+func newTestService(t *testing.T, n Notifier) (*NotificationService, *memQueue, *memSchedule) {
+	t.Helper()
+	queue := &memQueue{published: make(chan *models.RabbitMQMessage, 10)}
+	schedule := newMemSchedule()
+	templates := &memTemplates{data: map[string]map[string]*models.Template{}}
+	keys := &memKeys{data: map[string]string{}}
+	deadLetters := &memDeadLetters{data: map[string]*models.DeadLetter{}}
+	ns, err := NewNotificationService(&memCache{data: map[string]models.RedisMessage{}}, queue, schedule, templates, keys, deadLetters, n, "", "ready")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ns, queue, schedule
+}
+
+func TestScheduler_DeliversOnTime(t *testing.T) {
+	ns, queue, _ := newTestService(t, &stubNotifier{})
+	// большой интервал опроса: доставка вовремя должна обеспечиваться таймером, а не опросом
+	ns.pollInterval = time.Minute
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ns.StartScheduler(ctx)
+
+	sendAt := time.Now().Add(300 * time.Millisecond)
+	result, err := ns.CreateNotification(ctx, models.CreateNotificationRequest{To: "user@example.com", Body: "текст", SendAt: sendAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := result.ID
+
+	select {
+	case msg := <-queue.published:
+		if msg.ID != id {
+			t.Fatalf("в ready очередь попало чужое сообщение %s", msg.ID)
+		}
+		if late := time.Since(sendAt); late < 0 || late > 200*time.Millisecond {
+			t.Errorf("сообщение доставлено с отклонением %v", late)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("сообщение не попало в ready очередь")
+	}
+}
+
+func TestScheduler_CancelRemovesFromSchedule(t *testing.T) {
+	ns, _, schedule := newTestService(t, &stubNotifier{})
+	ctx := context.Background()
+
+	result, err := ns.CreateNotification(ctx, models.CreateNotificationRequest{To: "user@example.com", SendAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := result.ID
+	if err := ns.CancelNotification(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := schedule.at(id); ok {
+		t.Error("отменённое сообщение осталось в расписании")
+	}
+}
+
+func TestHandleMessage_RetryIsScheduled(t *testing.T) {
+	ns, _, schedule := newTestService(t, &stubNotifier{sendErr: errors.New("smtp недоступен")})
+	ctx := context.Background()
+
+	msg := &models.RabbitMQMessage{ID: "1", To: "user@example.com", RetryCount: 1}
+	_ = ns.cache.Set(ctx, msg.ID, &models.RedisMessage{ID: msg.ID, Status: "scheduled"})
+
+	before := time.Now()
+	if err := ns.handleMessage(ctx, msg); err != nil {
+		t.Fatalf("повтор не должен возвращать сообщение в очередь: %v", err)
+	}
+
+	at, _, ok := schedule.at(msg.ID)
+	if !ok {
+		t.Fatal("повтор не попал в расписание")
+	}
+	// 2^1 * retryBaseSeconds
+	if want := before.Add(4 * time.Second); at.Before(want) || at.After(want.Add(time.Second)) {
+		t.Errorf("повтор запланирован на %v, ожидалось около %v", at, want)
+	}
+	if msg.RetryCount != 2 {
+		t.Errorf("RetryCount = %d, ожидалось 2", msg.RetryCount)
+	}
+}
+
+func TestScheduler_RetryScheduledBeforeAckSurvives(t *testing.T) {
+	ns, queue, schedule := newTestService(t, &stubNotifier{sendErr: errors.New("smtp недоступен")})
+	ctx := context.Background()
+
+	result, err := ns.CreateNotification(ctx, models.CreateNotificationRequest{To: "user@example.com", Body: "текст", SendAt: time.Now().Add(10 * time.Millisecond)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// воркер получает сообщение и планирует повтор раньше, чем планировщик вызывает Ack
+	queue.onPublish = func(msg *models.RabbitMQMessage) {
+		if err := ns.handleMessage(ctx, msg); err != nil {
+			t.Error(err)
+		}
+	}
+	ns.moveDue(ctx)
+
+	_, msg, ok := schedule.at(result.ID)
+	if !ok {
+		t.Fatal("Ack удалил запланированный повтор")
+	}
+	if msg.RetryCount != 1 {
+		t.Errorf("RetryCount = %d, ожидалось 1", msg.RetryCount)
+	}
+}
@@ -2,94 +2,210 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
+	"net/mail"
 	"time"
 
 	"L3.1/internal/infrastructure"
 	"L3.1/internal/models"
+	"L3.1/internal/notifier"
 	"github.com/google/uuid"
 )
 
-// Notifier — интерфейс, через который мы отправляем уведомления (например, Gmail)
+// Notifier — интерфейс, через который мы отправляем уведомления по каналу сообщения (email, webhook, telegram)
 type Notifier interface {
 	Send(ctx context.Context, msg *models.RabbitMQMessage) error
+	// Resolve проверяет, что канал настроен, и подставляет канал по умолчанию вместо пустого
+	Resolve(channel string) (string, error)
 }
 
 // NotificationService — основной сервис для создания, отмены и обработки уведомлений
 type NotificationService struct {
 	cache            infrastructure.CacheClient
 	queue            infrastructure.QueueMQClient
+	schedule         infrastructure.ScheduleClient
+	templateStore    infrastructure.TemplateStore
+	keys             infrastructure.KeyReserver
+	deadLetters      infrastructure.DeadLetterStore
 	notifier         Notifier
 	queueNameDelayed string
 	queueNameReady   string
 
 	retryBaseSeconds int
 	maxRetries       int
+
+	// параметры планировщика, см. StartScheduler
+	pollInterval time.Duration
+	claimBatch   int
+	claimLease   time.Duration
+	wakeup       chan struct{}
+
+	// сколько хранится ответ по Idempotency-Key и сколько ключ держится за незавершённым запросом
+	idempotencyTTL        time.Duration
+	idempotencyPendingTTL time.Duration
 }
 
-// NewNotificationService — конструктор сервиса
+// NewNotificationService — конструктор сервиса. delayedQueue — очередь RabbitMQ, через которую
+// отложенные сообщения шли до планировщика; при запуске её остатки переносятся в расписание
 func NewNotificationService(
 	cache infrastructure.CacheClient,
 	queue infrastructure.QueueMQClient,
+	schedule infrastructure.ScheduleClient,
+	templateStore infrastructure.TemplateStore,
+	keys infrastructure.KeyReserver,
+	deadLetters infrastructure.DeadLetterStore,
 	notifier Notifier,
 	delayedQueue string,
 	readyQueue string,
 ) (*NotificationService, error) {
-	if cache == nil || queue == nil || notifier == nil {
+	if cache == nil || queue == nil || schedule == nil || templateStore == nil || keys == nil || deadLetters == nil || notifier == nil {
 		return nil, fmt.Errorf("не все зависимости переданы в конструктор")
 	}
 
 	return &NotificationService{
 		cache:            cache,
 		queue:            queue,
+		schedule:         schedule,
+		templateStore:    templateStore,
+		keys:             keys,
+		deadLetters:      deadLetters,
 		notifier:         notifier,
 		queueNameReady:   readyQueue,
 		queueNameDelayed: delayedQueue,
 		retryBaseSeconds: 2,
 		maxRetries:       5,
+		pollInterval:     500 * time.Millisecond,
+		claimBatch:       100,
+		claimLease:       30 * time.Second,
+		wakeup:           make(chan struct{}, 1),
+
+		idempotencyTTL:        24 * time.Hour,
+		idempotencyPendingTTL: time.Minute,
 	}, nil
 }
 
-// CreateNotification — создаёт новое уведомление
-func (ns *NotificationService) CreateNotification(ctx context.Context, to, subject, body string, sendAt time.Time) (string, error) {
+// CreateNotification — создаёт новое уведомление в канале req.Channel. Если указан req.Template,
+// тема и текст берутся из шаблона: переменные подставляются сразу, поэтому отправится ровно то,
+// что показывает предпросмотр, даже если шаблон потом изменят.
+// С req.IdempotencyKey повторный запрос возвращает сохранённый ответ первого, см. createIdempotent
+func (ns *NotificationService) CreateNotification(ctx context.Context, req models.CreateNotificationRequest) (*models.CreateNotificationResult, error) {
+	if req.IdempotencyKey != "" {
+		return ns.createIdempotent(ctx, req)
+	}
+	return ns.createNotification(ctx, req)
+}
+
+func (ns *NotificationService) createNotification(ctx context.Context, req models.CreateNotificationRequest) (*models.CreateNotificationResult, error) {
+	channel, err := ns.notifier.Resolve(req.Channel)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	if err := validateRecipient(channel, req.To); err != nil {
+		return nil, err
+	}
+	dedupeWindow := time.Duration(req.DedupeWindowSeconds) * time.Second
+	if dedupeWindow < 0 || dedupeWindow > maxDedupeWindow {
+		return nil, fmt.Errorf("%w: dedupeWindowSeconds должно быть от 0 до %d", ErrValidation, int(maxDedupeWindow.Seconds()))
+	}
+
+	subject, body, htmlBody := req.Subject, req.Body, ""
+	if req.Template != "" {
+		if req.Subject != "" || req.Body != "" {
+			return nil, fmt.Errorf("%w: укажите либо template, либо subject и body", ErrValidation)
+		}
+		rendered, err := ns.RenderTemplate(ctx, req.Template, req.Locale, req.Vars)
+		if errors.Is(err, ErrTemplateNotFound) {
+			return nil, fmt.Errorf("%w: шаблон %q не найден", ErrValidation, req.Template)
+		}
+		if err != nil {
+			return nil, err
+		}
+		subject, body, htmlBody = rendered.Subject, rendered.Text, rendered.HTML
+	}
+
 	id := uuid.New().String()
 
 	rabbitMsg := &models.RabbitMQMessage{
 		ID:         id,
-		To:         to,
+		Channel:    channel,
+		To:         req.To,
 		Subject:    subject,
 		Body:       body,
-		SendAt:     sendAt,
+		HTMLBody:   htmlBody,
+		SendAt:     req.SendAt,
 		RetryCount: 0,
 	}
 
+	var dedupeKey string
+	if dedupeWindow > 0 {
+		dedupeKey = dedupeKeyPrefix + contentFingerprint(rabbitMsg)
+		existing, err := ns.reserveDedupe(ctx, dedupeKey, id, dedupeWindow)
+		if err != nil {
+			return nil, err
+		}
+		if existing != "" {
+			return &models.CreateNotificationResult{ID: existing, Duplicate: true}, nil
+		}
+	}
+
+	if err := ns.enqueue(ctx, rabbitMsg); err != nil {
+		if dedupeKey != "" {
+			// уведомление не создано, такое же можно будет создать снова
+			if err := ns.keys.Release(ctx, dedupeKey); err != nil {
+				log.Printf("Ошибка при освобождении ключа дедупликации: %v", err)
+			}
+		}
+		return nil, err
+	}
+	return &models.CreateNotificationResult{ID: id}, nil
+}
+
+// enqueue — сохраняет статус и отправляет сообщение сразу или ставит его в расписание
+func (ns *NotificationService) enqueue(ctx context.Context, rabbitMsg *models.RabbitMQMessage) error {
+	now := time.Now()
 	redisMsg := &models.RedisMessage{
-		ID:        id,
-		Status:    "scheduled",
-		UpdatedAt: time.Now(),
+		ID:        rabbitMsg.ID,
+		Status:    models.StatusScheduled,
+		UpdatedAt: now,
+		History:   []models.StatusEvent{{Status: models.StatusScheduled, At: now}},
 	}
 
-	if err := ns.cache.Set(ctx, id, redisMsg); err != nil {
-		return "", fmt.Errorf("ошибка при добавлении в кэш: %w", err)
+	if err := ns.cache.Set(ctx, rabbitMsg.ID, redisMsg); err != nil {
+		return fmt.Errorf("ошибка при добавлении в кэш: %w", err)
 	}
 
-	delay := time.Until(sendAt)
+	delay := time.Until(rabbitMsg.SendAt)
 	if delay <= 0 {
 		// если время отправки уже наступило — публикуем сразу
 		if err := ns.queue.Publish(ctx, ns.queueNameReady, rabbitMsg); err != nil {
-			return id, fmt.Errorf("немедленная отправка не удалась: %w", err)
+			return fmt.Errorf("немедленная отправка не удалась: %w", err)
 		}
-		return id, nil
+		return nil
 	}
 
-	// публикуем в отложенную очередь
-	if err := ns.queue.Publish(ctx, ns.queueNameDelayed, rabbitMsg); err != nil {
-		return id, fmt.Errorf("отложенная отправка не удалась: %w", err)
+	// ставим в расписание, планировщик переложит сообщение в ready очередь в момент sendAt
+	if err := ns.schedule.Schedule(ctx, rabbitMsg, rabbitMsg.SendAt); err != nil {
+		return fmt.Errorf("отложенная отправка не удалась: %w", err)
 	}
+	ns.wake()
+
+	return nil
+}
 
-	return id, nil
+// validateRecipient — проверяет адрес получателя в формате канала
+func validateRecipient(channel, to string) error {
+	if to == "" {
+		return fmt.Errorf("%w: не указан получатель", ErrValidation)
+	}
+	if channel == notifier.ChannelEmail {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("%w: некорректный email %q", ErrValidation, to)
+		}
+	}
+	return nil
 }
 
 // GetStatus — получение статуса уведомления из Redis
@@ -106,19 +222,17 @@ func (ns *NotificationService) CancelNotification(ctx context.Context, messageID
 	if redisMessage == nil {
 		return fmt.Errorf("сообщение с ID %s не найдено", messageID)
 	}
-	if redisMessage.Status == "canceled" {
+	if redisMessage.Status == models.StatusCanceled {
 		return fmt.Errorf("сообщение уже было отменено")
 	}
 
-	cancelMsg := &models.RedisMessage{
-		ID:        redisMessage.ID,
-		Status:    "canceled",
-		UpdatedAt: time.Now(),
-	}
-
-	if err := ns.cache.Set(ctx, cancelMsg.ID, cancelMsg); err != nil {
+	if err := ns.recordStatus(ctx, messageID, models.StatusEvent{Status: models.StatusCanceled}); err != nil {
 		return fmt.Errorf("ошибка при обновлении статуса отмены: %w", err)
 	}
+	// отменённое сообщение не нужно перекладывать; если оно уже в ready очереди, его пропустит handleMessage
+	if err := ns.schedule.Unschedule(ctx, messageID); err != nil {
+		return fmt.Errorf("ошибка при удалении из расписания: %w", err)
+	}
 
 	return nil
 }
@@ -130,44 +244,8 @@ func (ns *NotificationService) StartWorker(ctx context.Context) error {
 	})
 }
 
-// StartDelayedWorker — запускает воркер для переноса сообщений из delayed в ready очередь
-func (ns *NotificationService) StartDelayedWorker(ctx context.Context) error {
-	ticker := time.NewTicker(5 * time.Second) // проверяем каждые 5 секунд
-	defer ticker.Stop()
-
-	log.Println("Delayed worker запущен")
-
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("Delayed worker остановлен")
-			return ctx.Err()
-		case <-ticker.C:
-			// Пытаемся получить сообщение из delayed очереди
-			msg, err := ns.queue.ConsumeSingleMessage(ctx, ns.queueNameDelayed)
-			if err != nil {
-				continue // если ошибка или очередь пуста - пропускаем
-			}
-
-			// Проверяем, наступило ли время отправки
-			if time.Now().After(msg.SendAt) || time.Now().Equal(msg.SendAt) {
-				// Время пришло - переносим в ready очередь
-				if err := ns.queue.Publish(ctx, ns.queueNameReady, msg); err != nil {
-					log.Printf("Ошибка при переносе сообщения в ready очередь: %v", err)
-					continue
-				}
-				log.Printf("Сообщение %s перенесено из delayed в ready очередь", msg.ID)
-			} else {
-				// Время еще не пришло - возвращаем сообщение обратно в delayed очередь
-				if err := ns.queue.Publish(ctx, ns.queueNameDelayed, msg); err != nil {
-					log.Printf("Ошибка при возврате сообщения в delayed очередь: %v", err)
-				}
-			}
-		}
-	}
-}
-
-// handleMessage — основная логика обработки сообщения
+// handleMessage — основная логика обработки сообщения. Каждая попытка и её исход попадают
+// в историю статусов; сообщение, исчерпавшее maxRetries, уходит в DLQ
 func (ns *NotificationService) handleMessage(ctx context.Context, msg *models.RabbitMQMessage) error {
 	redisMsg, err := ns.cache.Get(ctx, msg.ID)
 	if err != nil {
@@ -175,51 +253,40 @@ func (ns *NotificationService) handleMessage(ctx context.Context, msg *models.Ra
 	}
 
 	// если сообщение было отменено — просто игнорируем
-	if redisMsg.Status == "canceled" {
+	if redisMsg.Status == models.StatusCanceled {
 		return nil
 	}
 
+	attempt := msg.RetryCount + 1
+	_ = ns.recordStatus(ctx, msg.ID, models.StatusEvent{Status: models.StatusSending, Attempt: attempt})
+
 	// пробуем отправить
 	if err := ns.notifier.Send(ctx, msg); err != nil {
 		// достигнут лимит попыток
 		if msg.RetryCount >= ns.maxRetries {
-			failMsg := &models.RedisMessage{
-				ID:        msg.ID,
-				Status:    "failed",
-				UpdatedAt: time.Now(),
-			}
-			_ = ns.cache.Set(ctx, msg.ID, failMsg)
-			return nil
+			return ns.deadLetter(ctx, msg, err)
 		}
 
-		// экспоненциальная задержка
+		// экспоненциальная задержка: повтор ставится в расписание, а не ждёт в воркере
 		delaySeconds := int(math.Pow(2, float64(msg.RetryCount))) * ns.retryBaseSeconds
 		msg.RetryCount++
 
-		if err := ns.queue.RetryMessage(ctx, ns.queueNameReady, msg, delaySeconds); err != nil {
-			failMsg := &models.RedisMessage{
-				ID:        msg.ID,
-				Status:    "failed",
-				UpdatedAt: time.Now(),
-			}
-			_ = ns.cache.Set(ctx, msg.ID, failMsg)
-			return fmt.Errorf("ошибка при публикации retry: %w", err)
+		if scheduleErr := ns.schedule.Schedule(ctx, msg, time.Now().Add(time.Duration(delaySeconds)*time.Second)); scheduleErr != nil {
+			// повтор запланировать не удалось — сообщение можно будет переотправить вручную из DLQ
+			msg.RetryCount--
+			return ns.deadLetter(ctx, msg, fmt.Errorf("%v; повтор не запланирован: %w", err, scheduleErr))
 		}
 
-		redisMsg.Status = "scheduled"
-		redisMsg.UpdatedAt = time.Now()
-		_ = ns.cache.Set(ctx, msg.ID, redisMsg)
+		ns.wake()
+		_ = ns.recordStatus(ctx, msg.ID, models.StatusEvent{Status: models.StatusRetry, Attempt: msg.RetryCount, Error: err.Error()})
 
-		return fmt.Errorf("ошибка при отправке уведомления: %w", err)
+		// сообщение обработано: повтор уже в расписании, возвращать его в очередь не нужно
+		log.Printf("Ошибка при отправке уведомления %s, повтор через %d с: %v", msg.ID, delaySeconds, err)
+		return nil
 	}
 
 	// успешно отправлено
-	done := &models.RedisMessage{
-		ID:        msg.ID,
-		Status:    "sent",
-		UpdatedAt: time.Now(),
-	}
-	if err := ns.cache.Set(ctx, msg.ID, done); err != nil {
+	if err := ns.recordStatus(ctx, msg.ID, models.StatusEvent{Status: models.StatusSent, Attempt: attempt}); err != nil {
 		return fmt.Errorf("ошибка при обновлении статуса после отправки: %w", err)
 	}
 
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"regexp"
+	"slices"
+	"sort"
+	texttemplate "text/template"
+
+	"L3.1/internal/models"
+)
+
+// DefaultLocale язык, на который откатывается шаблон без версии на запрошенном языке
+const DefaultLocale = "ru"
+
+// SupportedLocales языки шаблонов
+var SupportedLocales = []string{"ru", "en"}
+
+// templateNamePattern имя шаблона попадает в ключ Redis и в URL
+var templateNamePattern = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+
+// SaveTemplate — проверяет и сохраняет шаблон. Переменные подставляются как {{.var}},
+// отсутствующая переменная при отправке считается ошибкой
+func (ns *NotificationService) SaveTemplate(ctx context.Context, template *models.Template) error {
+	if !templateNamePattern.MatchString(template.Name) {
+		return fmt.Errorf("%w: имя шаблона должно состоять из a-z, 0-9, _ и -", ErrValidation)
+	}
+	if template.Locale == "" {
+		template.Locale = DefaultLocale
+	}
+	if !slices.Contains(SupportedLocales, template.Locale) {
+		return fmt.Errorf("%w: язык %q не поддерживается, доступны %v", ErrValidation, template.Locale, SupportedLocales)
+	}
+	if template.Text == "" {
+		return fmt.Errorf("%w: у шаблона должен быть текст", ErrValidation)
+	}
+	// проверяем синтаксис сразу, чтобы ошибка в шаблоне не всплыла только при отправке
+	if _, err := parseTemplate(template); err != nil {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	if err := ns.templateStore.SaveTemplate(ctx, template); err != nil {
+		return fmt.Errorf("ошибка при сохранении шаблона: %w", err)
+	}
+	return nil
+}
+
+// Templates — возвращает все языковые версии шаблона, отсортированные по языку
+func (ns *NotificationService) Templates(ctx context.Context, name string) ([]*models.Template, error) {
+	byLocale, err := ns.templateStore.Templates(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении шаблона: %w", err)
+	}
+	if len(byLocale) == 0 {
+		return nil, ErrTemplateNotFound
+	}
+	templates := make([]*models.Template, 0, len(byLocale))
+	for _, template := range byLocale {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Locale < templates[j].Locale })
+	return templates, nil
+}
+
+// DeleteTemplate — удаляет версию шаблона на языке locale, при пустом locale — шаблон целиком
+func (ns *NotificationService) DeleteTemplate(ctx context.Context, name, locale string) error {
+	removed, err := ns.templateStore.DeleteTemplate(ctx, name, locale)
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении шаблона: %w", err)
+	}
+	if !removed {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// RenderTemplate — подставляет переменные в шаблон на языке locale. Если версии на этом языке нет,
+// используется DefaultLocale; язык результата возвращается в RenderedTemplate.Locale
+func (ns *NotificationService) RenderTemplate(ctx context.Context, name, locale string, vars map[string]any) (*models.RenderedTemplate, error) {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	if !slices.Contains(SupportedLocales, locale) {
+		return nil, fmt.Errorf("%w: язык %q не поддерживается, доступны %v", ErrValidation, locale, SupportedLocales)
+	}
+
+	byLocale, err := ns.templateStore.Templates(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении шаблона: %w", err)
+	}
+	template, ok := byLocale[locale]
+	if !ok {
+		template, ok = byLocale[DefaultLocale]
+	}
+	if !ok {
+		return nil, ErrTemplateNotFound
+	}
+
+	parsed, err := parseTemplate(template)
+	if err != nil {
+		return nil, fmt.Errorf("шаблон %s/%s повреждён: %w", template.Name, template.Locale, err)
+	}
+	rendered, err := parsed.execute(vars)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	rendered.Locale = template.Locale
+	return rendered, nil
+}
+
+// parsedTemplate разобранный шаблон: тема и текст как text/template, HTML как html/template,
+// чтобы значения переменных экранировались
+type parsedTemplate struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+func parseTemplate(template *models.Template) (*parsedTemplate, error) {
+	var (
+		parsed parsedTemplate
+		err    error
+	)
+	if parsed.subject, err = texttemplate.New("subject").Option("missingkey=error").Parse(template.Subject); err != nil {
+		return nil, fmt.Errorf("тема: %w", err)
+	}
+	if parsed.text, err = texttemplate.New("text").Option("missingkey=error").Parse(template.Text); err != nil {
+		return nil, fmt.Errorf("текст: %w", err)
+	}
+	if template.HTML != "" {
+		if parsed.html, err = htmltemplate.New("html").Option("missingkey=error").Parse(template.HTML); err != nil {
+			return nil, fmt.Errorf("html: %w", err)
+		}
+	}
+	return &parsed, nil
+}
+
+func (pt *parsedTemplate) execute(vars map[string]any) (*models.RenderedTemplate, error) {
+	if vars == nil {
+		vars = map[string]any{}
+	}
+	var subject, text, html bytes.Buffer
+	if err := pt.subject.Execute(&subject, vars); err != nil {
+		return nil, fmt.Errorf("тема: %w", err)
+	}
+	if err := pt.text.Execute(&text, vars); err != nil {
+		return nil, fmt.Errorf("текст: %w", err)
+	}
+	if pt.html != nil {
+		if err := pt.html.Execute(&html, vars); err != nil {
+			return nil, fmt.Errorf("html: %w", err)
+		}
+	}
+	return &models.RenderedTemplate{Subject: subject.String(), Text: text.String(), HTML: html.String()}, nil
+}
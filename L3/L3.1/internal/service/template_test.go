@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"L3.1/internal/models"
+)
+
+func saveTestTemplates(t *testing.T, ns *NotificationService) {
+	t.Helper()
+	for _, template := range []*models.Template{
+		{Name: "order-ready", Locale: "ru", Subject: "Заказ №{{.order}} готов", Text: "{{.name}}, заказ ждёт вас", HTML: "<p>{{.name}}, заказ ждёт вас</p>"},
+		{Name: "order-ready", Locale: "en", Subject: "Order #{{.order}} is ready", Text: "{{.name}}, your order is waiting"},
+	} {
+		if err := ns.SaveTemplate(context.Background(), template); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	ns, _, _ := newTestService(t, &stubNotifier{})
+	saveTestTemplates(t, ns)
+	ctx := context.Background()
+	vars := map[string]any{"order": 15, "name": "<Иван>"}
+
+	tests := []struct {
+		locale      string
+		wantLocale  string
+		wantSubject string
+		wantHTML    string
+	}{
+		{"", "ru", "Заказ №15 готов", "<p>&lt;Иван&gt;, заказ ждёт вас</p>"},
+		{"en", "en", "Order #15 is ready", ""},
+	}
+	for _, tt := range tests {
+		rendered, err := ns.RenderTemplate(ctx, "order-ready", tt.locale, vars)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.locale, err)
+		}
+		if rendered.Locale != tt.wantLocale || rendered.Subject != tt.wantSubject || rendered.HTML != tt.wantHTML {
+			t.Errorf("%q: получено %+v", tt.locale, rendered)
+		}
+	}
+
+	// без английской версии шаблон откатывается на русскую
+	if err := ns.DeleteTemplate(ctx, "order-ready", "en"); err != nil {
+		t.Fatal(err)
+	}
+	rendered, err := ns.RenderTemplate(ctx, "order-ready", "en", vars)
+	if err != nil || rendered.Locale != "ru" {
+		t.Errorf("ожидался откат на ru: %+v, %v", rendered, err)
+	}
+
+	if _, err := ns.RenderTemplate(ctx, "order-ready", "ru", map[string]any{"order": 15}); !errors.Is(err, ErrValidation) {
+		t.Errorf("отсутствующая переменная должна быть ошибкой валидации, получено %v", err)
+	}
+	if _, err := ns.RenderTemplate(ctx, "unknown", "ru", nil); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("ожидалась ErrTemplateNotFound, получено %v", err)
+	}
+	if _, err := ns.RenderTemplate(ctx, "order-ready", "de", vars); !errors.Is(err, ErrValidation) {
+		t.Errorf("неподдерживаемый язык должен быть ошибкой валидации, получено %v", err)
+	}
+}
+
+func TestSaveTemplate_Validation(t *testing.T) {
+	ns, _, _ := newTestService(t, &stubNotifier{})
+	for _, template := range []*models.Template{
+		{Name: "Bad Name", Text: "текст"},
+		{Name: "ok", Locale: "de", Text: "текст"},
+		{Name: "ok", Text: ""},
+		{Name: "ok", Text: "{{.name"},
+	} {
+		if err := ns.SaveTemplate(context.Background(), template); !errors.Is(err, ErrValidation) {
+			t.Errorf("%+v: ожидалась ошибка валидации, получено %v", template, err)
+		}
+	}
+}
+
+func TestCreateNotification_FromTemplate(t *testing.T) {
+	ns, _, schedule := newTestService(t, &stubNotifier{})
+	saveTestTemplates(t, ns)
+	ctx := context.Background()
+
+	result, err := ns.CreateNotification(ctx, models.CreateNotificationRequest{
+		To:       "user@example.com",
+		Template: "order-ready",
+		Vars:     map[string]any{"order": 7, "name": "Иван"},
+		SendAt:   time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, msg, _ := schedule.at(result.ID)
+	if msg.Subject != "Заказ №7 готов" || msg.Body != "Иван, заказ ждёт вас" || msg.HTMLBody != "<p>Иван, заказ ждёт вас</p>" {
+		t.Errorf("сообщение собрано неверно: %+v", msg)
+	}
+
+	_, err = ns.CreateNotification(ctx, models.CreateNotificationRequest{To: "user@example.com", Template: "order-ready", Body: "текст"})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("template вместе с body должен быть ошибкой валидации, получено %v", err)
+	}
+}
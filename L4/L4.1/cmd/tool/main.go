@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"log"
 	"math/rand/v2"
-	"sync"
 	"time"
 
 	orchannel "github.com/MAPiryazev/Wildberries_L1/tree/main/L4/L4.1/utils/or-channel"
+	"github.com/MAPiryazev/Wildberries_L1/tree/main/L4/L4.1/utils/pool"
 )
 
 func chanArrayGen(n, seconds int) []chan interface{} {
@@ -68,62 +68,41 @@ func inputGen(ctx context.Context) chan int {
 	return out
 }
 
-type workerPool struct {
-	amountOfWorkers int
-	inputChannel    chan int
-	sum             int
-
-	stopChannel <-chan interface{}
-	once        sync.Once
-
-	wg sync.WaitGroup
-	mu sync.RWMutex
-}
-
-func newWorkerPool(amount int, inputCh chan int, stopCh <-chan interface{}) *workerPool {
-	if amount <= 0 {
-		amount = 1
-		log.Println("количество воркеров объявлено как 1")
+// sumUntilStop отправляет числа из input в пул, пока не сработает stopChannel
+// или не закроется input, и возвращает их сумму
+func sumUntilStop(ctx context.Context, wp *pool.Pool[int], input <-chan int, stopChannel <-chan interface{}) (int, error) {
+	var futures []*pool.Future[int]
+loop:
+	for {
+		select {
+		case <-stopChannel:
+			break loop
+		case v, ok := <-input:
+			if !ok {
+				break loop
+			}
+			f, err := wp.Submit(ctx, func(ctx context.Context) (int, error) {
+				return v, nil
+			})
+			if err != nil {
+				return 0, err
+			}
+			futures = append(futures, f)
+		}
 	}
 
-	return &workerPool{
-		amountOfWorkers: amount,
-		inputChannel:    inputCh,
-		stopChannel:     stopCh,
+	if err := wp.Shutdown(ctx); err != nil {
+		return 0, err
 	}
-}
-
-func (wp *workerPool) start() {
-	for i := 0; i < wp.amountOfWorkers; i++ {
-		wp.wg.Add(1)
-		go func() {
-			defer wp.wg.Done()
-
-			for {
-				select {
-				case <-wp.stopChannel:
-					return
-				case v, ok := <-wp.inputChannel:
-					if !ok {
-						return
-					}
-					wp.mu.Lock()
-					wp.sum += v
-					wp.mu.Unlock()
-				}
-			}
-		}()
+	sum := 0
+	for _, f := range futures {
+		v, err := f.Result()
+		if err != nil {
+			return 0, err
+		}
+		sum += v
 	}
-}
-
-func (wp *workerPool) wait() {
-	wp.wg.Wait()
-}
-
-func (wp *workerPool) result() int {
-	wp.mu.RLock()
-	defer wp.mu.RUnlock()
-	return wp.sum
+	return sum, nil
 }
 
 func main() {
@@ -133,8 +112,10 @@ func main() {
 	input := inputGen(ctx)
 	stopChannelsArray := chanArrayGen(10, 5)
 
-	wp := newWorkerPool(10, input, orchannel.OrChannel(stopChannelsArray...))
-	wp.start()
-	wp.wait()
-	fmt.Println(wp.result())
+	wp := pool.New[int](pool.Config{Workers: 10})
+	sum, err := sumUntilStop(context.Background(), wp, input, orchannel.OrChannel(stopChannelsArray...))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(sum)
 }
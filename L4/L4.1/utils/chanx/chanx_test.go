@@ -0,0 +1,299 @@
+package chanx
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// checkLeaks запоминает число горутин и по завершении теста проверяет,
+// что все запущенные горутины завершились
+func checkLeaks(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(time.Second)
+		for {
+			after := runtime.NumGoroutine()
+			if after <= before {
+				return
+			}
+			if time.Now().After(deadline) {
+				buf := make([]byte, 1<<16)
+				n := runtime.Stack(buf, true)
+				t.Fatalf("утечка горутин: было %d, стало %d\n%s", before, after, buf[:n])
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+}
+
+func gen[T any](values ...T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range values {
+			out <- v
+		}
+	}()
+	return out
+}
+
+func collect[T any](t *testing.T, ch <-chan T) []T {
+	t.Helper()
+	var result []T
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return result
+			}
+			result = append(result, v)
+		case <-timeout:
+			t.Fatalf("timeout: канал не был закрыт")
+		}
+	}
+}
+
+func waitClosed(t *testing.T, ch <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatalf("timeout: канал не был закрыт")
+	}
+}
+
+func assertOpen(t *testing.T, ch <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+		t.Fatalf("канал закрылся раньше времени")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestOr_ClosesWhenAnyInputFires(t *testing.T) {
+	checkLeaks(t)
+	chans := make([]chan int, 100)
+	inputs := make([]<-chan int, 100)
+	for i := range chans {
+		chans[i] = make(chan int)
+		inputs[i] = chans[i]
+	}
+	before := runtime.NumGoroutine()
+	out := Or(context.Background(), inputs...)
+	if grown := runtime.NumGoroutine() - before; grown > 1 {
+		t.Errorf("Or должен запускать одну горутину, запущено %d", grown)
+	}
+	assertOpen(t, out)
+
+	chans[57] <- 1
+	waitClosed(t, out)
+	for _, ch := range chans {
+		close(ch)
+	}
+}
+
+func TestOr_ZeroInputsWaitsForContext(t *testing.T) {
+	checkLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := Or[int](ctx)
+	assertOpen(t, out)
+	cancel()
+	waitClosed(t, out)
+}
+
+func TestOr_ContextCancel(t *testing.T) {
+	checkLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+	out := Or(ctx, ch)
+	cancel()
+	waitClosed(t, out)
+}
+
+func TestAnd_WaitsForAllInputs(t *testing.T) {
+	checkLeaks(t)
+	a, b, c := make(chan int), make(chan int), make(chan int)
+	out := And(context.Background(), a, b, c)
+
+	close(a)
+	assertOpen(t, out)
+	b <- 1
+	assertOpen(t, out)
+	close(c)
+	waitClosed(t, out)
+	close(b)
+}
+
+func TestAnd_ContextCancel(t *testing.T) {
+	checkLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := And(ctx, make(chan int))
+	cancel()
+	waitClosed(t, out)
+	if ctx.Err() == nil {
+		t.Fatal("ожидали отменённый контекст")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	checkLeaks(t)
+	got := collect(t, Merge(context.Background(), gen(1, 2, 3), gen(4, 5), nil, gen[int]()))
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("got=%v", got)
+	}
+}
+
+func TestMerge_ContextCancel(t *testing.T) {
+	checkLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := Merge(ctx, in, in)
+	cancel()
+	if got := collect(t, out); len(got) != 0 {
+		t.Errorf("got=%v", got)
+	}
+	close(in)
+}
+
+func TestTee(t *testing.T) {
+	checkLeaks(t)
+	out1, out2 := Tee(context.Background(), gen("a", "b", "c"))
+
+	var wg sync.WaitGroup
+	var got1, got2 []string
+	wg.Add(2)
+	go func() { defer wg.Done(); got1 = collect(t, out1) }()
+	go func() { defer wg.Done(); got2 = collect(t, out2) }()
+	wg.Wait()
+
+	want := []string{"a", "b", "c"}
+	if !slices.Equal(got1, want) || !slices.Equal(got2, want) {
+		t.Errorf("got1=%v got2=%v", got1, got2)
+	}
+}
+
+func TestTee_ContextCancelWithSlowReader(t *testing.T) {
+	checkLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out1, out2 := Tee(ctx, in)
+	go func() { in <- 1 }()
+	if v := <-out1; v != 1 {
+		t.Fatalf("got=%d", v)
+	}
+	cancel()
+	collect(t, out1)
+	collect(t, out2)
+	close(in)
+}
+
+func TestBridge(t *testing.T) {
+	checkLeaks(t)
+	stream := make(chan (<-chan int))
+	go func() {
+		defer close(stream)
+		for i := 0; i < 3; i++ {
+			stream <- gen(i*10, i*10+1)
+		}
+	}()
+	got := collect(t, Bridge(context.Background(), stream))
+	if !slices.Equal(got, []int{0, 1, 10, 11, 20, 21}) {
+		t.Errorf("got=%v", got)
+	}
+}
+
+func TestBridge_ContextCancel(t *testing.T) {
+	checkLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := make(chan (<-chan int))
+	out := Bridge(ctx, stream)
+	inner := make(chan int)
+	stream <- inner
+	cancel()
+	collect(t, out)
+	close(inner)
+	close(stream)
+}
+
+func TestTake(t *testing.T) {
+	checkLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	infinite := make(chan int)
+	go func() {
+		defer close(infinite)
+		for i := 0; ; i++ {
+			select {
+			case <-ctx.Done():
+				return
+			case infinite <- i:
+			}
+		}
+	}()
+	got := collect(t, Take(ctx, infinite, 4))
+	if !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Errorf("got=%v", got)
+	}
+}
+
+func TestTake_ShortInput(t *testing.T) {
+	checkLeaks(t)
+	got := collect(t, Take(context.Background(), gen(1, 2), 5))
+	if !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("got=%v", got)
+	}
+}
+
+func TestOrDone(t *testing.T) {
+	checkLeaks(t)
+	got := collect(t, OrDone(context.Background(), gen(1, 2, 3)))
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("got=%v", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	never := make(chan int)
+	out := OrDone(ctx, never)
+	cancel()
+	collect(t, out)
+}
+
+func TestFanOut(t *testing.T) {
+	checkLeaks(t)
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = i
+	}
+	outs := FanOut(context.Background(), gen(values...), 4)
+	if len(outs) != 4 {
+		t.Fatalf("ожидали 4 выхода, получили %d", len(outs))
+	}
+
+	got := collect(t, Merge(context.Background(), outs...))
+	slices.Sort(got)
+	if !slices.Equal(got, values) {
+		t.Errorf("каждое значение должно попасть ровно в один выход, got=%v", got)
+	}
+}
+
+func TestFanOut_ContextCancel(t *testing.T) {
+	checkLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	outs := FanOut(ctx, in, 0)
+	if len(outs) != 1 {
+		t.Fatalf("при n <= 0 ожидали 1 выход, получили %d", len(outs))
+	}
+	cancel()
+	collect(t, outs[0])
+	close(in)
+}
@@ -0,0 +1,62 @@
+package chanx
+
+import (
+	"context"
+	"reflect"
+)
+
+// Or возвращает канал, который закрывается, как только любой из входных каналов
+// отдаст значение или будет закрыт, либо будет отменён ctx.
+// В отличие от рекурсивного orchannel.OrChannel использует одну горутину на весь вызов
+// через reflect.Select. Без входных каналов результат закроется только по ctx
+func Or[T any](ctx context.Context, channels ...<-chan T) <-chan struct{} {
+	out := make(chan struct{})
+
+	cases := make([]reflect.SelectCase, 0, len(channels)+1)
+	cases = append(cases, recvCase(ctx.Done()))
+	for _, ch := range channels {
+		if ch == nil {
+			continue
+		}
+		cases = append(cases, recvCase(ch))
+	}
+
+	go func() {
+		defer close(out)
+		reflect.Select(cases)
+	}()
+	return out
+}
+
+// And возвращает канал, который закрывается, когда каждый из входных каналов
+// отдал значение или был закрыт, либо когда отменён ctx.
+// Чтобы отличить эти случаи, после закрытия проверьте ctx.Err()
+func And[T any](ctx context.Context, channels ...<-chan T) <-chan struct{} {
+	out := make(chan struct{})
+
+	cases := make([]reflect.SelectCase, 0, len(channels)+1)
+	cases = append(cases, recvCase(ctx.Done()))
+	for _, ch := range channels {
+		if ch == nil {
+			continue
+		}
+		cases = append(cases, recvCase(ch))
+	}
+
+	go func() {
+		defer close(out)
+		for len(cases) > 1 {
+			chosen, _, _ := reflect.Select(cases)
+			if chosen == 0 {
+				return
+			}
+			cases[chosen] = cases[len(cases)-1]
+			cases = cases[:len(cases)-1]
+		}
+	}()
+	return out
+}
+
+func recvCase[C any](ch <-chan C) reflect.SelectCase {
+	return reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)}
+}
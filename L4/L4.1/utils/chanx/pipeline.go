@@ -0,0 +1,155 @@
+package chanx
+
+import (
+	"context"
+	"sync"
+)
+
+// OrDone пересылает значения из in, пока in не закрыт и не отменён ctx.
+// Позволяет читать из чужого канала через range, не забывая про отмену
+func OrDone[T any](ctx context.Context, in <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		forward(ctx, in, out)
+	}()
+	return out
+}
+
+// Take пересылает не больше n первых значений из in
+func Take[T any](ctx context.Context, in <-chan T, n int) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for i := 0; i < n; i++ {
+			v, ok := receive(ctx, in)
+			if !ok || !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Merge объединяет значения из всех входных каналов в один (fan-in).
+// Результат закрывается, когда закрыты все входы или отменён ctx
+func Merge[T any](ctx context.Context, channels ...<-chan T) <-chan T {
+	out := make(chan T)
+
+	var wg sync.WaitGroup
+	for _, ch := range channels {
+		if ch == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(ch <-chan T) {
+			defer wg.Done()
+			forward(ctx, ch, out)
+		}(ch)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Tee дублирует каждое значение из in в оба выходных канала.
+// Следующее значение читается только после того, как предыдущее получили оба читателя
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	out1 := make(chan T)
+	out2 := make(chan T)
+	go func() {
+		defer close(out1)
+		defer close(out2)
+		for {
+			v, ok := receive(ctx, in)
+			if !ok {
+				return
+			}
+			o1, o2 := out1, out2
+			for i := 0; i < 2; i++ {
+				select {
+				case <-ctx.Done():
+					return
+				case o1 <- v:
+					o1 = nil
+				case o2 <- v:
+					o2 = nil
+				}
+			}
+		}
+	}()
+	return out1, out2
+}
+
+// Bridge последовательно вычитывает каналы из потока каналов и сводит их в один
+func Bridge[T any](ctx context.Context, chanStream <-chan (<-chan T)) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			ch, ok := receive(ctx, chanStream)
+			if !ok {
+				return
+			}
+			if ch == nil {
+				continue
+			}
+			forward(ctx, ch, out)
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FanOut раздаёт значения из in по n каналам: каждое значение получает
+// ровно один выход, тот, чей читатель освободился первым. При n <= 0 используется один выход
+func FanOut[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	if n <= 0 {
+		n = 1
+	}
+	outs := make([]<-chan T, n)
+	for i := range outs {
+		out := make(chan T)
+		outs[i] = out
+		go func() {
+			defer close(out)
+			forward(ctx, in, out)
+		}()
+	}
+	return outs
+}
+
+// forward пересылает значения из in в out, пока in не закрыт и не отменён ctx
+func forward[T any](ctx context.Context, in <-chan T, out chan<- T) {
+	for {
+		v, ok := receive(ctx, in)
+		if !ok || !send(ctx, out, v) {
+			return
+		}
+	}
+}
+
+// receive читает значение из in; ok == false, если in закрыт или ctx отменён
+func receive[T any](ctx context.Context, in <-chan T) (v T, ok bool) {
+	select {
+	case <-ctx.Done():
+		return v, false
+	case v, ok = <-in:
+		return v, ok
+	}
+}
+
+// send отправляет v в out и возвращает false, если ctx отменили раньше
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case out <- v:
+		return true
+	}
+}
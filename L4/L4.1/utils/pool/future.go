@@ -0,0 +1,67 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// Future результат задачи, который станет доступен после её выполнения
+type Future[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+func (f *Future[T]) resolve(value T, err error) {
+	f.value = value
+	f.err = err
+	close(f.done)
+}
+
+// Done возвращает канал, который закрывается после выполнения задачи
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result ждёт выполнения задачи и возвращает её результат
+func (f *Future[T]) Result() (T, error) {
+	<-f.done
+	return f.value, f.err
+}
+
+// Wait как Result, но перестаёт ждать при отмене ctx
+func (f *Future[T]) Wait(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// PanicError возвращается задачей, которая запаниковала
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("паника в задаче: %v", e.Value)
+}
+
+func safeRun[T any](ctx context.Context, task Task[T]) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			value = zero
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return task(ctx)
+}
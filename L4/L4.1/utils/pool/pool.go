@@ -0,0 +1,226 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrClosed возвращается при отправке задачи в остановленный пул
+var ErrClosed = errors.New("пул остановлен")
+
+// Task задача пула. ctx отменяется, если отменён контекст, переданный в Submit,
+// или пул остановлен принудительно
+type Task[T any] func(ctx context.Context) (T, error)
+
+// DefaultMaxErrors сколько ошибок задач пул хранит по умолчанию
+const DefaultMaxErrors = 100
+
+// Config настройки пула
+type Config struct {
+	Workers   int // количество воркеров, минимум 1
+	QueueSize int // размер очереди задач, 0 - Submit ждёт свободного воркера
+	MaxErrors int // сколько первых ошибок хранить для Err, 0 - DefaultMaxErrors
+}
+
+type job[T any] struct {
+	ctx    context.Context
+	task   Task[T]
+	future *Future[T]
+}
+
+// Pool пул воркеров с ограниченным количеством горутин и типизированными результатами
+type Pool[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	tasks   chan job[T]
+	closing chan struct{}
+
+	mu        sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+	stops     []chan struct{} // по одному каналу остановки на воркер, используется в Resize
+
+	wg sync.WaitGroup
+
+	errMu   sync.Mutex
+	errs    []error
+	maxErrs int
+	dropped int // ошибки сверх maxErrs, от них остаётся только счётчик
+}
+
+// New создаёт пул и запускает воркеры
+func New[T any](cfg Config) *Pool[T] {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize < 0 {
+		cfg.QueueSize = 0
+	}
+	if cfg.MaxErrors <= 0 {
+		cfg.MaxErrors = DefaultMaxErrors
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool[T]{
+		ctx:     ctx,
+		cancel:  cancel,
+		tasks:   make(chan job[T], cfg.QueueSize),
+		closing: make(chan struct{}),
+		maxErrs: cfg.MaxErrors,
+	}
+	p.mu.Lock()
+	for i := 0; i < cfg.Workers; i++ {
+		p.startWorkerLocked()
+	}
+	p.mu.Unlock()
+	return p
+}
+
+// Submit ставит задачу в очередь. Если очередь заполнена, ждёт места,
+// отмены ctx или остановки пула
+func (p *Pool[T]) Submit(ctx context.Context, task Task[T]) (*Future[T], error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return nil, ErrClosed
+	}
+
+	j := job[T]{ctx: ctx, task: task, future: newFuture[T]()}
+	select {
+	case p.tasks <- j:
+		return j.future, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.closing:
+		return nil, ErrClosed
+	}
+}
+
+// Size возвращает текущее количество воркеров
+func (p *Pool[T]) Size() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.stops)
+}
+
+// Resize меняет количество воркеров. Лишние воркеры завершаются после текущей задачи
+func (p *Pool[T]) Resize(n int) {
+	if n <= 0 {
+		n = 1
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	for len(p.stops) < n {
+		p.startWorkerLocked()
+	}
+	for len(p.stops) > n {
+		last := len(p.stops) - 1
+		close(p.stops[last])
+		p.stops = p.stops[:last]
+	}
+}
+
+// Err возвращает первые Config.MaxErrors ошибок задач, объединённые через errors.Join.
+// Если ошибок было больше, в конец добавляется сообщение с количеством отброшенных
+func (p *Pool[T]) Err() error {
+	p.errMu.Lock()
+	defer p.errMu.Unlock()
+	if p.dropped == 0 {
+		return errors.Join(p.errs...)
+	}
+	errs := append(p.errs[:len(p.errs):len(p.errs)], fmt.Errorf("и ещё %d ошибок", p.dropped))
+	return errors.Join(errs...)
+}
+
+// Shutdown перестаёт принимать задачи и ждёт выполнения уже поставленных.
+// Если ctx истекает раньше, выполняющиеся задачи отменяются, оставшиеся в очереди
+// завершаются с ErrClosed, а Shutdown дожидается воркеров и возвращает ошибку ctx
+func (p *Pool[T]) Shutdown(ctx context.Context) error {
+	p.closeOnce.Do(func() {
+		// сначала будим заблокированные Submit, затем ждём, пока они отпустят RLock
+		close(p.closing)
+		p.mu.Lock()
+		p.closed = true
+		close(p.tasks)
+		p.mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (p *Pool[T]) startWorkerLocked() {
+	stop := make(chan struct{})
+	p.stops = append(p.stops, stop)
+	p.wg.Add(1)
+	go p.worker(stop)
+}
+
+func (p *Pool[T]) worker(stop <-chan struct{}) {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-stop:
+			return
+		case j, ok := <-p.tasks:
+			if !ok {
+				return
+			}
+			p.run(j)
+		}
+	}
+}
+
+func (p *Pool[T]) run(j job[T]) {
+	if p.ctx.Err() != nil {
+		var zero T
+		j.future.resolve(zero, ErrClosed)
+		return
+	}
+	if err := j.ctx.Err(); err != nil {
+		var zero T
+		p.recordErr(err)
+		j.future.resolve(zero, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(j.ctx)
+	stop := context.AfterFunc(p.ctx, cancel)
+	value, err := safeRun(ctx, j.task)
+	stop()
+	cancel()
+
+	if err != nil {
+		p.recordErr(err)
+	}
+	j.future.resolve(value, err)
+}
+
+func (p *Pool[T]) recordErr(err error) {
+	p.errMu.Lock()
+	if len(p.errs) < p.maxErrs {
+		p.errs = append(p.errs, err)
+	} else {
+		p.dropped++
+	}
+	p.errMu.Unlock()
+}
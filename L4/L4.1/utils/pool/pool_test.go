@@ -0,0 +1,274 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_Results(t *testing.T) {
+	p := New[int](Config{Workers: 4})
+
+	futures := make([]*Future[int], 0, 20)
+	for i := 0; i < 20; i++ {
+		f, err := p.Submit(context.Background(), func(ctx context.Context) (int, error) {
+			return i * i, nil
+		})
+		if err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+		futures = append(futures, f)
+	}
+
+	for i, f := range futures {
+		v, err := f.Result()
+		if err != nil || v != i*i {
+			t.Errorf("задача %d: got=%d err=%v", i, v, err)
+		}
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPool_ErrorAggregation(t *testing.T) {
+	p := New[int](Config{Workers: 2})
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	for _, e := range []error{errA, nil, errB} {
+		_, err := p.Submit(context.Background(), func(ctx context.Context) (int, error) {
+			return 0, e
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	err := p.Err()
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("ожидали обе ошибки, получили %v", err)
+	}
+}
+
+func TestPool_ErrorLimit(t *testing.T) {
+	p := New[int](Config{Workers: 2, MaxErrors: 3})
+	for i := 0; i < 10; i++ {
+		_, err := p.Submit(context.Background(), func(ctx context.Context) (int, error) {
+			return 0, fmt.Errorf("ошибка %d", i)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	joined, ok := p.Err().(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("ожидали errors.Join, получили %v", p.Err())
+	}
+	errs := joined.Unwrap()
+	if len(errs) != 4 {
+		t.Fatalf("ожидали 3 ошибки и счётчик, получили %d: %v", len(errs), errs)
+	}
+	if got := errs[3].Error(); got != "и ещё 7 ошибок" {
+		t.Errorf("счётчик отброшенных: %q", got)
+	}
+}
+
+func TestPool_PanicRecovery(t *testing.T) {
+	p := New[string](Config{Workers: 1})
+	defer p.Shutdown(context.Background())
+
+	f, err := p.Submit(context.Background(), func(ctx context.Context) (string, error) {
+		panic("boom")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.Result()
+	var pe *PanicError
+	if !errors.As(err, &pe) || pe.Value != "boom" || len(pe.Stack) == 0 {
+		t.Fatalf("ожидали PanicError, получили %v", err)
+	}
+
+	// воркер должен пережить панику
+	f, err = p.Submit(context.Background(), func(ctx context.Context) (string, error) {
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := f.Result(); v != "ok" || err != nil {
+		t.Errorf("got=%q err=%v", v, err)
+	}
+}
+
+func TestPool_TaskContextCancel(t *testing.T) {
+	p := New[int](Config{Workers: 1})
+	defer p.Shutdown(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	f, err := p.Submit(ctx, func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	cancel()
+	if _, err := f.Result(); !errors.Is(err, context.Canceled) {
+		t.Errorf("ожидали context.Canceled, получили %v", err)
+	}
+}
+
+func TestPool_DrainOnShutdown(t *testing.T) {
+	p := New[int](Config{Workers: 2, QueueSize: 10})
+
+	var done atomic.Int32
+	for i := 0; i < 10; i++ {
+		_, err := p.Submit(context.Background(), func(ctx context.Context) (int, error) {
+			time.Sleep(5 * time.Millisecond)
+			done.Add(1)
+			return 0, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if done.Load() != 10 {
+		t.Errorf("Shutdown должен дождаться всех задач из очереди, выполнено %d", done.Load())
+	}
+
+	if _, err := p.Submit(context.Background(), func(ctx context.Context) (int, error) { return 0, nil }); !errors.Is(err, ErrClosed) {
+		t.Errorf("ожидали ErrClosed, получили %v", err)
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Errorf("повторный Shutdown: %v", err)
+	}
+}
+
+func TestPool_ShutdownTimeoutCancelsTasks(t *testing.T) {
+	p := New[int](Config{Workers: 1, QueueSize: 1})
+
+	started := make(chan struct{})
+	running, err := p.Submit(context.Background(), func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	queued, err := p.Submit(context.Background(), func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ожидали DeadlineExceeded, получили %v", err)
+	}
+	if _, err := running.Result(); !errors.Is(err, context.Canceled) {
+		t.Errorf("выполняющаяся задача: %v", err)
+	}
+	if _, err := queued.Result(); !errors.Is(err, ErrClosed) {
+		t.Errorf("задача из очереди: %v", err)
+	}
+}
+
+func TestPool_SubmitUnblockedByShutdown(t *testing.T) {
+	p := New[int](Config{Workers: 1})
+	release := make(chan struct{})
+	_, err := p.Submit(context.Background(), func(ctx context.Context) (int, error) {
+		<-release
+		return 0, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := p.Submit(context.Background(), func(ctx context.Context) (int, error) { return 0, nil })
+		errCh <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	shutdownDone := make(chan error, 1)
+	go func() { shutdownDone <- p.Shutdown(context.Background()) }()
+
+	if err := <-errCh; !errors.Is(err, ErrClosed) {
+		t.Errorf("ожидали ErrClosed, получили %v", err)
+	}
+	close(release)
+	if err := <-shutdownDone; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPool_Resize(t *testing.T) {
+	p := New[int](Config{Workers: 1})
+	defer p.Shutdown(context.Background())
+
+	p.Resize(4)
+	if p.Size() != 4 {
+		t.Fatalf("ожидали 4 воркера, получили %d", p.Size())
+	}
+
+	// все 4 воркера должны работать одновременно
+	var wg sync.WaitGroup
+	wg.Add(4)
+	release := make(chan struct{})
+	futures := make([]*Future[int], 4)
+	for i := range futures {
+		f, err := p.Submit(context.Background(), func(ctx context.Context) (int, error) {
+			wg.Done()
+			<-release
+			return 0, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		futures[i] = f
+	}
+	waitCh := make(chan struct{})
+	go func() { wg.Wait(); close(waitCh) }()
+	select {
+	case <-waitCh:
+	case <-time.After(time.Second):
+		t.Fatal("задачи не выполняются параллельно после Resize")
+	}
+	close(release)
+	for _, f := range futures {
+		_, _ = f.Result()
+	}
+
+	p.Resize(2)
+	if p.Size() != 2 {
+		t.Errorf("ожидали 2 воркера, получили %d", p.Size())
+	}
+	p.Resize(0)
+	if p.Size() != 1 {
+		t.Errorf("количество воркеров не может быть меньше 1, получили %d", p.Size())
+	}
+}